@@ -0,0 +1,640 @@
+// Command admin runs operator tasks against the database through the same
+// services the API uses, so every rule the API enforces applies here too.
+//
+//	admin bootstrap        -user-id ID -name NAME -email EMAIL [-password-stdin] [-workspace-name NAME]
+//	admin users list       [-status STATUS]
+//	admin users create     -user-id ID -name NAME -email EMAIL [-role ROLE] [-password-stdin]
+//	admin users deactivate -user USER [-successor USER]
+//	admin users set-role   -user USER -role ROLE
+//	admin users set-password -user USER [-password-stdin]
+//	admin projects transfer -project ID -to USER
+//	admin indexes
+//	admin stats
+//
+// Every command also takes -uri, -db, -workspace SLUG and -json. USER is an
+// ObjectID or a user_id.
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/migrations"
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/repositories"
+	"Concurrent_Task_Management_System/internal/services"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultURI      = "mongodb://localhost:27017"
+	defaultDatabase = "trello_lite"
+)
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "bootstrap":
+		bootstrap(os.Args[2:])
+	case "users":
+		if len(os.Args) < 3 {
+			usage()
+		}
+		switch os.Args[2] {
+		case "list":
+			listUsers(os.Args[3:])
+		case "create":
+			createUser(os.Args[3:])
+		case "deactivate":
+			deactivateUser(os.Args[3:])
+		case "set-role":
+			setRole(os.Args[3:])
+		case "set-password":
+			setPassword(os.Args[3:])
+		default:
+			usage()
+		}
+	case "projects":
+		if len(os.Args) < 3 || os.Args[2] != "transfer" {
+			usage()
+		}
+		transferProject(os.Args[3:])
+	case "indexes":
+		indexes(os.Args[2:])
+	case "stats":
+		stats(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage:
+  admin bootstrap         -user-id ID -name NAME -email EMAIL [-password-stdin] [-workspace-name NAME]
+  admin users list        [-status active|suspended|deactivated]
+  admin users create      -user-id ID -name NAME -email EMAIL [-role ROLE] [-password-stdin]
+  admin users deactivate  -user USER [-successor USER]
+  admin users set-role    -user USER -role employee|admin|super_admin
+  admin users set-password -user USER [-password-stdin]
+  admin projects transfer -project ID -to USER
+  admin indexes
+  admin stats
+
+common flags: -uri URI -db NAME -workspace SLUG -json`)
+	os.Exit(2)
+}
+
+// =====================
+// ENVIRONMENT
+// =====================
+
+// commonFlags holds the flags every command shares.
+type commonFlags struct {
+	uri       string
+	db        string
+	workspace string
+	json      bool
+}
+
+func newFlagSet(name string) (*flag.FlagSet, *commonFlags) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	c := &commonFlags{}
+	fs.StringVar(&c.uri, "uri", defaultURI, "MongoDB connection string")
+	fs.StringVar(&c.db, "db", defaultDatabase, "database name")
+	fs.StringVar(&c.workspace, "workspace", models.DefaultWorkspaceSlug, "workspace slug")
+	fs.BoolVar(&c.json, "json", false, "print JSON instead of text")
+	return fs, c
+}
+
+// env is the wired service layer, the same way cmd/server builds it.
+type env struct {
+	client     *mongo.Client
+	db         *mongo.Database
+	users      *services.UserService
+	projects   *services.ProjectService
+	workspaces *services.WorkspaceService
+	json       bool
+}
+
+func open(ctx context.Context, c *commonFlags) *env {
+	connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(connectCtx, options.Client().ApplyURI(c.uri))
+	if err != nil {
+		log.Fatal("Mongo connect error: ", err)
+	}
+	if err := client.Ping(connectCtx, nil); err != nil {
+		log.Fatal("Mongo connect error: ", err)
+	}
+
+	db := client.Database(c.db)
+
+	userRepo := repositories.NewUserRepository(db)
+	projectRepo := repositories.NewProjectRepository(db)
+	taskRepo := repositories.NewTaskRepository(db)
+	sprintRepo := repositories.NewSprintRepository(db)
+	taskEventRepo := repositories.NewTaskEventRepository(db)
+	milestoneRepo := repositories.NewMilestoneRepository(db)
+	teamRepo := repositories.NewTeamRepository(db)
+	workspaceRepo := repositories.NewWorkspaceRepository(db)
+	uow := repositories.NewUnitOfWork(client)
+
+	projectService := services.NewProjectService(projectRepo, taskRepo, userRepo, teamRepo, uow)
+	taskService := services.NewTaskService(taskRepo, projectRepo, userRepo, sprintRepo, milestoneRepo, taskEventRepo, uow)
+	userService := services.NewUserService(userRepo, projectService, taskService, uow)
+
+	return &env{
+		client:     client,
+		db:         db,
+		users:      userService,
+		projects:   projectService,
+		workspaces: services.NewWorkspaceService(workspaceRepo, userService, uow, false),
+		json:       c.json,
+	}
+}
+
+func (e *env) close(ctx context.Context) {
+	e.client.Disconnect(ctx)
+}
+
+// scope resolves the workspace and returns a context scoped to it. The
+// default workspace is created on demand, as the server does on start.
+func (e *env) scope(ctx context.Context, slug string) (context.Context, *models.Workspace) {
+	if slug == models.DefaultWorkspaceSlug {
+		if _, _, err := e.workspaces.EnsureDefaultWorkspace(ctx); err != nil {
+			fail(err)
+		}
+	}
+
+	workspace, err := e.workspaces.ResolveWorkspace(ctx, slug)
+	if err != nil {
+		fail(err)
+	}
+	return services.WithWorkspace(ctx, workspace.ID), workspace
+}
+
+// operator is the actor for service calls that check permissions. It has
+// the rights of a super_admin and an ID no stored user has, so rules such as
+// "you cannot change your own role" never trigger.
+func operator(workspace *models.Workspace) *models.User {
+	return &models.User{
+		WorkspaceID: workspace.ID,
+		Name:        "admin CLI",
+		Role:        models.RoleSuperAdmin,
+	}
+}
+
+// findUser accepts an ObjectID or a user_id.
+func (e *env) findUser(ctx context.Context, ref string) *models.User {
+	var user *models.User
+	var err error
+
+	if id, parseErr := primitive.ObjectIDFromHex(ref); parseErr == nil {
+		user, err = e.users.GetUserByObjectID(ctx, id)
+	} else {
+		user, err = e.users.GetUserByUserID(ctx, ref)
+	}
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		fail(fmt.Errorf("user %q not found", ref))
+	}
+	if err != nil {
+		fail(err)
+	}
+	return user
+}
+
+// =====================
+// COMMANDS
+// =====================
+
+func bootstrap(args []string) {
+	fs, c := newFlagSet("bootstrap")
+	user := userFlags(fs)
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from stdin instead of generating one")
+	workspaceName := fs.String("workspace-name", "", "create a new workspace -workspace with this name")
+	fs.Parse(args)
+
+	ctx := context.Background()
+	e := open(ctx, c)
+	defer e.close(ctx)
+
+	password, generated := readPassword(*passwordStdin)
+
+	var workspace *models.Workspace
+	var admin *models.User
+	var err error
+
+	if *workspaceName != "" {
+		workspace, admin, err = e.workspaces.CreateWorkspace(ctx,
+			&models.Workspace{Name: *workspaceName, Slug: c.workspace}, user, password)
+	} else {
+		var scoped context.Context
+		scoped, workspace = e.scope(ctx, c.workspace)
+		admin, err = e.users.BootstrapSuperAdmin(scoped, user, password)
+	}
+	if err != nil {
+		fail(err)
+	}
+
+	result := map[string]interface{}{
+		"workspace": workspace.Slug,
+		"user":      admin,
+	}
+	if generated {
+		result["password"] = password
+	}
+
+	e.print(result, func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "created super_admin %s (%s) in workspace %s\n", admin.UserID, admin.ID.Hex(), workspace.Slug)
+		if generated {
+			fmt.Fprintf(w, "password: %s\n", password)
+			fmt.Fprintln(w, "it is shown only once")
+		}
+	})
+}
+
+func listUsers(args []string) {
+	fs, c := newFlagSet("users list")
+	status := fs.String("status", "", "only list users with this status")
+	fs.Parse(args)
+
+	ctx := context.Background()
+	e := open(ctx, c)
+	defer e.close(ctx)
+
+	ctx, _ = e.scope(ctx, c.workspace)
+
+	var users []models.User
+	var err error
+	if *status != "" {
+		users, err = e.users.GetUsersByStatus(ctx, *status)
+	} else {
+		users, err = e.users.GetAllUsers(ctx)
+	}
+	if err != nil {
+		fail(err)
+	}
+
+	e.print(users, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "ID\tUSER_ID\tNAME\tEMAIL\tROLE\tSTATUS\tPASSWORD")
+		for _, u := range users {
+			status := u.Status
+			if status == "" {
+				status = models.UserStatusActive
+			}
+			password := "set"
+			if u.PasswordHash == "" {
+				password = "none (cannot log in)"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", u.ID.Hex(), u.UserID, u.Name, u.Email, u.Role, status, password)
+		}
+	})
+}
+
+func createUser(args []string) {
+	fs, c := newFlagSet("users create")
+	user := userFlags(fs)
+	role := fs.String("role", models.RoleEmployee, "employee, admin or super_admin")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from stdin instead of generating one")
+	fs.Parse(args)
+
+	ctx := context.Background()
+	e := open(ctx, c)
+	defer e.close(ctx)
+
+	ctx, workspace := e.scope(ctx, c.workspace)
+
+	password, generated := readPassword(*passwordStdin)
+
+	user.Role = *role
+
+	created, err := e.users.CreateUser(ctx, operator(workspace), user, password)
+	if err != nil {
+		fail(err)
+	}
+
+	result := map[string]interface{}{"user": created}
+	if generated {
+		result["password"] = password
+	}
+
+	e.print(result, func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "created %s %s (%s) in workspace %s\n", created.Role, created.UserID, created.ID.Hex(), workspace.Slug)
+		if generated {
+			fmt.Fprintf(w, "password: %s\n", password)
+		}
+	})
+}
+
+func deactivateUser(args []string) {
+	fs, c := newFlagSet("users deactivate")
+	ref := fs.String("user", "", "user to deactivate")
+	successorRef := fs.String("successor", "", "user who takes over open tasks and owned projects")
+	fs.Parse(args)
+
+	if *ref == "" {
+		usage()
+	}
+
+	ctx := context.Background()
+	e := open(ctx, c)
+	defer e.close(ctx)
+
+	ctx, workspace := e.scope(ctx, c.workspace)
+
+	user := e.findUser(ctx, *ref)
+	successorID := ""
+	if *successorRef != "" {
+		successorID = e.findUser(ctx, *successorRef).ID.Hex()
+	}
+
+	plan, err := e.users.DeactivateUser(ctx, operator(workspace), user.ID.Hex(), successorID)
+	if err != nil {
+		fail(err)
+	}
+
+	e.print(plan, func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "deactivated %s\n", user.UserID)
+		if plan.SuccessorID != "" {
+			fmt.Fprintf(w, "handed %d open tasks and %d projects to %s\n",
+				len(plan.OpenTasks), len(plan.OwnedProjects), *successorRef)
+		}
+	})
+}
+
+func setRole(args []string) {
+	fs, c := newFlagSet("users set-role")
+	ref := fs.String("user", "", "user whose role changes")
+	role := fs.String("role", "", "employee, admin or super_admin")
+	fs.Parse(args)
+
+	if *ref == "" || *role == "" {
+		usage()
+	}
+
+	ctx := context.Background()
+	e := open(ctx, c)
+	defer e.close(ctx)
+
+	ctx, workspace := e.scope(ctx, c.workspace)
+
+	user := e.findUser(ctx, *ref)
+	previous := user.Role
+
+	updated, err := e.users.ChangeRole(ctx, operator(workspace), user.ID.Hex(), *role)
+	if err != nil {
+		fail(err)
+	}
+
+	e.print(updated, func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "%s: %s -> %s\n", updated.UserID, previous, updated.Role)
+	})
+}
+
+func setPassword(args []string) {
+	fs, c := newFlagSet("users set-password")
+	ref := fs.String("user", "", "user whose password is set")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from stdin instead of generating one")
+	fs.Parse(args)
+
+	if *ref == "" {
+		usage()
+	}
+
+	ctx := context.Background()
+	e := open(ctx, c)
+	defer e.close(ctx)
+
+	ctx, workspace := e.scope(ctx, c.workspace)
+
+	user := e.findUser(ctx, *ref)
+	password, generated := readPassword(*passwordStdin)
+
+	if err := e.users.SetPassword(ctx, operator(workspace), user.ID.Hex(), "", password); err != nil {
+		fail(err)
+	}
+
+	result := map[string]interface{}{"user": user.UserID}
+	if generated {
+		result["password"] = password
+	}
+
+	e.print(result, func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "password set for %s\n", user.UserID)
+		if generated {
+			fmt.Fprintf(w, "password: %s\n", password)
+		}
+	})
+}
+
+func transferProject(args []string) {
+	fs, c := newFlagSet("projects transfer")
+	projectID := fs.String("project", "", "project ID")
+	to := fs.String("to", "", "new owner")
+	fs.Parse(args)
+
+	if *projectID == "" || *to == "" {
+		usage()
+	}
+
+	ctx := context.Background()
+	e := open(ctx, c)
+	defer e.close(ctx)
+
+	ctx, workspace := e.scope(ctx, c.workspace)
+
+	newOwner := e.findUser(ctx, *to)
+	if err := e.projects.TransferOwnership(ctx, operator(workspace), *projectID, newOwner.ID.Hex()); err != nil {
+		fail(err)
+	}
+
+	e.print(map[string]string{"projectId": *projectID, "ownerId": newOwner.ID.Hex()}, func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "project %s now belongs to %s\n", *projectID, newOwner.UserID)
+	})
+}
+
+func indexes(args []string) {
+	fs, c := newFlagSet("indexes")
+	fs.Parse(args)
+
+	ctx := context.Background()
+	e := open(ctx, c)
+	defer e.close(ctx)
+
+	// Indexes live in migrations: apply the pending ones, then re-run the
+	// applied ones to restore anything dropped since.
+	runner := migrations.NewRunner(e.db, migrations.All(), log.Printf)
+
+	applied, err := runner.Migrate(ctx, migrations.Latest)
+	if err != nil {
+		fail(err)
+	}
+	reapplied, err := runner.Reapply(ctx)
+	if err != nil {
+		fail(err)
+	}
+
+	result := map[string]interface{}{
+		"database":  c.db,
+		"applied":   applied,
+		"reapplied": reapplied,
+	}
+	e.print(result, func(w *tabwriter.Writer) {
+		fmt.Fprintf(w, "indexes of %s are up to date (%d migrations applied, %d re-run)\n", c.db, len(applied), len(reapplied))
+	})
+}
+
+// stats reports on the -workspace given explicitly, or on all of them.
+func stats(args []string) {
+	fs, c := newFlagSet("stats")
+	fs.Parse(args)
+
+	explicit := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "workspace" {
+			explicit = true
+		}
+	})
+
+	ctx := context.Background()
+	e := open(ctx, c)
+	defer e.close(ctx)
+
+	var workspaces []models.Workspace
+	if explicit {
+		_, workspace := e.scope(ctx, c.workspace)
+		workspaces = []models.Workspace{*workspace}
+	} else {
+		var err error
+		if workspaces, err = e.workspaces.GetAllWorkspaces(ctx); err != nil {
+			fail(err)
+		}
+	}
+
+	all := make([]*dto.WorkspaceStats, 0, len(workspaces))
+	for i := range workspaces {
+		s, err := e.workspaces.GetStats(ctx, &workspaces[i])
+		if err != nil {
+			fail(err)
+		}
+		all = append(all, s)
+	}
+
+	e.print(all, func(w *tabwriter.Writer) {
+		for _, s := range all {
+			printStats(w, s)
+		}
+	})
+}
+
+// =====================
+// OUTPUT
+// =====================
+
+// print writes v as JSON with -json, and calls text otherwise.
+func (e *env) print(v interface{}, text func(w *tabwriter.Writer)) {
+	if e.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	text(w)
+	w.Flush()
+}
+
+func printStats(w *tabwriter.Writer, s *dto.WorkspaceStats) {
+	fmt.Fprintf(w, "workspace %s (%s)\n", s.Workspace.Slug, s.Workspace.Name)
+	fmt.Fprintf(w, "  projects\t%d active, %d archived\n", s.ActiveProjects, s.ArchivedProjects)
+	fmt.Fprintf(w, "  users by role\t%s\n", formatCounts(s.UsersByRole))
+	fmt.Fprintf(w, "  users by status\t%s\n", formatCounts(s.UsersByStatus))
+	fmt.Fprintf(w, "  tasks by status\t%s\n", formatCounts(s.TasksByStatus))
+	fmt.Fprintf(w, "  documents\t%s\n", formatCounts(s.Documents))
+}
+
+func formatCounts(counts map[string]int64) string {
+	if len(counts) == 0 {
+		return "-"
+	}
+
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%d", k, counts[k])
+	}
+	return strings.Join(parts, " ")
+}
+
+// fail prints err and exits. Validation errors list every field.
+func fail(err error) {
+	var validationErr *services.ValidationError
+	if errors.As(err, &validationErr) {
+		for _, f := range validationErr.Fields {
+			log.Printf("%s: %s", f.Field, f.Message)
+		}
+		os.Exit(1)
+	}
+	log.Fatal(err)
+}
+
+// =====================
+// INPUT
+// =====================
+
+func userFlags(fs *flag.FlagSet) *models.User {
+	user := &models.User{}
+	fs.StringVar(&user.UserID, "user-id", "", "login name")
+	fs.StringVar(&user.Name, "name", "", "display name")
+	fs.StringVar(&user.Email, "email", "", "email address")
+	return user
+}
+
+// readPassword reads one line from stdin, or generates a random password
+// when fromStdin is off. Passwords are never taken as flags, which would
+// leave them in shell history and process listings.
+func readPassword(fromStdin bool) (string, bool) {
+	if fromStdin {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			log.Fatal("reading password from stdin: ", err)
+		}
+		return strings.TrimRight(line, "\r\n"), false
+	}
+
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), true
+}
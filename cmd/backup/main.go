@@ -0,0 +1,167 @@
+// Command backup dumps the database to a compressed archive and restores
+// such an archive into an empty database.
+//
+//	backup dump    [-uri URI] [-db NAME] [-out FILE]
+//	backup verify  -in FILE
+//	backup restore [-uri URI] [-db NAME] -in FILE [-ids preserve|remap]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/backup"
+	"Concurrent_Task_Management_System/internal/migrations"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultURI      = "mongodb://localhost:27017"
+	defaultDatabase = "trello_lite"
+)
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "dump":
+		dump(os.Args[2:])
+	case "verify":
+		verify(os.Args[2:])
+	case "restore":
+		restore(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage:
+  backup dump    [-uri URI] [-db NAME] [-out FILE]
+  backup verify  -in FILE
+  backup restore [-uri URI] [-db NAME] -in FILE [-ids preserve|remap]`)
+	os.Exit(2)
+}
+
+func dump(args []string) {
+	fs := flag.NewFlagSet("dump", flag.ExitOnError)
+	uri := fs.String("uri", defaultURI, "MongoDB connection string")
+	dbName := fs.String("db", defaultDatabase, "database to dump")
+	out := fs.String("out", "", "archive to write (default <db>-<time>.tar.gz)")
+	fs.Parse(args)
+
+	if *out == "" {
+		*out = fmt.Sprintf("%s-%s.tar.gz", *dbName, time.Now().UTC().Format("20060102T150405Z"))
+	}
+
+	ctx := context.Background()
+	client := connect(ctx, *uri)
+	defer client.Disconnect(ctx)
+
+	// Write next to the target and rename at the end, so a failed dump
+	// never leaves something that looks like a finished archive.
+	tmp := *out + ".partial"
+	f, err := os.Create(tmp)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	manifest, err := backup.Dump(ctx, client, client.Database(*dbName), f, log.Printf)
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		log.Fatal("Dump failed: ", err)
+	}
+	if err := os.Rename(tmp, *out); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("wrote %s: %d collections, %d documents", *out, len(manifest.Collections), total(manifest))
+}
+
+func verify(args []string) {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	in := fs.String("in", "", "archive to check")
+	fs.Parse(args)
+
+	if *in == "" {
+		usage()
+	}
+
+	manifest, _, err := backup.Verify(*in, false)
+	if err != nil {
+		log.Fatal("Verify failed: ", err)
+	}
+
+	log.Printf("%s is intact: database %s, taken %s", *in, manifest.Database, manifest.CreatedAt.Format(time.RFC3339))
+	for _, c := range manifest.Collections {
+		log.Printf("  %-20s %d documents", c.Name, c.Documents)
+	}
+}
+
+func restore(args []string) {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	uri := fs.String("uri", defaultURI, "MongoDB connection string")
+	dbName := fs.String("db", defaultDatabase, "empty database to restore into")
+	in := fs.String("in", "", "archive to restore")
+	ids := fs.String("ids", "preserve", "preserve ObjectIDs, or remap them to new ones")
+	fs.Parse(args)
+
+	if *in == "" || (*ids != "preserve" && *ids != "remap") {
+		usage()
+	}
+
+	ctx := context.Background()
+	client := connect(ctx, *uri)
+	defer client.Disconnect(ctx)
+
+	db := client.Database(*dbName)
+
+	manifest, err := backup.Restore(ctx, db, *in, backup.RestoreOptions{RemapIDs: *ids == "remap"}, log.Printf)
+	if err != nil {
+		log.Fatal("Restore failed: ", err)
+	}
+
+	if _, err := migrations.NewRunner(db, migrations.All(), log.Printf).Migrate(ctx, migrations.Latest); err != nil {
+		log.Fatal("Migrations failed: ", err)
+	}
+
+	log.Printf("restored %d collections, %d documents into %s", len(manifest.Collections), total(manifest), *dbName)
+}
+
+func connect(ctx context.Context, uri string) *mongo.Client {
+	connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(connectCtx, options.Client().ApplyURI(uri))
+	if err != nil {
+		log.Fatal("Mongo connect error: ", err)
+	}
+	if err := client.Ping(connectCtx, nil); err != nil {
+		log.Fatal("Mongo connect error: ", err)
+	}
+	return client
+}
+
+func total(m *backup.Manifest) int64 {
+	var n int64
+	for _, c := range m.Collections {
+		n += c.Documents
+	}
+	return n
+}
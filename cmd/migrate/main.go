@@ -0,0 +1,155 @@
+// Command migrate shows and applies schema migrations. The server applies
+// pending migrations on start; this is for planning them, rolling back and
+// running them ahead of a deploy.
+//
+//	migrate status [-uri URI] [-db NAME] [-json]
+//	migrate plan   [-uri URI] [-db NAME] [-to VERSION] [-json]
+//	migrate run    [-uri URI] [-db NAME] [-to VERSION] [-wait DURATION] [-json]
+//
+// Without -to, plan and run go to the newest migration. A -to below the
+// applied migrations rolls them back; -to 0 rolls back everything.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/migrations"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultURI      = "mongodb://localhost:27017"
+	defaultDatabase = "trello_lite"
+)
+
+func main() {
+	log.SetFlags(0)
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	command := os.Args[1]
+	if command != "status" && command != "plan" && command != "run" {
+		usage()
+	}
+
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	uri := fs.String("uri", defaultURI, "MongoDB connection string")
+	dbName := fs.String("db", defaultDatabase, "database name")
+	to := fs.Int("to", migrations.Latest, "target version (default newest)")
+	wait := fs.Duration("wait", 2*time.Minute, "how long to wait for another run holding the lock")
+	asJSON := fs.Bool("json", false, "print JSON instead of text")
+	fs.Parse(os.Args[2:])
+
+	ctx := context.Background()
+	client := connect(ctx, *uri)
+	defer client.Disconnect(ctx)
+
+	// Progress goes to stderr, so -json output stays parseable.
+	runner := migrations.NewRunner(client.Database(*dbName), migrations.All(), log.Printf)
+	runner.LockWait = *wait
+
+	switch command {
+	case "status":
+		statuses, err := runner.Status(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		output(*asJSON, statuses, func(w *tabwriter.Writer) {
+			fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+			for _, s := range statuses {
+				state, at := "pending", "-"
+				if s.Applied {
+					state, at = "applied", s.AppliedAt.Format(time.RFC3339)
+				}
+				if !s.Known {
+					state += " (unknown to this binary)"
+				}
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Version, s.Name, state, at)
+			}
+		})
+
+	case "plan":
+		steps, err := runner.Plan(ctx, *to)
+		if err != nil {
+			log.Fatal(err)
+		}
+		output(*asJSON, steps, func(w *tabwriter.Writer) {
+			printSteps(w, steps, "nothing to do")
+		})
+
+	case "run":
+		steps, err := runner.Migrate(ctx, *to)
+		if err != nil {
+			if len(steps) > 0 {
+				log.Printf("completed before the failure:")
+				for _, s := range steps {
+					log.Printf("  %d %s %s", s.Version, s.Name, s.Direction)
+				}
+			}
+			log.Fatal("Migration failed: ", err)
+		}
+		output(*asJSON, steps, func(w *tabwriter.Writer) {
+			printSteps(w, steps, "already up to date")
+		})
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage:
+  migrate status [-uri URI] [-db NAME] [-json]
+  migrate plan   [-uri URI] [-db NAME] [-to VERSION] [-json]
+  migrate run    [-uri URI] [-db NAME] [-to VERSION] [-wait DURATION] [-json]`)
+	os.Exit(2)
+}
+
+func connect(ctx context.Context, uri string) *mongo.Client {
+	connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(connectCtx, options.Client().ApplyURI(uri))
+	if err != nil {
+		log.Fatal("Mongo connect error: ", err)
+	}
+	if err := client.Ping(connectCtx, nil); err != nil {
+		log.Fatal("Mongo connect error: ", err)
+	}
+	return client
+}
+
+// output writes v as JSON with -json, and calls text otherwise.
+func output(asJSON bool, v interface{}, text func(w *tabwriter.Writer)) {
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(v); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	text(w)
+	w.Flush()
+}
+
+func printSteps(w *tabwriter.Writer, steps []migrations.Step, empty string) {
+	if len(steps) == 0 {
+		fmt.Fprintln(w, empty)
+		return
+	}
+	fmt.Fprintln(w, "VERSION\tNAME\tDIRECTION")
+	for _, s := range steps {
+		fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, s.Direction)
+	}
+}
@@ -0,0 +1,150 @@
+// Command seed fills a workspace with generated users, projects and tasks.
+// Runs with the same -seed and -now produce identical data.
+//
+//	seed [-uri URI] [-db NAME] [-workspace SLUG] [-seed N] [-now DATE]
+//	     [-admins N] [-employees N] [-projects N] [-members N] [-tasks N] [-json]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/repositories"
+	"Concurrent_Task_Management_System/internal/seed"
+	"Concurrent_Task_Management_System/internal/services"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultURI      = "mongodb://localhost:27017"
+	defaultDatabase = "trello_lite"
+)
+
+func main() {
+	log.SetFlags(0)
+
+	uri := flag.String("uri", defaultURI, "MongoDB connection string")
+	dbName := flag.String("db", defaultDatabase, "database name")
+	slug := flag.String("workspace", models.DefaultWorkspaceSlug, "workspace to fill; created if missing")
+	seedValue := flag.Int64("seed", 1, "random seed")
+	now := flag.String("now", "", "reference date (YYYY-MM-DD or RFC 3339) that ages and due dates are relative to (default today)")
+	password := flag.String("password", "password123", "password of every generated user")
+	asJSON := flag.Bool("json", false, "print the summary as JSON")
+
+	cfg := seed.Config{}
+	flag.IntVar(&cfg.Admins, "admins", 5, "number of admins; they own the projects")
+	flag.IntVar(&cfg.Employees, "employees", 50, "number of employees")
+	flag.IntVar(&cfg.Projects, "projects", 20, "number of projects")
+	flag.IntVar(&cfg.MembersPerProject, "members", 8, "average employees per project")
+	flag.IntVar(&cfg.TasksPerProject, "tasks", 200, "average tasks per project")
+	flag.Parse()
+
+	cfg.Seed = *seedValue
+	cfg.Now = referenceTime(*now)
+
+	hash, err := services.HashPassword(*password)
+	if err != nil {
+		log.Fatal(err)
+	}
+	cfg.PasswordHash = hash
+
+	ctx := context.Background()
+
+	connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(connectCtx, options.Client().ApplyURI(*uri))
+	if err == nil {
+		err = client.Ping(connectCtx, nil)
+	}
+	if err != nil {
+		log.Fatal("Mongo connect error: ", err)
+	}
+	defer client.Disconnect(ctx)
+
+	db := client.Database(*dbName)
+
+	workspace, err := findOrCreateWorkspace(ctx, repositories.NewWorkspaceRepository(db), *slug)
+	if err != nil {
+		log.Fatal("Workspace error: ", err)
+	}
+	ctx = repositories.WithTenant(ctx, workspace.ID)
+
+	start := time.Now()
+	result, err := seed.Generate(ctx, seed.Repositories{
+		Users:    repositories.NewUserRepository(db),
+		Projects: repositories.NewProjectRepository(db),
+		Tasks:    repositories.NewTaskRepository(db),
+	}, cfg, log.Printf)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			log.Print("the workspace already holds data from this seed; use another -seed, -workspace or database")
+		}
+		log.Fatal("Seed failed: ", err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	log.Printf("seeded workspace %s in %s", workspace.Slug, time.Since(start).Round(time.Millisecond))
+	log.Printf("  users     %d admins, %d employees", result.Admins, result.Employees)
+	log.Printf("  projects  %d (%d archived), %d memberships", result.Projects, result.ArchivedProjects, result.Memberships)
+	log.Printf("  tasks     %d, %d overdue", result.Tasks, result.OverdueTasks)
+
+	statuses := make([]string, 0, len(result.TasksByStatus))
+	for status := range result.TasksByStatus {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	for _, status := range statuses {
+		log.Printf("    %-12s %d", status, result.TasksByStatus[status])
+	}
+}
+
+// referenceTime parses -now. The default is midnight UTC today, so runs on
+// the same day match; pass -now to reproduce data on another day.
+func referenceTime(value string) time.Time {
+	if value == "" {
+		return time.Now().UTC().Truncate(24 * time.Hour)
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	log.Fatalf("invalid -now %q: use YYYY-MM-DD or RFC 3339", value)
+	return time.Time{}
+}
+
+func findOrCreateWorkspace(
+	ctx context.Context,
+	repo repositories.WorkspaceRepository,
+	slug string,
+) (*models.Workspace, error) {
+
+	workspace, err := repo.FindBySlug(ctx, slug)
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return workspace, err
+	}
+
+	workspace = &models.Workspace{Name: slug, Slug: slug, CreatedAt: time.Now()}
+	if err := repo.Create(ctx, workspace); err != nil {
+		return nil, err
+	}
+	return workspace, nil
+}
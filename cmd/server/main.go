@@ -8,7 +8,10 @@ import (
 	"os/signal"
 	"time"
 
+	"Concurrent_Task_Management_System/internal/config"
 	"Concurrent_Task_Management_System/internal/handlers"
+	"Concurrent_Task_Management_System/internal/mailer"
+	"Concurrent_Task_Management_System/internal/migrations"
 	"Concurrent_Task_Management_System/internal/repositories"
 	"Concurrent_Task_Management_System/internal/routes"
 	"Concurrent_Task_Management_System/internal/services"
@@ -21,6 +24,8 @@ import (
 
 func main() {
 
+	cfg := config.Load()
+
 	// MongoDB Connection
 	mongoURI := "mongodb://localhost:27017"
 
@@ -37,28 +42,135 @@ func main() {
 	}
 
 	db := client.Database("trello_lite")
-	utils.EnsureMongoIndexes(db)
+
+	// Apply pending schema migrations; other instances starting at the
+	// same time wait for the lock and then find nothing left to do.
+	if _, err := migrations.NewRunner(db, migrations.All(), log.Printf).Migrate(context.Background(), migrations.Latest); err != nil {
+		log.Fatal("Migration error:", err)
+	}
 
 	// Repositories
 	userRepo := repositories.NewUserRepository(db)
 	projectRepo := repositories.NewProjectRepository(db)
 	taskRepo := repositories.NewTaskRepository(db)
+	sprintRepo := repositories.NewSprintRepository(db)
+	taskEventRepo := repositories.NewTaskEventRepository(db)
+	milestoneRepo := repositories.NewMilestoneRepository(db)
+	templateRepo := repositories.NewProjectTemplateRepository(db)
 	dashboardRepo := repositories.NewDashboardRepository(db) // ✅ ADD
+	workspaceRepo := repositories.NewWorkspaceRepository(db)
+	teamRepo := repositories.NewTeamRepository(db)
+	invitationRepo := repositories.NewInvitationRepository(db)
+	presenceRepo := repositories.NewPresenceRepository(db)
+	uow := repositories.NewUnitOfWork(client)
 
 	// Services
-	projectService := services.NewProjectService(projectRepo)
-	userService := services.NewUserService(userRepo, projectService)
-	taskService := services.NewTaskService(taskRepo)
+	projectService := services.NewProjectService(projectRepo, taskRepo, userRepo, teamRepo, uow)
+	taskService := services.NewTaskService(taskRepo, projectRepo, userRepo, sprintRepo, milestoneRepo, taskEventRepo, uow)
+	sprintService := services.NewSprintService(sprintRepo, projectRepo, taskRepo, taskEventRepo, uow)
+	milestoneService := services.NewMilestoneService(
+		milestoneRepo,
+		projectRepo,
+		taskRepo,
+		uow,
+		cfg.MilestoneAtRiskWindow,
+		cfg.MilestoneAtRiskPercent,
+	)
+	templateService := services.NewTemplateService(
+		templateRepo,
+		projectRepo,
+		taskRepo,
+		milestoneRepo,
+		projectService,
+		uow,
+	)
+	userService := services.NewUserService(userRepo, projectService, taskService, uow)
+	workspaceService := services.NewWorkspaceService(workspaceRepo, userService, uow, cfg.WorkspaceSignup)
+	teamService := services.NewTeamService(teamRepo, userRepo, projectRepo, taskRepo, uow)
+	invitationService := services.NewInvitationService(
+		invitationRepo,
+		userRepo,
+		projectRepo,
+		userService,
+		uow,
+		mailer.New(cfg),
+		cfg.InvitationTTL,
+		cfg.AppBaseURL,
+	)
+
+	// Adopting orphans can touch every document on the first start, far
+	// longer than the connect timeout allows.
+	defaultWorkspace, adopted, err := workspaceService.EnsureDefaultWorkspace(context.Background())
+	if err != nil {
+		log.Fatal("Default workspace error:", err)
+	}
+	if adopted > 0 {
+		log.Printf("Moved %d documents into workspace %q", adopted, defaultWorkspace.Slug)
+	}
 
 	dashboardService := services.NewDashboardService(
 		dashboardRepo,
 		userService,
 	)
 
+	exportService := services.NewExportService(
+		projectService,
+		projectRepo,
+		taskRepo,
+		sprintRepo,
+		milestoneRepo,
+		taskEventRepo,
+		userRepo,
+		teamRepo,
+		uow,
+	)
+
+	calendarService := services.NewCalendarService(
+		userRepo,
+		taskRepo,
+		projectRepo,
+		projectService,
+		cfg.AppBaseURL,
+	)
+
+	trashService := services.NewTrashService(
+		userRepo,
+		projectRepo,
+		taskRepo,
+		taskEventRepo,
+		uow,
+		cfg.TrashRetention,
+	)
+
+	liveService := services.NewLiveService(
+		taskRepo,
+		userRepo,
+		projectService,
+	)
+
+	presenceService := services.NewPresenceService(
+		presenceRepo,
+		taskRepo,
+		userRepo,
+		projectService,
+		cfg.PresenceTTL,
+	)
+
 	// Handlers
 	userHandler := handlers.NewUserHandler(userService)
-	projectHandler := handlers.NewProjectHandler(projectService)
-	taskHandler := handlers.NewTaskHandler(taskService)
+	projectHandler := handlers.NewProjectHandler(projectService, userService)
+	taskHandler := handlers.NewTaskHandler(taskService, userService)
+	trashHandler := handlers.NewTrashHandler(trashService, userService)
+	sprintHandler := handlers.NewSprintHandler(sprintService, userService)
+	milestoneHandler := handlers.NewMilestoneHandler(milestoneService, userService)
+	templateHandler := handlers.NewTemplateHandler(templateService, userService)
+	workspaceHandler := handlers.NewWorkspaceHandler(workspaceService, userService)
+	teamHandler := handlers.NewTeamHandler(teamService, userService)
+	invitationHandler := handlers.NewInvitationHandler(invitationService, userService)
+	exportHandler := handlers.NewExportHandler(exportService, userService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService, userService)
+	liveHandler := handlers.NewLiveHandler(liveService, userService)
+	presenceHandler := handlers.NewPresenceHandler(presenceService, userService)
 
 	dashboardHandler := handlers.NewDashboardHandler(
 		dashboardService,
@@ -67,7 +179,7 @@ func main() {
 
 
 	// ✅ ADD THIS
-	authHandler := handlers.NewAuthHandler(userService)
+	authHandler := handlers.NewAuthHandler(userService, workspaceService)
 
 	// Router
 	router := mux.NewRouter()
@@ -77,6 +189,17 @@ func main() {
 	routes.RegisterProjectRoutes(router, projectHandler)
 	routes.RegisterTaskRoutes(router, taskHandler)
 	routes.RegisterDashboardRoutes(router, dashboardHandler)
+	routes.RegisterTrashRoutes(router, trashHandler)
+	routes.RegisterSprintRoutes(router, sprintHandler)
+	routes.RegisterMilestoneRoutes(router, milestoneHandler)
+	routes.RegisterTemplateRoutes(router, templateHandler)
+	routes.RegisterWorkspaceRoutes(router, workspaceHandler)
+	routes.RegisterTeamRoutes(router, teamHandler)
+	routes.RegisterInvitationRoutes(router, invitationHandler)
+	routes.RegisterExportRoutes(router, exportHandler)
+	routes.RegisterCalendarRoutes(router, calendarHandler)
+	routes.RegisterLiveRoutes(router, liveHandler)
+	routes.RegisterPresenceRoutes(router, presenceHandler)
 
 	// ✅ ADD THIS
 	routes.RegisterAuthRoutes(router, authHandler)
@@ -96,6 +219,16 @@ func main() {
 		Addr:    ":8080",
 		Handler: router,
 	}
+	// Event streams never finish on their own; end them so Shutdown can.
+	server.RegisterOnShutdown(liveService.Shutdown)
+	server.RegisterOnShutdown(presenceService.Shutdown)
+
+	// Background jobs
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	trashService.StartPurgeLoop(jobsCtx, cfg.TrashPurgeInterval)
+	presenceService.StartBroadcastLoop(jobsCtx)
 
 	// Start Server
 	go func() {
@@ -111,6 +244,7 @@ func main() {
 	<-stop
 
 	log.Println(" Shutting down server...")
+	stopJobs()
 
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer shutdownCancel()
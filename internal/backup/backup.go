@@ -0,0 +1,510 @@
+// Package backup dumps a whole database to a single archive and restores
+// it into an empty one.
+//
+// An archive is a gzip-compressed tar file. Its first entry, manifest.json,
+// describes the dump; every collection follows as collections/<name>.jsonl,
+// one document per line in canonical Extended JSON, which keeps every BSON
+// type and can be read by any MongoDB tooling.
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/migrations"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	Format  = "ctms-backup"
+	Version = 1
+
+	manifestName = "manifest.json"
+	insertBatch  = 1000
+
+	// Lines are single documents, which MongoDB caps at 16 MB; Extended
+	// JSON can be a few times larger than the BSON.
+	maxLineSize = 64 << 20
+)
+
+var ErrNotEmpty = errors.New("target database is not empty")
+
+type Manifest struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	Database  string    `json:"database"`
+
+	// SnapshotTime is the cluster time every collection was read at.
+	SnapshotTime *primitive.Timestamp `json:"snapshotTime,omitempty"`
+
+	Collections []CollectionEntry `json:"collections"`
+}
+
+type CollectionEntry struct {
+	Name      string `json:"name"`
+	File      string `json:"file"`
+	Documents int64  `json:"documents"`
+	SHA256    string `json:"sha256"`
+}
+
+// =====================
+// DUMP
+// =====================
+
+// Dump writes every collection of db to w. All reads share one snapshot
+// session, so the archive reflects a single point in time even while the
+// application keeps writing. Snapshot reads need a replica set, and the
+// dump must finish within the server's snapshot history window
+// (minSnapshotHistoryWindowInSeconds, 5 minutes by default).
+func Dump(ctx context.Context, client *mongo.Client, db *mongo.Database, w io.Writer, logf func(string, ...interface{})) (*Manifest, error) {
+	manifest := &Manifest{
+		Format:    Format,
+		Version:   Version,
+		CreatedAt: time.Now().UTC(),
+		Database:  db.Name(),
+	}
+
+	// Migration records are left out: a restore runs every migration
+	// again, which is also what creates the indexes. So is presence, which
+	// only describes connections that are gone by the time of a restore.
+	names, err := db.ListCollectionNames(ctx, bson.M{"name": bson.M{
+		"$not": primitive.Regex{Pattern: `^system\.`},
+		"$nin": bson.A{migrations.Collection, migrations.LockCollection, "presence"},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	tmpDir, err := os.MkdirTemp("", "backup-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	session, err := client.StartSession(options.Session().SetSnapshot(true))
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+
+	// Collections are spooled to temporary files first: tar needs each
+	// entry's size up front, and the manifest, which goes first, needs
+	// the counts and checksums.
+	err = mongo.WithSession(ctx, session, func(sc mongo.SessionContext) error {
+		for _, name := range names {
+			entry, err := dumpCollection(sc, db.Collection(name), tmpDir)
+			if err != nil {
+				return fmt.Errorf("dumping %s: %w", name, err)
+			}
+			logf("dumped %s: %d documents", name, entry.Documents)
+			manifest.Collections = append(manifest.Collections, *entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	manifest.SnapshotTime = session.OperationTime()
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeEntry(tw, manifestName, manifest.CreatedAt, int64(len(manifestJSON)), bytes.NewReader(manifestJSON)); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range manifest.Collections {
+		if err := copyEntry(tw, tmpDir, entry, manifest.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+func dumpCollection(ctx context.Context, coll *mongo.Collection, dir string) (*CollectionEntry, error) {
+	entry := &CollectionEntry{
+		Name: coll.Name(),
+		File: "collections/" + coll.Name() + ".jsonl",
+	}
+
+	f, err := os.Create(spoolPath(dir, entry.Name))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	out := bufio.NewWriter(io.MultiWriter(f, hash))
+
+	cursor, err := coll.Find(ctx, bson.D{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		line, err := bson.MarshalExtJSON(cursor.Current, true, false)
+		if err != nil {
+			return nil, err
+		}
+		out.Write(line)
+		out.WriteByte('\n')
+		entry.Documents++
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	if err := out.Flush(); err != nil {
+		return nil, err
+	}
+
+	entry.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	return entry, nil
+}
+
+func copyEntry(tw *tar.Writer, dir string, entry CollectionEntry, modTime time.Time) error {
+	f, err := os.Open(spoolPath(dir, entry.Name))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	return writeEntry(tw, entry.File, modTime, info.Size(), f)
+}
+
+func writeEntry(tw *tar.Writer, name string, modTime time.Time, size int64, r io.Reader) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    size,
+		ModTime: modTime,
+		Format:  tar.FormatPAX,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := io.Copy(tw, r)
+	return err
+}
+
+func spoolPath(dir, collection string) string {
+	return dir + string(os.PathSeparator) + strings.ReplaceAll(collection, string(os.PathSeparator), "_") + ".jsonl"
+}
+
+// =====================
+// VERIFY
+// =====================
+
+// Verify reads the whole archive and checks it against its manifest:
+// format and version, every listed collection present exactly once, and
+// each one's document count and checksum. With collectIDs it also returns
+// every ObjectID _id in the archive.
+func Verify(path string, collectIDs bool) (*Manifest, []primitive.ObjectID, error) {
+	var ids []primitive.ObjectID
+
+	manifest, err := readArchive(path, func(entry CollectionEntry, lines *bufio.Scanner) error {
+		hash := sha256.New()
+		var count int64
+
+		for lines.Scan() {
+			line := lines.Bytes()
+			hash.Write(line)
+			hash.Write([]byte{'\n'})
+			count++
+
+			if collectIDs {
+				var doc struct {
+					ID interface{} `bson:"_id"`
+				}
+				if err := bson.UnmarshalExtJSON(line, true, &doc); err != nil {
+					return fmt.Errorf("%s line %d: %w", entry.File, count, err)
+				}
+				if id, ok := doc.ID.(primitive.ObjectID); ok {
+					ids = append(ids, id)
+				}
+			}
+		}
+		if err := lines.Err(); err != nil {
+			return fmt.Errorf("%s: %w", entry.File, err)
+		}
+
+		if count != entry.Documents {
+			return fmt.Errorf("%s: manifest lists %d documents, archive has %d", entry.File, entry.Documents, count)
+		}
+		if sum := hex.EncodeToString(hash.Sum(nil)); sum != entry.SHA256 {
+			return fmt.Errorf("%s: checksum mismatch", entry.File)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return manifest, ids, nil
+}
+
+// readArchive opens the archive, checks its manifest and calls visit for
+// every collection entry in manifest order.
+func readArchive(path string, visit func(CollectionEntry, *bufio.Scanner) error) (*Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("not a backup archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+
+	header, err := tr.Next()
+	if err != nil || header.Name != manifestName {
+		return nil, errors.New("not a backup archive: manifest.json must come first")
+	}
+
+	var manifest Manifest
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+	if manifest.Format != Format {
+		return nil, fmt.Errorf("not a backup archive: format %q", manifest.Format)
+	}
+	if manifest.Version != Version {
+		return nil, fmt.Errorf("unsupported backup version %d; this tool reads version %d", manifest.Version, Version)
+	}
+
+	for i, entry := range manifest.Collections {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("archive is truncated: %s is missing", entry.File)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Name != entry.File {
+			return nil, fmt.Errorf("entry %d is %s, manifest expects %s", i+1, header.Name, entry.File)
+		}
+
+		lines := bufio.NewScanner(tr)
+		lines.Buffer(make([]byte, 0, 64<<10), maxLineSize)
+
+		if err := visit(entry, lines); err != nil {
+			return nil, err
+		}
+	}
+
+	if header, err := tr.Next(); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("unexpected entry %s after the last collection", header.Name)
+	}
+
+	return &manifest, nil
+}
+
+// =====================
+// RESTORE
+// =====================
+
+type RestoreOptions struct {
+	// RemapIDs gives every document a new ObjectID and rewrites every
+	// reference to it, so the data can sit next to a copy of itself.
+	// Otherwise ids are kept as they are.
+	//
+	// Secrets handed out before the dump embed the workspace id, which a
+	// remap changes: login tokens, calendar feed URLs and invitation links
+	// all stop working. Calendar tokens are cleared, so users can create
+	// new ones, and pending invitations are revoked, so they can be sent
+	// again; everyone has to log in again.
+	RemapIDs bool
+}
+
+// Restore loads an archive into db, which must not have documents in any
+// of the archive's collections. The archive is verified in full before
+// anything is written, and document counts are checked afterwards. Indexes
+// are not part of the archive; create them once the restore is done.
+func Restore(ctx context.Context, db *mongo.Database, path string, opts RestoreOptions, logf func(string, ...interface{})) (*Manifest, error) {
+	manifest, ids, err := Verify(path, opts.RemapIDs)
+	if err != nil {
+		return nil, err
+	}
+	logf("archive verified: %d collections from %s taken %s", len(manifest.Collections), manifest.Database, manifest.CreatedAt.Format(time.RFC3339))
+
+	for _, entry := range manifest.Collections {
+		n, err := db.Collection(entry.Name).EstimatedDocumentCount(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			return nil, fmt.Errorf("%w: %s has %d documents", ErrNotEmpty, entry.Name, n)
+		}
+	}
+
+	// ids come in _id order per collection, so new ids keep the order.
+	var newIDs map[primitive.ObjectID]primitive.ObjectID
+	if opts.RemapIDs {
+		newIDs = make(map[primitive.ObjectID]primitive.ObjectID, len(ids))
+		for _, id := range ids {
+			newIDs[id] = primitive.NewObjectID()
+		}
+	}
+
+	restoredAt := time.Now().UTC()
+	var invalidated tokenCounts
+
+	_, err = readArchive(path, func(entry CollectionEntry, lines *bufio.Scanner) error {
+		coll := db.Collection(entry.Name)
+		batch := make([]interface{}, 0, insertBatch)
+
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			if _, err := coll.InsertMany(ctx, batch); err != nil {
+				return fmt.Errorf("restoring %s: %w", entry.Name, err)
+			}
+			batch = batch[:0]
+			return nil
+		}
+
+		for lines.Scan() {
+			var doc bson.D
+			if err := bson.UnmarshalExtJSON(lines.Bytes(), true, &doc); err != nil {
+				return fmt.Errorf("%s: %w", entry.File, err)
+			}
+			if newIDs != nil {
+				doc = remap(doc, newIDs).(bson.D)
+				doc = invalidateTokens(entry.Name, doc, restoredAt, &invalidated)
+			}
+			batch = append(batch, doc)
+			if len(batch) == insertBatch {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+		if err := lines.Err(); err != nil {
+			return err
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+
+		logf("restored %s: %d documents", entry.Name, entry.Documents)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.RemapIDs {
+		logf("ids remapped: cleared %d calendar feed tokens and revoked %d pending invitations; existing logins are no longer valid and users have to log in again",
+			invalidated.calendars, invalidated.invitations)
+	}
+
+	for _, entry := range manifest.Collections {
+		n, err := db.Collection(entry.Name).CountDocuments(ctx, bson.D{})
+		if err != nil {
+			return nil, err
+		}
+		if n != entry.Documents {
+			return nil, fmt.Errorf("%s has %d documents after restore, archive has %d", entry.Name, n, entry.Documents)
+		}
+	}
+
+	return manifest, nil
+}
+
+// tokenCounts tallies what invalidateTokens changed.
+type tokenCounts struct {
+	calendars   int
+	invitations int
+}
+
+// invalidateTokens drops what a remap leaves unusable because the secret
+// starts with the old workspace id: a user's calendar token hash, and
+// pending invitations, which are revoked at now.
+func invalidateTokens(collection string, doc bson.D, now time.Time, counts *tokenCounts) bson.D {
+	switch collection {
+	case "users":
+		for i, e := range doc {
+			if e.Key == "calendarTokenHash" {
+				counts.calendars++
+				return append(doc[:i], doc[i+1:]...)
+			}
+		}
+
+	case "invitations":
+		for _, e := range doc {
+			if e.Key == "acceptedAt" || e.Key == "revokedAt" {
+				return doc
+			}
+		}
+		counts.invitations++
+		return append(doc, bson.E{Key: "revokedAt", Value: now})
+	}
+	return doc
+}
+
+// remap replaces every ObjectID in v that is a known _id, wherever it
+// appears, so references follow the documents they point to. Ids are
+// unique across collections, which makes one map enough.
+func remap(v interface{}, ids map[primitive.ObjectID]primitive.ObjectID) interface{} {
+	switch v := v.(type) {
+	case primitive.ObjectID:
+		if id, ok := ids[v]; ok {
+			return id
+		}
+		return v
+	case bson.D:
+		for i := range v {
+			v[i].Value = remap(v[i].Value, ids)
+		}
+		return v
+	case bson.A:
+		for i := range v {
+			v[i] = remap(v[i], ids)
+		}
+		return v
+	}
+	return v
+}
@@ -0,0 +1,123 @@
+package backup
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRemap(t *testing.T) {
+	workspace, project, task := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	foreign := primitive.NewObjectID()
+
+	ids := map[primitive.ObjectID]primitive.ObjectID{
+		workspace: primitive.NewObjectID(),
+		project:   primitive.NewObjectID(),
+		task:      primitive.NewObjectID(),
+	}
+
+	doc := bson.D{
+		{Key: "_id", Value: task},
+		{Key: "workspaceId", Value: workspace},
+		{Key: "projectId", Value: project},
+		{Key: "external", Value: foreign},
+		{Key: "title", Value: "keep me"},
+		{Key: "members", Value: bson.A{
+			bson.D{{Key: "userId", Value: project}, {Key: "role", Value: "owner"}},
+			workspace,
+			"text",
+		}},
+	}
+
+	got := remap(doc, ids).(bson.D)
+
+	want := bson.D{
+		{Key: "_id", Value: ids[task]},
+		{Key: "workspaceId", Value: ids[workspace]},
+		{Key: "projectId", Value: ids[project]},
+		{Key: "external", Value: foreign},
+		{Key: "title", Value: "keep me"},
+		{Key: "members", Value: bson.A{
+			bson.D{{Key: "userId", Value: ids[project]}, {Key: "role", Value: "owner"}},
+			ids[workspace],
+			"text",
+		}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("remap =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestRemapLeavesOtherValues(t *testing.T) {
+	ids := map[primitive.ObjectID]primitive.ObjectID{primitive.NewObjectID(): primitive.NewObjectID()}
+
+	for _, v := range []interface{}{"text", int32(4), nil, primitive.NewObjectID(), bson.A{}, bson.D{}} {
+		if got := remap(v, ids); !reflect.DeepEqual(got, v) {
+			t.Errorf("remap(%v) = %v", v, got)
+		}
+	}
+}
+
+func TestInvalidateTokens(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	accepted := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name       string
+		collection string
+		in         bson.D
+		want       bson.D
+		counts     tokenCounts
+	}{
+		{
+			name:       "user with a calendar token",
+			collection: "users",
+			in:         bson.D{{Key: "name", Value: "Ada"}, {Key: "calendarTokenHash", Value: "h"}, {Key: "role", Value: "admin"}},
+			want:       bson.D{{Key: "name", Value: "Ada"}, {Key: "role", Value: "admin"}},
+			counts:     tokenCounts{calendars: 1},
+		},
+		{
+			name:       "user without one",
+			collection: "users",
+			in:         bson.D{{Key: "name", Value: "Ada"}},
+			want:       bson.D{{Key: "name", Value: "Ada"}},
+		},
+		{
+			name:       "pending invitation",
+			collection: "invitations",
+			in:         bson.D{{Key: "email", Value: "a@example.com"}},
+			want:       bson.D{{Key: "email", Value: "a@example.com"}, {Key: "revokedAt", Value: now}},
+			counts:     tokenCounts{invitations: 1},
+		},
+		{
+			name:       "accepted invitation",
+			collection: "invitations",
+			in:         bson.D{{Key: "email", Value: "a@example.com"}, {Key: "acceptedAt", Value: accepted}},
+			want:       bson.D{{Key: "email", Value: "a@example.com"}, {Key: "acceptedAt", Value: accepted}},
+		},
+		{
+			name:       "other collection",
+			collection: "tasks",
+			in:         bson.D{{Key: "calendarTokenHash", Value: "h"}},
+			want:       bson.D{{Key: "calendarTokenHash", Value: "h"}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var counts tokenCounts
+			got := invalidateTokens(c.collection, c.in, now, &counts)
+
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+			if counts != c.counts {
+				t.Errorf("counts = %+v, want %+v", counts, c.counts)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+type Config struct {
+	// TrashRetention is how long soft-deleted documents stay restorable
+	// before the purge loop removes them for good.
+	TrashRetention     time.Duration
+	TrashPurgeInterval time.Duration
+
+	// A milestone or epic is at risk when its target date is within
+	// MilestoneAtRiskWindow and less than MilestoneAtRiskPercent of its
+	// tasks are done.
+	MilestoneAtRiskWindow  time.Duration
+	MilestoneAtRiskPercent int
+
+	// Invitations expire InvitationTTL after they are sent. AppBaseURL is
+	// where links in emails point to.
+	InvitationTTL time.Duration
+	AppBaseURL    string
+
+	// Mail goes out over SMTP when SMTPHost is set and to the log otherwise.
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	MailFrom     string
+
+	// Presence on a board or task expires PresenceTTL after the client's
+	// last heartbeat.
+	PresenceTTL time.Duration
+
+	// WorkspaceSignup opens POST /workspaces to anyone. When it is off,
+	// operators create workspaces with the admin CLI.
+	WorkspaceSignup bool
+}
+
+func Load() *Config {
+	return &Config{
+		TrashRetention:         getDays("TRASH_RETENTION_DAYS", 30),
+		TrashPurgeInterval:     getDuration("TRASH_PURGE_INTERVAL", time.Hour),
+		MilestoneAtRiskWindow:  getDays("MILESTONE_AT_RISK_DAYS", 7),
+		MilestoneAtRiskPercent: getInt("MILESTONE_AT_RISK_PERCENT", 80),
+		InvitationTTL:          getDuration("INVITATION_TTL", 72*time.Hour),
+		AppBaseURL:             getString("APP_BASE_URL", "http://localhost:8080"),
+		SMTPHost:               os.Getenv("SMTP_HOST"),
+		SMTPPort:               getInt("SMTP_PORT", 587),
+		SMTPUsername:           os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:           os.Getenv("SMTP_PASSWORD"),
+		MailFrom:               getString("MAIL_FROM", "no-reply@localhost"),
+		PresenceTTL:            getDuration("PRESENCE_TTL", 45*time.Second),
+		WorkspaceSignup:        getBool("WORKSPACE_SIGNUP", false),
+	}
+}
+
+func getString(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getBool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("invalid %s %q, using %t", key, value, fallback)
+		return fallback
+	}
+
+	return parsed
+}
+
+func getInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		log.Printf("invalid %s %q, using %d", key, value, fallback)
+		return fallback
+	}
+
+	return parsed
+}
+
+func getDays(key string, fallback int) time.Duration {
+	days := fallback
+
+	if value := os.Getenv(key); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			log.Printf("invalid %s %q, using %d", key, value, fallback)
+		} else {
+			days = parsed
+		}
+	}
+
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed <= 0 {
+		log.Printf("invalid %s %q, using %s", key, value, fallback)
+		return fallback
+	}
+
+	return parsed
+}
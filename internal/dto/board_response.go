@@ -0,0 +1,15 @@
+package dto
+
+import "Concurrent_Task_Management_System/internal/models"
+
+type BoardColumn struct {
+	Status string         `json:"status"`
+	WIP    WIPUtilization `json:"wip"`
+	Tasks  []models.Task  `json:"tasks"`
+}
+
+type BoardResponse struct {
+	ProjectID   string        `json:"projectId"`
+	ProjectName string        `json:"projectName"`
+	Columns     []BoardColumn `json:"columns"`
+}
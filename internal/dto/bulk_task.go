@@ -0,0 +1,55 @@
+package dto
+
+// BulkTaskRequest is either a list of Operations or a Filter with one
+// Patch applied to every task it matches.
+type BulkTaskRequest struct {
+	Mode       string                 `json:"mode"`
+	Operations []BulkTaskOperation    `json:"operations,omitempty"`
+	Filter     *BulkTaskFilter        `json:"filter,omitempty"`
+	Patch      map[string]interface{} `json:"patch,omitempty"`
+}
+
+// BulkTaskOperation is one "update" (Patch as in PUT /tasks/{id}), "move"
+// (Status and/or ProjectID; the task goes to the bottom of the column) or
+// "delete".
+type BulkTaskOperation struct {
+	Op        string                 `json:"op"`
+	ID        string                 `json:"id"`
+	Patch     map[string]interface{} `json:"patch,omitempty"`
+	Status    string                 `json:"status,omitempty"`
+	ProjectID string                 `json:"projectId,omitempty"`
+}
+
+// BulkTaskFilter selects live tasks of one project. Empty fields match
+// anything.
+type BulkTaskFilter struct {
+	ProjectID      string `json:"projectId"`
+	Status         string `json:"status,omitempty"`
+	Priority       string `json:"priority,omitempty"`
+	Label          string `json:"label,omitempty"`
+	AssignedTo     string `json:"assignedTo,omitempty"`
+	AssignedTeamID string `json:"assignedTeamId,omitempty"`
+	SprintID       string `json:"sprintId,omitempty"`
+}
+
+type BulkTaskResult struct {
+	Index  int    `json:"index"`
+	Op     string `json:"op"`
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+const (
+	BulkResultOK         = "ok"
+	BulkResultFailed     = "failed"
+	BulkResultNotApplied = "not_applied"
+)
+
+type BulkTaskResponse struct {
+	Mode      string           `json:"mode"`
+	Applied   bool             `json:"applied"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkTaskResult `json:"results"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CalendarToken is returned once, when the token is issued; only its hash
+// is kept.
+type CalendarToken struct {
+	Token string `json:"token"`
+
+	// URLs to subscribe to. ProjectFeedURL has a {projectId} placeholder.
+	UserFeedURL    string `json:"userFeedUrl"`
+	ProjectFeedURL string `json:"projectFeedUrl"`
+}
+
+// CalendarFeed is the content of a .ics feed: tasks with a due date and
+// the names of their projects.
+type CalendarFeed struct {
+	Name     string
+	Tasks    []models.Task
+	Projects map[primitive.ObjectID]string
+}
@@ -0,0 +1,14 @@
+package dto
+
+import "Concurrent_Task_Management_System/internal/models"
+
+// DeactivationPlan lists the work a user hands over when deactivated.
+// SuccessorID and ProjectsToJoin are only set once a successor is chosen;
+// the successor joins those projects as contributor to take over tasks.
+type DeactivationPlan struct {
+	UserID         string           `json:"userId"`
+	SuccessorID    string           `json:"successorId,omitempty"`
+	OpenTasks      []models.Task    `json:"openTasks"`
+	OwnedProjects  []models.Project `json:"ownedProjects"`
+	ProjectsToJoin []string         `json:"projectsToJoin"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "Concurrent_Task_Management_System/internal/models"
+
+// MilestoneResponse is a milestone or epic with progress computed from the
+// statuses of its linked tasks. Progress is the percentage of them that
+// are Done.
+type MilestoneResponse struct {
+	*models.Milestone
+	TotalTasks int  `json:"totalTasks"`
+	DoneTasks  int  `json:"doneTasks"`
+	Progress   int  `json:"progress"`
+	AtRisk     bool `json:"atRisk"`
+}
+
+type MilestoneDetail struct {
+	MilestoneResponse
+	Tasks []models.Task `json:"tasks"`
+}
@@ -0,0 +1,49 @@
+package dto
+
+import "time"
+
+// PresenceMessage is what a client sends over the presence socket: join or
+// leave a project board or a task, or a heartbeat that keeps every joined
+// resource alive.
+type PresenceMessage struct {
+	Type    string `json:"type"`
+	Project string `json:"project,omitempty"`
+	Task    string `json:"task,omitempty"`
+	Mode    string `json:"mode,omitempty"`
+}
+
+// PresenceUser is one person on a resource. A user with the resource open
+// in several tabs is listed once, as editing if any tab is.
+type PresenceUser struct {
+	UserID string    `json:"userId"`
+	Name   string    `json:"name"`
+	Mode   string    `json:"mode"`
+	Since  time.Time `json:"since"`
+}
+
+// PresenceUpdate is sent to every client on a resource whenever the list of
+// people on it changes, and once right after joining.
+type PresenceUpdate struct {
+	Type      string         `json:"type"`
+	Resource  string         `json:"resource"`
+	ProjectID string         `json:"projectId"`
+	TaskID    string         `json:"taskId,omitempty"`
+	Users     []PresenceUser `json:"users"`
+}
+
+// PresenceHello is the first message on a presence socket and tells the
+// client how often to send heartbeats.
+type PresenceHello struct {
+	Type             string `json:"type"`
+	HeartbeatSeconds int    `json:"heartbeatSeconds"`
+	TTLSeconds       int    `json:"ttlSeconds"`
+}
+
+// PresenceNotice reports a rejected message, with the request it answers,
+// or a resource the user lost access to and was removed from.
+type PresenceNotice struct {
+	Type     string           `json:"type"`
+	Resource string           `json:"resource,omitempty"`
+	Request  *PresenceMessage `json:"request,omitempty"`
+	Error    string           `json:"error"`
+}
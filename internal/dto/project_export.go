@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProjectExport is a complete snapshot of one project. The JSON form is
+// what POST /projects/import accepts; ids are those of the exporting
+// instance and people are matched again by email on import.
+type ProjectExport struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exportedAt"`
+
+	Project    models.Project     `json:"project"`
+	Members    []ExportMember     `json:"members"`
+	Teams      []ExportTeam       `json:"teams"`
+	Milestones []models.Milestone `json:"milestones"`
+	Sprints    []models.Sprint    `json:"sprints"`
+	Tasks      []models.Task      `json:"tasks"`
+
+	// History holds the task events of the exported tasks, grouped by task
+	// and oldest first within each, when the export was asked to include it.
+	History []models.TaskEvent `json:"history,omitempty"`
+}
+
+// ExportMember is a user the project refers to. Role is the project role
+// ("owner" for the owner) and is empty for people who only appear as
+// assignees, for instance through a team.
+type ExportMember struct {
+	ID     primitive.ObjectID `json:"id"`
+	UserID string             `json:"user_id"`
+	Name   string             `json:"name"`
+	Email  string             `json:"email"`
+	Role   string             `json:"role,omitempty"`
+}
+
+// ExportTeam is a team the project refers to. Role is empty for teams that
+// only appear as task assignees.
+type ExportTeam struct {
+	ID   primitive.ObjectID `json:"id"`
+	Name string             `json:"name"`
+	Role string             `json:"role,omitempty"`
+}
+
+type ProjectImportResponse struct {
+	Project    *models.Project `json:"project"`
+	Milestones int             `json:"milestones"`
+	Sprints    int             `json:"sprints"`
+	Tasks      int             `json:"tasks"`
+	History    int             `json:"history"`
+
+	// People and teams of the export that do not exist here. Their
+	// memberships are dropped and their tasks left unassigned.
+	UnmatchedMembers []string `json:"unmatchedMembers"`
+	UnmatchedTeams   []string `json:"unmatchedTeams"`
+}
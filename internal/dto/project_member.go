@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+type ProjectMemberResponse struct {
+	ID      string    `json:"id"`
+	UserID  string    `json:"user_id"`
+	Name    string    `json:"name"`
+	Email   string    `json:"email"`
+	Role    string    `json:"role"`
+	Status  string    `json:"status"`
+	AddedAt time.Time `json:"addedAt"`
+
+	// Teams lists the project teams the member belongs to. Role is the
+	// highest of the member's direct role and those teams' roles.
+	Teams []string `json:"teams,omitempty"`
+}
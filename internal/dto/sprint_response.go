@@ -0,0 +1,35 @@
+package dto
+
+import (
+	"time"
+
+	"Concurrent_Task_Management_System/internal/models"
+)
+
+// SprintDetail is a sprint with the tasks currently in it. The sprint
+// fields are embedded so the JSON keeps the shape of models.Sprint.
+type SprintDetail struct {
+	*models.Sprint
+	Tasks []models.Task `json:"tasks"`
+}
+
+// BurndownPoint is the state of a sprint at the end of one day. Scope,
+// Completed and Remaining are omitted for days that have not happened yet;
+// Ideal is the straight line from the starting scope down to zero.
+type BurndownPoint struct {
+	Date      string  `json:"date"`
+	Scope     *int    `json:"scope,omitempty"`
+	Completed *int    `json:"completed,omitempty"`
+	Remaining *int    `json:"remaining,omitempty"`
+	Ideal     float64 `json:"ideal"`
+}
+
+type BurndownResponse struct {
+	SprintID  string          `json:"sprintId"`
+	Name      string          `json:"name"`
+	State     string          `json:"state"`
+	StartDate time.Time       `json:"startDate"`
+	EndDate   time.Time       `json:"endDate"`
+	Unit      string          `json:"unit"`
+	Points    []BurndownPoint `json:"points"`
+}
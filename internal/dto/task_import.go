@@ -0,0 +1,24 @@
+package dto
+
+import "Concurrent_Task_Management_System/internal/models"
+
+// TaskImportError is one problem found in a CSV row. Row is the line
+// number in the file, the header being line 1.
+type TaskImportError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type TaskImportResponse struct {
+	DryRun   bool              `json:"dryRun"`
+	Mapping  map[string]string `json:"mapping"`
+	Rows     int               `json:"rows"`
+	Valid    int               `json:"valid"`
+	Invalid  int               `json:"invalid"`
+	Imported int               `json:"imported"`
+	Errors   []TaskImportError `json:"errors"`
+
+	// Preview holds the first valid rows as the tasks they would become.
+	Preview []models.Task `json:"preview,omitempty"`
+}
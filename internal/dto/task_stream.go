@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"time"
+
+	"Concurrent_Task_Management_System/internal/models"
+)
+
+// TaskStreamEvent is one live update pushed to board clients. ID is sent as
+// the SSE event id; a client passes it back as Last-Event-ID to resume.
+type TaskStreamEvent struct {
+	ID            string       `json:"-"`
+	Type          string       `json:"type"`
+	ProjectID     string       `json:"projectId"`
+	TaskID        string       `json:"taskId"`
+	Task          *models.Task `json:"task"`
+	ChangedFields []string     `json:"changedFields,omitempty"`
+	At            time.Time    `json:"at"`
+}
@@ -0,0 +1,10 @@
+package dto
+
+import "Concurrent_Task_Management_System/internal/models"
+
+type TrashResponse struct {
+	RetentionDays int              `json:"retentionDays"`
+	Users         []models.User    `json:"users"`
+	Projects      []models.Project `json:"projects"`
+	Tasks         []models.Task    `json:"tasks"`
+}
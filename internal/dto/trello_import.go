@@ -0,0 +1,36 @@
+package dto
+
+import "Concurrent_Task_Management_System/internal/models"
+
+// TrelloImportMapping overrides how a board is mapped. Lists maps a list
+// name (or id) to a task status. Members maps a Trello username to the
+// email or user_id of a user here, for boards whose export has no emails.
+type TrelloImportMapping struct {
+	Lists   map[string]string `json:"lists"`
+	Members map[string]string `json:"members"`
+}
+
+// TrelloImportIssue is something on the board that was not imported, or
+// not as is. Kind is one of list, card, member, label, attachment,
+// comment or customField.
+type TrelloImportIssue struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name,omitempty"`
+	Message string `json:"message"`
+}
+
+type TrelloImportResponse struct {
+	DryRun  bool            `json:"dryRun"`
+	Project *models.Project `json:"project,omitempty"`
+
+	// Lists is the status each list was mapped to; Members the user each
+	// Trello member was matched with.
+	Lists   map[string]string `json:"lists"`
+	Members map[string]string `json:"members"`
+
+	Tasks  int                 `json:"tasks"`
+	Issues []TrelloImportIssue `json:"issues"`
+
+	// Preview holds the first tasks on a dry run.
+	Preview []models.Task `json:"preview,omitempty"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+import "Concurrent_Task_Management_System/internal/models"
+
+// WIPUtilization is how full a status column is against its WIP limits.
+// PerAssignee is keyed by user id; unassigned tasks are not listed.
+type WIPUtilization struct {
+	Status         string         `json:"status"`
+	Count          int            `json:"count"`
+	Max            int            `json:"max,omitempty"`
+	MaxPerAssignee int            `json:"maxPerAssignee,omitempty"`
+	PerAssignee    map[string]int `json:"perAssignee,omitempty"`
+}
+
+// ProjectDetail is a project with its current WIP utilization. The project
+// fields are embedded so the JSON keeps the shape of models.Project.
+type ProjectDetail struct {
+	*models.Project
+	WIPUtilization []WIPUtilization `json:"wipUtilization"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+import "Concurrent_Task_Management_System/internal/models"
+
+// WorkspaceStats summarizes one workspace for operators. Documents counts
+// raw documents per collection, trash included; the other counts only look
+// at live documents.
+type WorkspaceStats struct {
+	Workspace        models.Workspace `json:"workspace"`
+	UsersByRole      map[string]int64 `json:"usersByRole"`
+	UsersByStatus    map[string]int64 `json:"usersByStatus"`
+	ActiveProjects   int              `json:"activeProjects"`
+	ArchivedProjects int              `json:"archivedProjects"`
+	TasksByStatus    map[string]int64 `json:"tasksByStatus"`
+	Documents        map[string]int64 `json:"documents"`
+}
@@ -0,0 +1,445 @@
+// Package exporter renders project exports. JSON is the lossless form; CSV
+// holds one row per task and Markdown is a readable report.
+package exporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatMarkdown = "md"
+)
+
+// ContentTypes maps each supported format to its media type.
+var ContentTypes = map[string]string{
+	FormatJSON:     "application/json",
+	FormatCSV:      "text/csv; charset=utf-8",
+	FormatMarkdown: "text/markdown; charset=utf-8",
+}
+
+// Writer renders an export while it is being read. NewWriter writes the
+// header right away; then come the tasks column by column in board order,
+// each column announced by Column, then the history events grouped by task,
+// and finally Close.
+type Writer interface {
+	Column(status string, count int) error
+	Task(task *models.Task) error
+	Event(event *models.TaskEvent) error
+	Close() error
+}
+
+// NewWriter starts an export in the given format, which must be a key of
+// ContentTypes. header is the export without its tasks and history.
+func NewWriter(w io.Writer, format string, header *dto.ProjectExport) (Writer, error) {
+	switch format {
+	case FormatJSON:
+		return newJSONWriter(w, header)
+	case FormatCSV:
+		return newCSVWriter(w, header)
+	case FormatMarkdown:
+		return newMarkdownWriter(w, header)
+	}
+	return nil, fmt.Errorf("unknown export format %q", format)
+}
+
+// =====================
+// JSON
+// =====================
+
+// jsonWriter produces the same document as encoding a dto.ProjectExport
+// with two-space indentation, one element at a time.
+type jsonWriter struct {
+	bw      *bufio.Writer
+	tasks   int
+	history int
+}
+
+func newJSONWriter(w io.Writer, header *dto.ProjectExport) (*jsonWriter, error) {
+	jw := &jsonWriter{bw: bufio.NewWriter(w)}
+
+	jw.bw.WriteString("{")
+	fields := []struct {
+		name  string
+		value interface{}
+	}{
+		{"format", header.Format},
+		{"version", header.Version},
+		{"exportedAt", header.ExportedAt},
+		{"project", header.Project},
+		{"members", header.Members},
+		{"teams", header.Teams},
+		{"milestones", header.Milestones},
+		{"sprints", header.Sprints},
+	}
+	for _, f := range fields {
+		if err := jw.field(f.name, f.value); err != nil {
+			return nil, err
+		}
+		jw.bw.WriteString(",")
+	}
+	jw.bw.WriteString("\n  \"tasks\": [")
+
+	return jw, nil
+}
+
+func (jw *jsonWriter) field(name string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "  ", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(jw.bw, "\n  %q: %s", name, data)
+	return nil
+}
+
+func (jw *jsonWriter) element(value interface{}, first bool) error {
+	data, err := json.MarshalIndent(value, "    ", "  ")
+	if err != nil {
+		return err
+	}
+	if !first {
+		jw.bw.WriteString(",")
+	}
+	jw.bw.WriteString("\n    ")
+	_, err = jw.bw.Write(data)
+	return err
+}
+
+// endArray closes an array of n elements.
+func (jw *jsonWriter) endArray(n int) {
+	if n > 0 {
+		jw.bw.WriteString("\n  ")
+	}
+	jw.bw.WriteString("]")
+}
+
+func (jw *jsonWriter) Column(status string, count int) error { return nil }
+
+func (jw *jsonWriter) Task(task *models.Task) error {
+	jw.tasks++
+	return jw.element(task, jw.tasks == 1)
+}
+
+func (jw *jsonWriter) Event(event *models.TaskEvent) error {
+	if jw.history == 0 {
+		jw.endArray(jw.tasks)
+		jw.bw.WriteString(",\n  \"history\": [")
+	}
+	jw.history++
+	return jw.element(event, jw.history == 1)
+}
+
+// Close ends the document. Like the history field of dto.ProjectExport, an
+// empty history is left out.
+func (jw *jsonWriter) Close() error {
+	if jw.history == 0 {
+		jw.endArray(jw.tasks)
+	} else {
+		jw.endArray(jw.history)
+	}
+	jw.bw.WriteString("\n}\n")
+	return jw.bw.Flush()
+}
+
+// =====================
+// CSV
+// =====================
+
+// csvHeader is also understood by the CSV task import: the assignee column
+// holds an email, or a user_id when the user has none.
+var csvHeader = []string{
+	"id", "title", "description", "status", "priority", "labels", "dueDate",
+	"assignee", "assigneeName", "team", "sprint", "milestone", "epic",
+	"rank", "createdAt", "updatedAt",
+}
+
+// csvWriter writes one row per task. Project details and history do not
+// fit the format and are left out.
+type csvWriter struct {
+	cw    *csv.Writer
+	names *lookup
+}
+
+func newCSVWriter(w io.Writer, header *dto.ProjectExport) (*csvWriter, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return nil, err
+	}
+	return &csvWriter{cw: cw, names: newLookup(header)}, nil
+}
+
+func (c *csvWriter) Column(status string, count int) error { return nil }
+
+func (c *csvWriter) Task(t *models.Task) error {
+	member := c.names.members[t.AssignedTo]
+	assignee := member.Email
+	if assignee == "" {
+		assignee = member.UserID
+	}
+
+	return c.cw.Write([]string{
+		t.ID.Hex(),
+		t.Title,
+		t.Description,
+		t.Status,
+		t.Priority,
+		strings.Join(t.Labels, ";"),
+		formatTime(t.DueDate, time.RFC3339),
+		assignee,
+		member.Name,
+		c.names.teams[t.AssignedTeamID],
+		c.names.sprints[t.SprintID],
+		c.names.milestones[t.MilestoneID],
+		c.names.milestones[t.EpicID],
+		t.Rank,
+		formatTime(t.CreatedAt, time.RFC3339),
+		formatTime(t.UpdatedAt, time.RFC3339),
+	})
+}
+
+func (c *csvWriter) Event(event *models.TaskEvent) error { return nil }
+
+func (c *csvWriter) Close() error {
+	c.cw.Flush()
+	return c.cw.Error()
+}
+
+// =====================
+// MARKDOWN
+// =====================
+
+// markdownWriter writes a report: project details, members, teams,
+// milestones, sprints, the tasks column by column and, when the export has
+// it, each task's history.
+type markdownWriter struct {
+	bw    *bufio.Writer
+	names *lookup
+
+	// titles heads each task's history, which comes after all tasks.
+	titles    map[primitive.ObjectID]string
+	history   bool
+	lastTask  primitive.ObjectID
+	columnLen int
+}
+
+func newMarkdownWriter(w io.Writer, header *dto.ProjectExport) (*markdownWriter, error) {
+	mw := &markdownWriter{
+		bw:     bufio.NewWriter(w),
+		names:  newLookup(header),
+		titles: make(map[primitive.ObjectID]string),
+	}
+	p := mw.p
+
+	project := header.Project
+	p("# %s", mdText(project.Name))
+	p("")
+	if project.Description != "" {
+		p("%s", strings.TrimSpace(project.Description))
+		p("")
+	}
+	p("- **Owner:** %s", mw.names.person(project.OwnerID))
+	p("- **Created:** %s", formatTime(project.CreatedAt, time.DateOnly))
+	if project.ArchivedAt != nil {
+		p("- **Archived:** %s", formatTime(*project.ArchivedAt, time.DateOnly))
+	}
+	p("- **Exported:** %s", formatTime(header.ExportedAt, time.RFC3339))
+	p("")
+
+	p("## Members")
+	p("")
+	p("| Name | Email | Role |")
+	p("| --- | --- | --- |")
+	for _, m := range header.Members {
+		if m.Role == "" {
+			continue
+		}
+		p("| %s | %s | %s |", mdCell(m.Name), mdCell(m.Email), m.Role)
+	}
+	p("")
+
+	if len(header.Teams) > 0 {
+		p("## Teams")
+		p("")
+		p("| Team | Role |")
+		p("| --- | --- |")
+		for _, t := range header.Teams {
+			p("| %s | %s |", mdCell(t.Name), mdCell(t.Role))
+		}
+		p("")
+	}
+
+	if len(header.Milestones) > 0 {
+		p("## Milestones and Epics")
+		p("")
+		p("| Name | Kind | Target date |")
+		p("| --- | --- | --- |")
+		for _, m := range header.Milestones {
+			p("| %s | %s | %s |", mdCell(m.Name), m.Kind, formatTime(m.TargetDate, time.DateOnly))
+		}
+		p("")
+	}
+
+	if len(header.Sprints) > 0 {
+		p("## Sprints")
+		p("")
+		p("| Sprint | State | Start | End | Goal |")
+		p("| --- | --- | --- | --- | --- |")
+		for _, s := range header.Sprints {
+			p("| %s | %s | %s | %s | %s |",
+				mdCell(s.Name), s.State,
+				formatTime(s.StartDate, time.DateOnly), formatTime(s.EndDate, time.DateOnly),
+				mdCell(s.Goal))
+		}
+		p("")
+	}
+
+	p("## Tasks")
+	p("")
+
+	return mw, nil
+}
+
+func (mw *markdownWriter) p(format string, args ...interface{}) {
+	fmt.Fprintf(mw.bw, format+"\n", args...)
+}
+
+func (mw *markdownWriter) endColumn() {
+	if mw.columnLen > 0 {
+		mw.p("")
+	}
+}
+
+func (mw *markdownWriter) Column(status string, count int) error {
+	mw.endColumn()
+	mw.columnLen = count
+
+	mw.p("### %s (%d)", status, count)
+	mw.p("")
+	if count == 0 {
+		mw.p("_No tasks._")
+		mw.p("")
+		return nil
+	}
+	mw.p("| Title | Priority | Assignee | Due | Sprint | Labels |")
+	mw.p("| --- | --- | --- | --- | --- | --- |")
+	return nil
+}
+
+func (mw *markdownWriter) Task(t *models.Task) error {
+	mw.titles[t.ID] = t.Title
+
+	assignee := mw.names.members[t.AssignedTo].Name
+	if team := mw.names.teams[t.AssignedTeamID]; team != "" {
+		assignee = strings.TrimPrefix(assignee+", "+team, ", ")
+	}
+	mw.p("| %s | %s | %s | %s | %s | %s |",
+		mdCell(t.Title), t.Priority, mdCell(assignee),
+		formatTime(t.DueDate, time.DateOnly), mdCell(mw.names.sprints[t.SprintID]),
+		mdCell(strings.Join(t.Labels, ", ")))
+	return nil
+}
+
+func (mw *markdownWriter) Event(ev *models.TaskEvent) error {
+	if !mw.history {
+		mw.endColumn()
+		mw.columnLen = 0
+		mw.history = true
+		mw.p("## History")
+		mw.p("")
+	}
+
+	if ev.TaskID != mw.lastTask {
+		if !mw.lastTask.IsZero() {
+			mw.p("")
+		}
+		mw.lastTask = ev.TaskID
+		mw.p("### %s", mdText(mw.titles[ev.TaskID]))
+		mw.p("")
+	}
+
+	line := fmt.Sprintf("- %s: %s", formatTime(ev.At, time.RFC3339), ev.Status)
+	if sprint := mw.names.sprints[ev.SprintID]; sprint != "" {
+		line += " in " + mdText(sprint)
+	}
+	if ev.Deleted {
+		line += " (deleted)"
+	}
+	mw.p("%s", line)
+	return nil
+}
+
+func (mw *markdownWriter) Close() error {
+	if mw.history {
+		mw.p("")
+	} else {
+		mw.endColumn()
+	}
+	return mw.bw.Flush()
+}
+
+// lookup resolves the ids in an export to names.
+type lookup struct {
+	members    map[primitive.ObjectID]dto.ExportMember
+	teams      map[primitive.ObjectID]string
+	sprints    map[primitive.ObjectID]string
+	milestones map[primitive.ObjectID]string
+}
+
+func newLookup(e *dto.ProjectExport) *lookup {
+	l := &lookup{
+		members:    make(map[primitive.ObjectID]dto.ExportMember, len(e.Members)),
+		teams:      make(map[primitive.ObjectID]string, len(e.Teams)),
+		sprints:    make(map[primitive.ObjectID]string, len(e.Sprints)),
+		milestones: make(map[primitive.ObjectID]string, len(e.Milestones)),
+	}
+	for _, m := range e.Members {
+		l.members[m.ID] = m
+	}
+	for _, t := range e.Teams {
+		l.teams[t.ID] = t.Name
+	}
+	for _, s := range e.Sprints {
+		l.sprints[s.ID] = s.Name
+	}
+	for _, m := range e.Milestones {
+		l.milestones[m.ID] = m.Name
+	}
+	return l
+}
+
+func (l *lookup) person(id primitive.ObjectID) string {
+	m := l.members[id]
+	if m.Email == "" {
+		return mdText(m.Name)
+	}
+	return fmt.Sprintf("%s (%s)", mdText(m.Name), m.Email)
+}
+
+func formatTime(t time.Time, layout string) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(layout)
+}
+
+// mdText flattens free text onto one line.
+func mdText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// mdCell makes free text safe inside a table cell.
+func mdCell(s string) string {
+	return strings.ReplaceAll(mdText(s), "|", `\|`)
+}
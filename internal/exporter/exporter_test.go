@@ -0,0 +1,214 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func testExport() *dto.ProjectExport {
+	owner := dto.ExportMember{ID: primitive.NewObjectID(), UserID: "owner", Name: "Olivia Owner", Email: "olivia@example.com", Role: "owner"}
+	legacy := dto.ExportMember{ID: primitive.NewObjectID(), UserID: "legacy", Name: "Lee Legacy", Role: models.ProjectRoleContributor}
+	team := dto.ExportTeam{ID: primitive.NewObjectID(), Name: "Platform", Role: models.ProjectRoleContributor}
+	sprint := models.Sprint{ID: primitive.NewObjectID(), Name: "Sprint 1", State: models.SprintStateActive}
+	epic := models.Milestone{ID: primitive.NewObjectID(), Name: "Launch", Kind: models.MilestoneKindEpic}
+
+	created := time.Date(2026, 9, 1, 8, 0, 0, 0, time.UTC)
+	project := models.Project{ID: primitive.NewObjectID(), Name: "Website", OwnerID: owner.ID, CreatedAt: created}
+
+	todo := models.Task{
+		ID:             primitive.NewObjectID(),
+		ProjectID:      project.ID,
+		Title:          "Write copy",
+		Status:         models.TaskStatusTodo,
+		Priority:       "High",
+		Labels:         []string{"content", "seo"},
+		AssignedTo:     owner.ID,
+		AssignedTeamID: team.ID,
+		SprintID:       sprint.ID,
+		EpicID:         epic.ID,
+		Rank:           "a",
+		CreatedAt:      created,
+		UpdatedAt:      created,
+	}
+	done := models.Task{
+		ID:         primitive.NewObjectID(),
+		ProjectID:  project.ID,
+		Title:      "Pick a | domain",
+		Status:     models.TaskStatusDone,
+		Priority:   "Low",
+		AssignedTo: legacy.ID,
+		Rank:       "b",
+		CreatedAt:  created,
+		UpdatedAt:  created.Add(time.Hour),
+	}
+
+	return &dto.ProjectExport{
+		Format:     "project-export",
+		Version:    1,
+		ExportedAt: created.Add(24 * time.Hour),
+		Project:    project,
+		Members:    []dto.ExportMember{owner, legacy},
+		Teams:      []dto.ExportTeam{team},
+		Milestones: []models.Milestone{epic},
+		Sprints:    []models.Sprint{sprint},
+		Tasks:      []models.Task{todo, done},
+		History: []models.TaskEvent{
+			{TaskID: todo.ID, SprintID: sprint.ID, Status: models.TaskStatusTodo, At: created},
+			{TaskID: done.ID, Status: models.TaskStatusTodo, At: created},
+			{TaskID: done.ID, Status: models.TaskStatusDone, Deleted: true, At: created.Add(time.Hour)},
+		},
+	}
+}
+
+// render drives a Writer the way the export service does.
+func render(t *testing.T, format string, export *dto.ProjectExport) string {
+	t.Helper()
+
+	header := *export
+	header.Tasks, header.History = nil, nil
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, format, &header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, status := range models.TaskStatuses {
+		var column []models.Task
+		for _, task := range export.Tasks {
+			if task.Status == status {
+				column = append(column, task)
+			}
+		}
+		if err := w.Column(status, len(column)); err != nil {
+			t.Fatal(err)
+		}
+		for i := range column {
+			if err := w.Task(&column[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	for i := range export.History {
+		if err := w.Event(&export.History[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestJSONMatchesEncodedExport(t *testing.T) {
+	withoutHistory := testExport()
+	withoutHistory.History = nil
+
+	empty := testExport()
+	empty.Tasks, empty.History = []models.Task{}, nil
+
+	tests := []struct {
+		name   string
+		export *dto.ProjectExport
+	}{
+		{"with history", testExport()},
+		{"without history", withoutHistory},
+		{"without tasks", empty},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := json.MarshalIndent(tt.export, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := render(t, FormatJSON, tt.export); got != string(want)+"\n" {
+				t.Errorf("streamed JSON differs from the encoded export:\n%s\nwant\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestCSV(t *testing.T) {
+	export := testExport()
+
+	records, err := csv.NewReader(strings.NewReader(render(t, FormatCSV, export))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want a header and 2 tasks", len(records))
+	}
+	if !reflect.DeepEqual(records[0], csvHeader) {
+		t.Errorf("header = %v, want %v", records[0], csvHeader)
+	}
+
+	row := func(record []string) map[string]string {
+		m := make(map[string]string, len(csvHeader))
+		for i, column := range csvHeader {
+			m[column] = record[i]
+		}
+		return m
+	}
+
+	todo := row(records[1])
+	wantTodo := map[string]string{
+		"title":        "Write copy",
+		"status":       models.TaskStatusTodo,
+		"labels":       "content;seo",
+		"assignee":     "olivia@example.com",
+		"assigneeName": "Olivia Owner",
+		"team":         "Platform",
+		"sprint":       "Sprint 1",
+		"milestone":    "",
+		"epic":         "Launch",
+		"dueDate":      "",
+		"createdAt":    "2026-09-01T08:00:00Z",
+	}
+	for column, want := range wantTodo {
+		if todo[column] != want {
+			t.Errorf("%s = %q, want %q", column, todo[column], want)
+		}
+	}
+
+	// Users without an email are named by user_id.
+	if got := row(records[2])["assignee"]; got != "legacy" {
+		t.Errorf("assignee = %q, want legacy", got)
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	got := render(t, FormatMarkdown, testExport())
+
+	for _, want := range []string{
+		"# Website\n",
+		"- **Owner:** Olivia Owner (olivia@example.com)\n",
+		"| Platform | contributor |\n",
+		"### Todo (1)\n\n| Title | Priority | Assignee | Due | Sprint | Labels |\n",
+		"| Write copy | High | Olivia Owner, Platform |  | Sprint 1 | content, seo |\n",
+		"### In Progress (0)\n\n_No tasks._\n",
+		"| Pick a \\| domain | Low | Lee Legacy |",
+		"## History\n\n### Write copy\n\n- 2026-09-01T08:00:00Z: Todo in Sprint 1\n\n### Pick a | domain\n",
+		"- 2026-09-01T09:00:00Z: Done (deleted)\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("report is missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestNewWriterRejectsUnknownFormat(t *testing.T) {
+	if _, err := NewWriter(&bytes.Buffer{}, "xlsx", testExport()); err == nil {
+		t.Error("NewWriter accepted an unknown format")
+	}
+}
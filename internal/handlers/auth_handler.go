@@ -9,15 +9,20 @@ import (
 )
 
 type AuthHandler struct {
-	userService *services.UserService
+	userService      *services.UserService
+	workspaceService *services.WorkspaceService
 }
 
-func NewAuthHandler(userService *services.UserService) *AuthHandler {
-	return &AuthHandler{userService: userService}
+func NewAuthHandler(userService *services.UserService, workspaceService *services.WorkspaceService) *AuthHandler {
+	return &AuthHandler{userService: userService, workspaceService: workspaceService}
 }
 
+// LoginRequest names the workspace by slug, since user ids are only unique
+// within a workspace. An empty workspace means the default one.
 type LoginRequest struct {
-	UserID string `json:"user_id"`
+	Workspace string `json:"workspace"`
+	UserID    string `json:"user_id"`
+	Password  string `json:"password"`
 }
 
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
@@ -28,13 +33,26 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.userService.GetUserByUserID(r.Context(), req.UserID)
+	workspace, err := h.workspaceService.ResolveWorkspace(r.Context(), req.Workspace)
 	if err != nil {
 		utils.SendError(w, http.StatusUnauthorized, "Invalid credentials")
 		return
 	}
 
-	token, err := utils.GenerateJWT(user.ID.Hex(), user.Role)
+	ctx := services.WithWorkspace(r.Context(), workspace.ID)
+
+	user, err := h.userService.GetUserByUserID(ctx, req.UserID)
+	if err != nil || !h.userService.CheckPassword(user, req.Password) {
+		utils.SendError(w, http.StatusUnauthorized, "Invalid credentials")
+		return
+	}
+
+	if err := services.CheckUserActive(user); err != nil {
+		utils.SendError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	token, err := utils.GenerateJWT(user.ID.Hex(), workspace.ID.Hex(), user.Role)
 	if err != nil {
 		utils.SendError(w, http.StatusInternalServerError, "Failed to generate token")
 		return
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/services"
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// authenticate validates the Bearer token and loads the caller from the
+// database. It also scopes r's context to the workspace named in the token,
+// which every repository call made with r.Context() afterwards requires. The
+// returned error is safe to send back as a 401 description.
+func authenticate(r *http.Request, userService *services.UserService) (*models.User, error) {
+
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		return nil, errors.New("Authorization header missing")
+	}
+
+	tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
+	claims, err := utils.ParseJWT(tokenStr)
+	if err != nil {
+		return nil, errors.New("Invalid token")
+	}
+
+	workspaceID, err := primitive.ObjectIDFromHex(claims.WorkspaceID)
+	if err != nil {
+		return nil, errors.New("Invalid token")
+	}
+	*r = *r.WithContext(services.WithWorkspace(r.Context(), workspaceID))
+
+	// ✅ JWT → DB User
+	user, err := userService.GetUserByIDFromJWT(r.Context(), claims.UserID)
+	if err != nil {
+		return nil, errors.New("User not found")
+	}
+
+	if err := services.CheckUserActive(user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
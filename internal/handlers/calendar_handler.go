@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net"
+	"net/http"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/ical"
+	"Concurrent_Task_Management_System/internal/services"
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type CalendarHandler struct {
+	service     *services.CalendarService
+	userService *services.UserService
+}
+
+func NewCalendarHandler(service *services.CalendarService, userService *services.UserService) *CalendarHandler {
+	return &CalendarHandler{service: service, userService: userService}
+}
+
+// =========================
+// ISSUE FEED TOKEN
+// =========================
+func (h *CalendarHandler) IssueFeedToken(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	token, err := h.service.IssueFeedToken(r.Context(), currentUser, mux.Vars(r)["id"])
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusCreated,
+		"Calendar feed token issued successfully",
+		token,
+	)
+}
+
+// =========================
+// REVOKE FEED TOKEN
+// =========================
+func (h *CalendarHandler) RevokeFeedToken(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := h.service.RevokeFeedToken(r.Context(), currentUser, mux.Vars(r)["id"]); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Calendar feed token revoked successfully",
+		nil,
+	)
+}
+
+// =========================
+// FEEDS
+// =========================
+// Feeds are authorized by ?token= instead of a bearer header.
+func (h *CalendarHandler) UserFeed(w http.ResponseWriter, r *http.Request) {
+	feed, err := h.service.UserFeed(r.Context(), r.URL.Query().Get("token"))
+	h.sendFeed(w, r, feed, err)
+}
+
+func (h *CalendarHandler) ProjectFeed(w http.ResponseWriter, r *http.Request) {
+	feed, err := h.service.ProjectFeed(r.Context(), r.URL.Query().Get("token"), mux.Vars(r)["id"])
+	h.sendFeed(w, r, feed, err)
+}
+
+// sendFeed writes the feed as VEVENTs, or as VTODOs with ?type=todo.
+func (h *CalendarHandler) sendFeed(w http.ResponseWriter, r *http.Request, feed *dto.CalendarFeed, err error) {
+	if errors.Is(err, services.ErrFeedTokenInvalid) {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	asTodo := r.URL.Query().Get("type") == "todo"
+
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	// The URL is a credential; keep it out of Referer headers.
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.WriteHeader(http.StatusOK)
+
+	if err := ical.Write(w, feed, asTodo, host); err != nil {
+		log.Printf("calendar feed failed: %v", err)
+	}
+}
@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"net/http"
-	"strings"
 
 	"Concurrent_Task_Management_System/internal/services"
 	"Concurrent_Task_Management_System/internal/utils"
@@ -25,32 +24,18 @@ func NewDashboardHandler(
 
 func (h *DashboardHandler) GetDashboard(w http.ResponseWriter, r *http.Request) {
 
-	authHeader := r.Header.Get("Authorization")
-	if authHeader == "" {
-		utils.SendError(w, http.StatusUnauthorized, "Authorization header missing")
-		return
-	}
-
-	tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-	claims, err := utils.ParseJWT(tokenStr)
+	currentUser, err := authenticate(r, h.userService)
 	if err != nil {
-		utils.SendError(w, http.StatusUnauthorized, "Invalid token")
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
 		return
 	}
 
-	// ✅ JWT → DB User
-	currentUser, err := h.userService.GetUserByIDFromJWT(
-		r.Context(),
-		claims.UserID,
-	)
-	if err != nil {
-		utils.SendError(w, http.StatusUnauthorized, "User not found")
-		return
-	}
+	includeArchived := r.URL.Query().Get("includeArchived") == "true"
 
 	result, err := h.dashboardService.GetDashboard(
 		r.Context(),
 		currentUser,
+		includeArchived,
 	)
 	if err != nil {
 		utils.SendError(w, http.StatusForbidden, err.Error())
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"Concurrent_Task_Management_System/internal/services"
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// sendValidationError answers with 400 and the per-field errors when err is
+// a *services.ValidationError, and reports whether it did so.
+func sendValidationError(w http.ResponseWriter, err error) bool {
+	var validationErr *services.ValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+
+	utils.SendErrorWithData(w, http.StatusBadRequest, "validation failed", validationErr.Fields)
+	return true
+}
+
+// sendServiceError maps well-known service errors to their HTTP status and
+// uses fallback for everything else.
+func sendServiceError(w http.ResponseWriter, err error, fallback int) {
+	if sendValidationError(w, err) {
+		return
+	}
+
+	var wipErr *services.WIPLimitError
+	if errors.As(err, &wipErr) {
+		utils.SendErrorWithData(w, http.StatusConflict, wipErr.Error(), map[string]interface{}{
+			"status":      wipErr.Status,
+			"limit":       wipErr.Limit,
+			"perAssignee": wipErr.PerAssignee,
+		})
+		return
+	}
+
+	status := fallback
+
+	switch {
+	case errors.Is(err, services.ErrForbidden),
+		errors.Is(err, services.ErrCannotChangeOwnStatus),
+		errors.Is(err, services.ErrSignupDisabled),
+		errors.Is(err, services.ErrCannotChangeOwnRole),
+		errors.Is(err, services.ErrCannotDeleteSelf),
+		errors.Is(err, services.ErrWrongPassword),
+		errors.Is(err, services.ErrRestoreForbidden):
+		status = http.StatusForbidden
+	case errors.Is(err, mongo.ErrNoDocuments),
+		errors.Is(err, services.ErrNotInTrash),
+		errors.Is(err, services.ErrNotMember),
+		errors.Is(err, services.ErrWorkspaceNotFound),
+		errors.Is(err, services.ErrTeamNotInProject),
+		errors.Is(err, services.ErrNotTeamMember):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrAlreadyMember),
+		errors.Is(err, services.ErrOwnershipChanged),
+		errors.Is(err, services.ErrUserOwnsProjects),
+		errors.Is(err, services.ErrStaleNeighbors),
+		errors.Is(err, services.ErrSprintNotPlanned),
+		errors.Is(err, services.ErrSprintNotActive),
+		errors.Is(err, services.ErrSprintAlreadyActive),
+		errors.Is(err, services.ErrSprintClosed),
+		errors.Is(err, services.ErrProjectArchived),
+		errors.Is(err, services.ErrAlreadyArchived),
+		errors.Is(err, services.ErrNotArchived),
+		errors.Is(err, services.ErrWorkspaceSlugTaken),
+		errors.Is(err, services.ErrTeamAlreadyAdded),
+		errors.Is(err, services.ErrAlreadyTeamMember),
+		errors.Is(err, services.ErrTeamLead),
+		errors.Is(err, services.ErrAlreadyUser),
+		errors.Is(err, services.ErrUserIDTaken),
+		errors.Is(err, services.ErrSuccessorRequired),
+		errors.Is(err, services.ErrStatusUnchanged),
+		errors.Is(err, services.ErrTasksChanged),
+		errors.Is(err, services.ErrRoleUnchanged),
+		errors.Is(err, services.ErrLastSuperAdmin),
+		errors.Is(err, services.ErrSuperAdminExists):
+		status = http.StatusConflict
+	case errors.Is(err, services.ErrInvitationInvalid):
+		status = http.StatusGone
+	case errors.Is(err, services.ErrInvalidEventID):
+		status = http.StatusBadRequest
+	case errors.Is(err, services.ErrLiveUpdatesUnavailable):
+		status = http.StatusServiceUnavailable
+	}
+
+	utils.SendError(w, status, err.Error())
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/exporter"
+	"Concurrent_Task_Management_System/internal/services"
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"github.com/gorilla/mux"
+)
+
+const maxProjectImportSize = 64 << 20
+
+type ExportHandler struct {
+	service     *services.ExportService
+	userService *services.UserService
+}
+
+func NewExportHandler(service *services.ExportService, userService *services.UserService) *ExportHandler {
+	return &ExportHandler{service: service, userService: userService}
+}
+
+// =========================
+// EXPORT PROJECT
+// =========================
+// The file is written straight to the response instead of being wrapped
+// in the usual JSON envelope, so it can be saved as is and re-imported. It
+// is streamed while it is read, so large projects are never held in memory.
+func (h *ExportHandler) ExportProject(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = exporter.FormatJSON
+	}
+	contentType, ok := exporter.ContentTypes[format]
+	if !ok {
+		utils.SendError(w, http.StatusBadRequest, "format must be one of json, csv, md")
+		return
+	}
+
+	includeHistory := r.URL.Query().Get("history") == "true"
+	projectID := mux.Vars(r)["id"]
+
+	started := false
+	err = h.service.ExportProject(r.Context(), currentUser, projectID, includeHistory,
+		func(header *dto.ProjectExport) (services.ExportWriter, error) {
+			filename := fmt.Sprintf("%s-%s.%s",
+				exportFileName(header.Project.Name),
+				header.ExportedAt.UTC().Format("20060102"),
+				format,
+			)
+
+			w.Header().Set("Content-Type", contentType)
+			w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+			w.WriteHeader(http.StatusOK)
+			started = true
+
+			return exporter.NewWriter(w, format, header)
+		})
+	if err == nil {
+		return
+	}
+
+	if !started {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	// Headers are gone by now; all that is left is to cut the body short.
+	log.Printf("export of project %s failed: %v", projectID, err)
+}
+
+// =========================
+// IMPORT PROJECT
+// =========================
+// The body is a JSON export as produced above; ?name= renames the copy.
+func (h *ExportHandler) ImportProject(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxProjectImportSize)
+
+	var export dto.ProjectExport
+	if err := json.NewDecoder(r.Body).Decode(&export); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	result, err := h.service.ImportProject(r.Context(), currentUser, &export, r.URL.Query().Get("name"))
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusCreated,
+		"Project imported successfully",
+		result,
+	)
+}
+
+// =========================
+// IMPORT TRELLO BOARD
+// =========================
+// Takes the board's JSON export as the raw body, or as the "file" part of
+// a multipart form whose optional "mapping" part overrides list statuses
+// and member matches.
+func (h *ExportHandler) ImportTrelloBoard(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxProjectImportSize)
+
+	var file io.Reader = r.Body
+	var mapping dto.TrelloImportMapping
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(maxImportSize); err != nil {
+			utils.SendError(w, http.StatusBadRequest, "invalid multipart form")
+			return
+		}
+
+		part, _, err := r.FormFile("file")
+		if err != nil {
+			utils.SendError(w, http.StatusBadRequest, "file is required")
+			return
+		}
+		defer part.Close()
+		file = part
+
+		if raw := r.FormValue("mapping"); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+				utils.SendError(w, http.StatusBadRequest, "mapping must be a JSON object with lists and members")
+				return
+			}
+		}
+	}
+
+	query := r.URL.Query()
+	dryRun := query.Get("dryRun") == "true"
+
+	result, err := h.service.ImportTrelloBoard(r.Context(), currentUser, file, mapping, query.Get("name"), dryRun)
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	message := "Trello board imported successfully"
+	status := http.StatusCreated
+	if dryRun {
+		message = "Trello import preview generated"
+		status = http.StatusOK
+	}
+
+	utils.SendSuccess(w, status, message, result)
+}
+
+// exportFileName turns a project name into a safe file name.
+func exportFileName(name string) string {
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			return unicode.ToLower(r)
+		case r == '-' || r == '_':
+			return r
+		case unicode.IsSpace(r):
+			return '-'
+		}
+		return -1
+	}, name)
+
+	if slug = strings.Trim(slug, "-"); slug == "" {
+		return "project"
+	}
+	return slug
+}
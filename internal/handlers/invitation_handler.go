@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/services"
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type InvitationHandler struct {
+	service     *services.InvitationService
+	userService *services.UserService
+}
+
+func NewInvitationHandler(service *services.InvitationService, userService *services.UserService) *InvitationHandler {
+	return &InvitationHandler{service: service, userService: userService}
+}
+
+type acceptInvitationRequest struct {
+	Token    string `json:"token"`
+	UserID   string `json:"user_id"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+// =========================
+// INVITE
+// =========================
+func (h *InvitationHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var invitation models.Invitation
+	if err := json.NewDecoder(r.Body).Decode(&invitation); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	created, err := h.service.Invite(r.Context(), currentUser, &invitation)
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusCreated,
+		"Invitation sent successfully",
+		created,
+	)
+}
+
+// =========================
+// GET PENDING INVITATIONS
+// =========================
+func (h *InvitationHandler) GetPendingInvitations(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	invitations, err := h.service.GetPendingInvitations(r.Context(), currentUser)
+	if err != nil {
+		sendServiceError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Invitations fetched successfully",
+		invitations,
+	)
+}
+
+// =========================
+// REVOKE INVITATION
+// =========================
+func (h *InvitationHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	if err := h.service.RevokeInvitation(r.Context(), currentUser, id); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Invitation revoked successfully",
+		nil,
+	)
+}
+
+// =========================
+// ACCEPT INVITATION (no login)
+// =========================
+func (h *InvitationHandler) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	var req acceptInvitationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := h.service.AcceptInvitation(r.Context(), req.Token, &models.User{
+		UserID: req.UserID,
+		Name:   req.Name,
+	}, req.Password)
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	token, err := utils.GenerateJWT(user.ID.Hex(), user.WorkspaceID.Hex(), user.Role)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Failed to generate token")
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusCreated,
+		"Invitation accepted successfully",
+		map[string]interface{}{
+			"user":  user,
+			"token": token,
+		},
+	)
+}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/services"
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// sseHeartbeat is how often an idle stream sends a comment line, which keeps
+// proxies from closing it and re-checks the subscriber's access.
+const sseHeartbeat = 25 * time.Second
+
+type LiveHandler struct {
+	service     *services.LiveService
+	userService *services.UserService
+}
+
+func NewLiveHandler(service *services.LiveService, userService *services.UserService) *LiveHandler {
+	return &LiveHandler{service: service, userService: userService}
+}
+
+// =========================
+// PROJECT STREAM
+// =========================
+func (h *LiveHandler) ProjectEvents(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticateStream(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	sub, err := h.service.SubscribeProject(r.Context(), currentUser, mux.Vars(r)["id"], lastEventID(r))
+	h.stream(w, r, sub, err)
+}
+
+// =========================
+// USER STREAM
+// =========================
+func (h *LiveHandler) UserEvents(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticateStream(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	sub, err := h.service.SubscribeUser(r.Context(), currentUser, mux.Vars(r)["id"], lastEventID(r))
+	h.stream(w, r, sub, err)
+}
+
+// stream writes the subscription as Server-Sent Events until the client
+// disconnects, the subscriber loses access or the server shuts down.
+func (h *LiveHandler) stream(w http.ResponseWriter, r *http.Request, sub *services.TaskSubscription, err error) {
+	if err != nil {
+		sendServiceError(w, err, http.StatusInternalServerError)
+		return
+	}
+	defer sub.Close()
+
+	rc := http.NewResponseController(w)
+	// A stream outlives any write timeout meant for ordinary responses.
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Println("SSE write deadline error:", err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, "retry: 3000\n\n")
+	if sub.Reset {
+		// The requested event is too old to resume from; the client has to
+		// reload the board before applying further events.
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	if rc.Flush() != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case event, ok := <-sub.Events:
+			if !ok {
+				if err := sub.Err(); err != nil && !errors.Is(err, services.ErrStreamClosed) {
+					log.Println("SSE stream error:", err)
+				}
+				return
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Println("SSE encode error:", err)
+				return
+			}
+			fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+			if rc.Flush() != nil {
+				return
+			}
+
+		case <-heartbeat.C:
+			if err := sub.Check(r.Context()); err != nil {
+				data, _ := json.Marshal(map[string]string{"error": err.Error()})
+				fmt.Fprintf(w, "event: revoked\ndata: %s\n\n", data)
+				rc.Flush()
+				return
+			}
+			fmt.Fprint(w, ": ping\n\n")
+			if rc.Flush() != nil {
+				return
+			}
+		}
+	}
+}
+
+// authenticateStream accepts the JWT as ?access_token= as well, since the
+// browser EventSource and WebSocket APIs cannot send an Authorization
+// header.
+func authenticateStream(r *http.Request, userService *services.UserService) (*models.User, error) {
+	if r.Header.Get("Authorization") == "" {
+		if token := r.URL.Query().Get("access_token"); token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	return authenticate(r, userService)
+}
+
+// lastEventID is sent as a header by reconnecting EventSource clients; the
+// query parameter lets a fresh page resume from an id it stored.
+func lastEventID(r *http.Request) string {
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		return id
+	}
+	return r.URL.Query().Get("lastEventId")
+}
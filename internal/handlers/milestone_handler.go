@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/services"
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type MilestoneHandler struct {
+	service     *services.MilestoneService
+	userService *services.UserService
+}
+
+func NewMilestoneHandler(service *services.MilestoneService, userService *services.UserService) *MilestoneHandler {
+	return &MilestoneHandler{service: service, userService: userService}
+}
+
+// =========================
+// CREATE MILESTONE / EPIC
+// =========================
+func (h *MilestoneHandler) CreateMilestone(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var milestone models.Milestone
+	if err := json.NewDecoder(r.Body).Decode(&milestone); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	created, err := h.service.CreateMilestone(r.Context(), currentUser, mux.Vars(r)["id"], &milestone)
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusCreated,
+		"Milestone created successfully",
+		created,
+	)
+}
+
+// =========================
+// GET MILESTONES OF PROJECT
+// =========================
+func (h *MilestoneHandler) GetMilestones(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	kind := r.URL.Query().Get("kind")
+
+	milestones, err := h.service.GetMilestones(r.Context(), currentUser, mux.Vars(r)["id"], kind)
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Milestones fetched successfully",
+		milestones,
+	)
+}
+
+// =========================
+// GET MILESTONE
+// =========================
+func (h *MilestoneHandler) GetMilestone(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	milestone, err := h.service.GetMilestone(r.Context(), currentUser, mux.Vars(r)["id"])
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Milestone fetched successfully",
+		milestone,
+	)
+}
+
+// =========================
+// UPDATE MILESTONE
+// =========================
+func (h *MilestoneHandler) UpdateMilestone(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var updateData map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.service.UpdateMilestone(r.Context(), currentUser, mux.Vars(r)["id"], updateData); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Milestone updated successfully",
+		nil,
+	)
+}
+
+// =========================
+// DELETE MILESTONE
+// =========================
+func (h *MilestoneHandler) DeleteMilestone(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := h.service.DeleteMilestone(r.Context(), currentUser, mux.Vars(r)["id"]); err != nil {
+		sendServiceError(w, err, http.StatusNotFound)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Milestone deleted successfully",
+		nil,
+	)
+}
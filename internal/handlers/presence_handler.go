@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/services"
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"github.com/gorilla/websocket"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const presenceWriteTimeout = 10 * time.Second
+
+var presenceUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// Sockets authenticate with a token, not cookies, so a page on another
+	// origin cannot open one in the user's name.
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+type PresenceHandler struct {
+	service     *services.PresenceService
+	userService *services.UserService
+}
+
+func NewPresenceHandler(service *services.PresenceService, userService *services.UserService) *PresenceHandler {
+	return &PresenceHandler{service: service, userService: userService}
+}
+
+// =========================
+// PRESENCE SOCKET
+// =========================
+func (h *PresenceHandler) Presence(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticateStream(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	session, err := h.service.Connect(r.Context(), currentUser)
+	if err != nil {
+		sendServiceError(w, err, http.StatusInternalServerError)
+		return
+	}
+	defer session.Close()
+
+	// Upgrade answers a failed handshake itself.
+	conn, err := presenceUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	out := make(chan interface{}, 16)
+	writerDone := make(chan struct{})
+	go writePresence(conn, session, out, writerDone)
+	defer func() {
+		close(out)
+		<-writerDone
+	}()
+
+	send := func(v interface{}) {
+		select {
+		case out <- v:
+		case <-writerDone:
+		}
+	}
+
+	send(dto.PresenceHello{
+		Type:             "hello",
+		HeartbeatSeconds: int(h.service.HeartbeatInterval().Seconds()),
+		TTLSeconds:       int(h.service.TTL().Seconds()),
+	})
+
+	conn.SetReadLimit(4096)
+
+	for {
+		// A client that stops sending heartbeats is gone.
+		conn.SetReadDeadline(time.Now().Add(h.service.TTL()))
+
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var msg dto.PresenceMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			send(dto.PresenceNotice{Type: "error", Error: "invalid JSON message"})
+			continue
+		}
+
+		switch msg.Type {
+		case services.PresenceJoin:
+			err = session.Join(r.Context(), msg)
+
+		case services.PresenceLeave:
+			err = session.Leave(r.Context(), msg)
+
+		case services.PresenceHeartbeat:
+			var revoked []string
+			revoked, err = session.Heartbeat(r.Context())
+			if err != nil {
+				// The user was suspended, deactivated or removed.
+				send(dto.PresenceNotice{Type: "error", Request: &msg, Error: err.Error()})
+				return
+			}
+			for _, resource := range revoked {
+				send(dto.PresenceNotice{Type: "revoked", Resource: resource, Error: "you can no longer be present on this resource"})
+			}
+
+		default:
+			err = errors.New("unknown message type")
+		}
+
+		if err != nil {
+			send(dto.PresenceNotice{Type: "error", Request: &msg, Error: presenceErrorText(err)})
+		}
+	}
+}
+
+// writePresence is the only goroutine writing to conn. It sends replies from
+// out and presence updates from the session, and closes the socket when out
+// is closed, the server shuts down or a write fails.
+func writePresence(conn *websocket.Conn, session *services.PresenceSession, out <-chan interface{}, done chan<- struct{}) {
+	defer close(done)
+	defer conn.Close()
+
+	closeWith := func(code int, reason string) {
+		deadline := time.Now().Add(presenceWriteTimeout)
+		conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), deadline)
+	}
+
+	for {
+		var v interface{}
+
+		select {
+		case reply, ok := <-out:
+			if !ok {
+				closeWith(websocket.CloseNormalClosure, "")
+				return
+			}
+			v = reply
+
+		case update, ok := <-session.Updates:
+			if !ok {
+				closeWith(websocket.CloseGoingAway, services.ErrStreamClosed.Error())
+				return
+			}
+			v = update
+		}
+
+		conn.SetWriteDeadline(time.Now().Add(presenceWriteTimeout))
+		if err := conn.WriteJSON(v); err != nil {
+			return
+		}
+	}
+}
+
+func presenceErrorText(err error) string {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "project or task not found"
+	}
+	return err.Error()
+}
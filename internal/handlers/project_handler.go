@@ -12,17 +12,23 @@ import (
 )
 
 type ProjectHandler struct {
-	service *services.ProjectService
+	service     *services.ProjectService
+	userService *services.UserService
 }
 
-func NewProjectHandler(service *services.ProjectService) *ProjectHandler {
-	return &ProjectHandler{service: service}
+func NewProjectHandler(service *services.ProjectService, userService *services.UserService) *ProjectHandler {
+	return &ProjectHandler{service: service, userService: userService}
 }
 
 // =========================
 // CREATE PROJECT
 // =========================
 func (h *ProjectHandler) CreateProject(w http.ResponseWriter, r *http.Request) {
+	if _, err := authenticate(r, h.userService); err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
 	var project models.Project
 
 	if err := json.NewDecoder(r.Body).Decode(&project); err != nil {
@@ -32,6 +38,9 @@ func (h *ProjectHandler) CreateProject(w http.ResponseWriter, r *http.Request) {
 
 	createdProject, err := h.service.CreateProject(r.Context(), &project)
 	if err != nil {
+		if sendValidationError(w, err) {
+			return
+		}
 		utils.SendError(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -48,11 +57,17 @@ func (h *ProjectHandler) CreateProject(w http.ResponseWriter, r *http.Request) {
 // GET PROJECT BY ID
 // =========================
 func (h *ProjectHandler) GetProjectByID(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
 	id := mux.Vars(r)["id"]
 
-	project, err := h.service.GetProjectByID(r.Context(), id)
+	project, err := h.service.GetProjectByID(r.Context(), currentUser, id)
 	if err != nil {
-		utils.SendError(w, http.StatusNotFound, err.Error())
+		sendServiceError(w, err, http.StatusNotFound)
 		return
 	}
 
@@ -68,7 +83,14 @@ func (h *ProjectHandler) GetProjectByID(w http.ResponseWriter, r *http.Request)
 // GET ALL PROJECTS
 // =========================
 func (h *ProjectHandler) GetAllProjects(w http.ResponseWriter, r *http.Request) {
-	projects, err := h.service.GetAllProjects(r.Context())
+	if _, err := authenticate(r, h.userService); err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	includeArchived := r.URL.Query().Get("includeArchived") == "true"
+
+	projects, err := h.service.GetAllProjects(r.Context(), includeArchived)
 	if err != nil {
 		utils.SendError(w, http.StatusInternalServerError, "failed to fetch projects")
 		return
@@ -86,9 +108,17 @@ func (h *ProjectHandler) GetAllProjects(w http.ResponseWriter, r *http.Request)
 // GET PROJECTS BY USER
 // =========================
 func (h *ProjectHandler) GetProjectsByUser(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
 	userID := mux.Vars(r)["userId"]
 
-	projects, err := h.service.GetProjectsByUser(r.Context(), userID)
+	includeArchived := r.URL.Query().Get("includeArchived") == "true"
+
+	projects, err := h.service.GetProjectsByUser(r.Context(), currentUser, userID, includeArchived)
 	if err != nil {
 		utils.SendError(w, http.StatusBadRequest, err.Error())
 		return
@@ -106,6 +136,12 @@ func (h *ProjectHandler) GetProjectsByUser(w http.ResponseWriter, r *http.Reques
 // UPDATE PROJECT
 // =========================
 func (h *ProjectHandler) UpdateProject(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
 	id := mux.Vars(r)["id"]
 
 	var updateData map[string]interface{}
@@ -114,8 +150,8 @@ func (h *ProjectHandler) UpdateProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.UpdateProject(r.Context(), id, updateData); err != nil {
-		utils.SendError(w, http.StatusBadRequest, err.Error())
+	if err := h.service.UpdateProject(r.Context(), currentUser, id, updateData); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
 		return
 	}
 
@@ -127,21 +163,104 @@ func (h *ProjectHandler) UpdateProject(w http.ResponseWriter, r *http.Request) {
 	)
 }
 
+// =========================
+// ARCHIVE / UNARCHIVE PROJECT
+// =========================
+func (h *ProjectHandler) ArchiveProject(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := h.service.ArchiveProject(r.Context(), currentUser, mux.Vars(r)["id"]); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Project archived successfully",
+		nil,
+	)
+}
+
+func (h *ProjectHandler) UnarchiveProject(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := h.service.UnarchiveProject(r.Context(), currentUser, mux.Vars(r)["id"]); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Project unarchived successfully",
+		nil,
+	)
+}
+
 // =========================
 // DELETE PROJECT
 // =========================
 func (h *ProjectHandler) DeleteProject(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
 	id := mux.Vars(r)["id"]
 
-	if err := h.service.DeleteProject(r.Context(), id); err != nil {
-		utils.SendError(w, http.StatusNotFound, err.Error())
+	if err := h.service.DeleteProject(r.Context(), currentUser, id); err != nil {
+		sendServiceError(w, err, http.StatusNotFound)
 		return
 	}
 
 	utils.SendSuccess(
 		w,
 		http.StatusOK,
-		"Project deleted successfully",
+		"Project moved to trash",
 		nil,
 	)
 }
+
+// =========================
+// SET WIP LIMITS
+// =========================
+func (h *ProjectHandler) SetWIPLimits(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	var body struct {
+		WIPLimits []models.WIPLimit `json:"wipLimits"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	project, err := h.service.SetWIPLimits(r.Context(), currentUser, id, body.WIPLimits)
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"WIP limits updated successfully",
+		project,
+	)
+}
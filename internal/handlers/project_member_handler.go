@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type memberRequest struct {
+	UserID string `json:"userId"`
+	Role   string `json:"role"`
+}
+
+type transferOwnershipRequest struct {
+	NewOwnerID string `json:"newOwnerId"`
+}
+
+// =========================
+// GET MEMBERS
+// =========================
+func (h *ProjectHandler) GetMembers(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	assignable := r.URL.Query().Get("assignable") == "true"
+
+	members, err := h.service.GetMembers(r.Context(), currentUser, mux.Vars(r)["id"], assignable)
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Members fetched successfully",
+		members,
+	)
+}
+
+// =========================
+// ADD MEMBER
+// =========================
+func (h *ProjectHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var req memberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.service.AddMember(r.Context(), currentUser, mux.Vars(r)["id"], req.UserID, req.Role); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusCreated,
+		"Member added successfully",
+		nil,
+	)
+}
+
+// =========================
+// UPDATE MEMBER ROLE
+// =========================
+func (h *ProjectHandler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var req memberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	if err := h.service.UpdateMemberRole(r.Context(), currentUser, vars["id"], vars["userId"], req.Role); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Member role updated successfully",
+		nil,
+	)
+}
+
+// =========================
+// REMOVE MEMBER
+// =========================
+func (h *ProjectHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	if err := h.service.RemoveMember(r.Context(), currentUser, vars["id"], vars["userId"]); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Member removed successfully",
+		nil,
+	)
+}
+
+// =========================
+// TRANSFER OWNERSHIP
+// =========================
+func (h *ProjectHandler) TransferOwnership(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var req transferOwnershipRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.service.TransferOwnership(r.Context(), currentUser, mux.Vars(r)["id"], req.NewOwnerID); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Ownership transferred successfully",
+		nil,
+	)
+}
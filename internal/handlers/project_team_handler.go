@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type projectTeamRequest struct {
+	TeamID string `json:"teamId"`
+	Role   string `json:"role"`
+}
+
+// =========================
+// ADD TEAM
+// =========================
+func (h *ProjectHandler) AddTeam(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var req projectTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.service.AddTeam(r.Context(), currentUser, mux.Vars(r)["id"], req.TeamID, req.Role); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusCreated,
+		"Team added successfully",
+		nil,
+	)
+}
+
+// =========================
+// UPDATE TEAM ROLE
+// =========================
+func (h *ProjectHandler) UpdateTeamRole(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var req projectTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	if err := h.service.UpdateTeamRole(r.Context(), currentUser, vars["id"], vars["teamId"], req.Role); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Team role updated successfully",
+		nil,
+	)
+}
+
+// =========================
+// REMOVE TEAM
+// =========================
+func (h *ProjectHandler) RemoveTeam(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	if err := h.service.RemoveTeam(r.Context(), currentUser, vars["id"], vars["teamId"]); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Team removed successfully",
+		nil,
+	)
+}
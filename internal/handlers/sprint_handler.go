@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/services"
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type SprintHandler struct {
+	service     *services.SprintService
+	userService *services.UserService
+}
+
+func NewSprintHandler(service *services.SprintService, userService *services.UserService) *SprintHandler {
+	return &SprintHandler{service: service, userService: userService}
+}
+
+type closeSprintRequest struct {
+	CarryOverTo string `json:"carryOverTo"`
+}
+
+// =========================
+// CREATE SPRINT
+// =========================
+func (h *SprintHandler) CreateSprint(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var sprint models.Sprint
+	if err := json.NewDecoder(r.Body).Decode(&sprint); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	created, err := h.service.CreateSprint(r.Context(), currentUser, mux.Vars(r)["id"], &sprint)
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusCreated,
+		"Sprint created successfully",
+		created,
+	)
+}
+
+// =========================
+// GET SPRINTS OF PROJECT
+// =========================
+func (h *SprintHandler) GetSprints(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	sprints, err := h.service.GetSprints(r.Context(), currentUser, mux.Vars(r)["id"])
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Sprints fetched successfully",
+		sprints,
+	)
+}
+
+// =========================
+// GET SPRINT
+// =========================
+func (h *SprintHandler) GetSprint(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	sprint, err := h.service.GetSprint(r.Context(), currentUser, mux.Vars(r)["id"])
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Sprint fetched successfully",
+		sprint,
+	)
+}
+
+// =========================
+// UPDATE SPRINT
+// =========================
+func (h *SprintHandler) UpdateSprint(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var updateData map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.service.UpdateSprint(r.Context(), currentUser, mux.Vars(r)["id"], updateData); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Sprint updated successfully",
+		nil,
+	)
+}
+
+// =========================
+// START SPRINT
+// =========================
+func (h *SprintHandler) StartSprint(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	sprint, err := h.service.StartSprint(r.Context(), currentUser, mux.Vars(r)["id"])
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Sprint started successfully",
+		sprint,
+	)
+}
+
+// =========================
+// CLOSE SPRINT
+// =========================
+func (h *SprintHandler) CloseSprint(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	// The body is optional; without it unfinished tasks go back to the backlog.
+	var req closeSprintRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			utils.SendError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	sprint, err := h.service.CloseSprint(r.Context(), currentUser, mux.Vars(r)["id"], req.CarryOverTo)
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Sprint closed successfully",
+		sprint,
+	)
+}
+
+// =========================
+// GET BURNDOWN
+// =========================
+func (h *SprintHandler) GetBurndown(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	burndown, err := h.service.GetBurndown(r.Context(), currentUser, mux.Vars(r)["id"])
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Burndown fetched successfully",
+		burndown,
+	)
+}
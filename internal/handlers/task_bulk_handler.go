@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/services"
+	"Concurrent_Task_Management_System/internal/utils"
+)
+
+// =========================
+// BULK TASKS
+// =========================
+func (h *TaskHandler) BulkTasks(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var req dto.BulkTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	result, err := h.service.BulkTasks(r.Context(), currentUser, &req)
+	if errors.Is(err, services.ErrBulkRejected) {
+		utils.SendErrorWithData(w, http.StatusBadRequest, err.Error(), result)
+		return
+	}
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Bulk operation completed",
+		result,
+	)
+}
@@ -13,15 +13,22 @@ import (
 )
 
 type TaskHandler struct {
-	service *services.TaskService
+	service     *services.TaskService
+	userService *services.UserService
 }
 
-func NewTaskHandler(service *services.TaskService) *TaskHandler {
-	return &TaskHandler{service: service}
+func NewTaskHandler(service *services.TaskService, userService *services.UserService) *TaskHandler {
+	return &TaskHandler{service: service, userService: userService}
 }
 
 // CREATE TASK
 func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
 	var task models.Task
 
 	if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
@@ -29,9 +36,9 @@ func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	createdTask, err := h.service.CreateTask(r.Context(), &task)
+	createdTask, err := h.service.CreateTask(r.Context(), currentUser, &task)
 	if err != nil {
-		utils.SendError(w, http.StatusBadRequest, err.Error())
+		sendServiceError(w, err, http.StatusBadRequest)
 		return
 	}
 
@@ -46,6 +53,12 @@ func (h *TaskHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
 // GET TASK BY ID
 
 func (h *TaskHandler) GetTaskByID(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
 	idStr := mux.Vars(r)["id"]
 
 	id, err := primitive.ObjectIDFromHex(idStr)
@@ -54,9 +67,9 @@ func (h *TaskHandler) GetTaskByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	task, err := h.service.GetTaskByID(r.Context(), id)
+	task, err := h.service.GetTaskByID(r.Context(), currentUser, id)
 	if err != nil {
-		utils.SendError(w, http.StatusNotFound, err.Error())
+		sendServiceError(w, err, http.StatusNotFound)
 		return
 	}
 
@@ -70,7 +83,13 @@ func (h *TaskHandler) GetTaskByID(w http.ResponseWriter, r *http.Request) {
 
 // GET ALL TASKS
 func (h *TaskHandler) GetAllTasks(w http.ResponseWriter, r *http.Request) {
-	tasks, err := h.service.GetAllTasks(r.Context())
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	tasks, err := h.service.GetAllTasks(r.Context(), currentUser)
 	if err != nil {
 		utils.SendError(w, http.StatusInternalServerError, "failed to fetch tasks")
 		return
@@ -86,6 +105,12 @@ func (h *TaskHandler) GetAllTasks(w http.ResponseWriter, r *http.Request) {
 
 // GET TASKS BY PROJECT
 func (h *TaskHandler) GetTasksByProject(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
 	projectIDStr := mux.Vars(r)["projectId"]
 
 	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
@@ -94,9 +119,9 @@ func (h *TaskHandler) GetTasksByProject(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	tasks, err := h.service.GetTasksByProject(r.Context(), projectID)
+	tasks, err := h.service.GetTasksByProject(r.Context(), currentUser, projectID)
 	if err != nil {
-		utils.SendError(w, http.StatusBadRequest, err.Error())
+		sendServiceError(w, err, http.StatusBadRequest)
 		return
 	}
 
@@ -110,6 +135,12 @@ func (h *TaskHandler) GetTasksByProject(w http.ResponseWriter, r *http.Request)
 
 // GET TASKS BY ASSIGNED USER
 func (h *TaskHandler) GetTasksByAssignedUser(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
 	userIDStr := mux.Vars(r)["userId"]
 
 	userID, err := primitive.ObjectIDFromHex(userIDStr)
@@ -118,7 +149,7 @@ func (h *TaskHandler) GetTasksByAssignedUser(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	tasks, err := h.service.GetTasksByAssignedUser(r.Context(), userID)
+	tasks, err := h.service.GetTasksByAssignedUser(r.Context(), currentUser, userID)
 	if err != nil {
 		utils.SendError(w, http.StatusBadRequest, err.Error())
 		return
@@ -134,9 +165,15 @@ func (h *TaskHandler) GetTasksByAssignedUser(w http.ResponseWriter, r *http.Requ
 
 // GET TASKS BY STATUS
 func (h *TaskHandler) GetTasksByStatus(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
 	status := mux.Vars(r)["status"]
 
-	tasks, err := h.service.GetTasksByStatus(r.Context(), status)
+	tasks, err := h.service.GetTasksByStatus(r.Context(), currentUser, status)
 	if err != nil {
 		utils.SendError(w, http.StatusBadRequest, err.Error())
 		return
@@ -152,6 +189,12 @@ func (h *TaskHandler) GetTasksByStatus(w http.ResponseWriter, r *http.Request) {
 
 // UPDATE TASK
 func (h *TaskHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
 	idStr := mux.Vars(r)["id"]
 
 	id, err := primitive.ObjectIDFromHex(idStr)
@@ -166,8 +209,8 @@ func (h *TaskHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.UpdateTask(r.Context(), id, updateData); err != nil {
-		utils.SendError(w, http.StatusBadRequest, err.Error())
+	if err := h.service.UpdateTask(r.Context(), currentUser, id, updateData); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
 		return
 	}
 
@@ -181,6 +224,12 @@ func (h *TaskHandler) UpdateTask(w http.ResponseWriter, r *http.Request) {
 
 // DELETE TASK
 func (h *TaskHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
 	idStr := mux.Vars(r)["id"]
 
 	id, err := primitive.ObjectIDFromHex(idStr)
@@ -189,15 +238,83 @@ func (h *TaskHandler) DeleteTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.service.DeleteTask(r.Context(), id); err != nil {
-		utils.SendError(w, http.StatusNotFound, err.Error())
+	if err := h.service.DeleteTask(r.Context(), currentUser, id); err != nil {
+		sendServiceError(w, err, http.StatusNotFound)
 		return
 	}
 
 	utils.SendSuccess(
 		w,
 		http.StatusOK,
-		"Task deleted successfully",
+		"Task moved to trash",
 		nil,
 	)
 }
+
+type moveTaskRequest struct {
+	Status     string `json:"status"`
+	PrevTaskID string `json:"prevTaskId"`
+	NextTaskID string `json:"nextTaskId"`
+}
+
+// MOVE TASK
+func (h *TaskHandler) MoveTask(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid task id")
+		return
+	}
+
+	var req moveTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	task, err := h.service.MoveTask(r.Context(), currentUser, id, req.Status, req.PrevTaskID, req.NextTaskID)
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Task moved successfully",
+		task,
+	)
+}
+
+// GET PROJECT BOARD
+func (h *TaskHandler) GetBoard(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	projectID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid project id")
+		return
+	}
+
+	board, err := h.service.GetBoard(r.Context(), currentUser, projectID)
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Board fetched successfully",
+		board,
+	)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"Concurrent_Task_Management_System/internal/services"
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const maxImportSize = 10 << 20
+
+// =========================
+// IMPORT TASKS (CSV)
+// =========================
+// The CSV comes either as the raw request body (Content-Type: text/csv) or
+// as the "file" part of a multipart form, whose optional "mapping" part is
+// a JSON object from task field to column header.
+func (h *TaskHandler) ImportTasks(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	projectID, err := primitive.ObjectIDFromHex(mux.Vars(r)["id"])
+	if err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid project id")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	var file io.Reader = r.Body
+	var mapping map[string]string
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(maxImportSize); err != nil {
+			utils.SendError(w, http.StatusBadRequest, "invalid multipart form")
+			return
+		}
+
+		part, _, err := r.FormFile("file")
+		if err != nil {
+			utils.SendError(w, http.StatusBadRequest, "file is required")
+			return
+		}
+		defer part.Close()
+		file = part
+
+		if raw := r.FormValue("mapping"); raw != "" {
+			if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+				utils.SendError(w, http.StatusBadRequest, "mapping must be a JSON object of field to column")
+				return
+			}
+		}
+	}
+
+	dryRun := r.URL.Query().Get("dryRun") == "true"
+
+	result, err := h.service.ImportTasks(r.Context(), currentUser, projectID, file, mapping, dryRun)
+	if errors.Is(err, services.ErrImportInvalid) {
+		utils.SendErrorWithData(w, http.StatusBadRequest, err.Error(), result)
+		return
+	}
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	message := "Tasks imported successfully"
+	status := http.StatusCreated
+	if dryRun {
+		message = "Import preview generated"
+		status = http.StatusOK
+	}
+
+	utils.SendSuccess(w, status, message, result)
+}
@@ -0,0 +1,230 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/services"
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type TeamHandler struct {
+	service     *services.TeamService
+	userService *services.UserService
+}
+
+func NewTeamHandler(service *services.TeamService, userService *services.UserService) *TeamHandler {
+	return &TeamHandler{service: service, userService: userService}
+}
+
+type teamMemberRequest struct {
+	UserID string `json:"userId"`
+}
+
+// =========================
+// CREATE TEAM
+// =========================
+func (h *TeamHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var team models.Team
+	if err := json.NewDecoder(r.Body).Decode(&team); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	created, err := h.service.CreateTeam(r.Context(), currentUser, &team)
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusCreated,
+		"Team created successfully",
+		created,
+	)
+}
+
+// =========================
+// GET TEAMS
+// =========================
+func (h *TeamHandler) GetTeams(w http.ResponseWriter, r *http.Request) {
+	if _, err := authenticate(r, h.userService); err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	teams, err := h.service.GetTeams(r.Context())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "failed to fetch teams")
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Teams fetched successfully",
+		teams,
+	)
+}
+
+// =========================
+// GET TEAM
+// =========================
+func (h *TeamHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
+	if _, err := authenticate(r, h.userService); err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	team, err := h.service.GetTeam(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Team fetched successfully",
+		team,
+	)
+}
+
+// =========================
+// TEAM QUEUE
+// =========================
+func (h *TeamHandler) GetTeamQueue(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+
+	tasks, err := h.service.GetTeamQueue(r.Context(), currentUser, mux.Vars(r)["id"], status)
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Team queue fetched successfully",
+		tasks,
+	)
+}
+
+// =========================
+// UPDATE TEAM
+// =========================
+func (h *TeamHandler) UpdateTeam(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var updateData map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.service.UpdateTeam(r.Context(), currentUser, mux.Vars(r)["id"], updateData); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Team updated successfully",
+		nil,
+	)
+}
+
+// =========================
+// DELETE TEAM
+// =========================
+func (h *TeamHandler) DeleteTeam(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := h.service.DeleteTeam(r.Context(), currentUser, mux.Vars(r)["id"]); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Team deleted successfully",
+		nil,
+	)
+}
+
+// =========================
+// ADD / REMOVE MEMBER
+// =========================
+func (h *TeamHandler) AddMember(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var req teamMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.service.AddMember(r.Context(), currentUser, mux.Vars(r)["id"], req.UserID); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusCreated,
+		"Team member added successfully",
+		nil,
+	)
+}
+
+func (h *TeamHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	vars := mux.Vars(r)
+
+	if err := h.service.RemoveMember(r.Context(), currentUser, vars["id"], vars["userId"]); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Team member removed successfully",
+		nil,
+	)
+}
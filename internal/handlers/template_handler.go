@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/services"
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type TemplateHandler struct {
+	service     *services.TemplateService
+	userService *services.UserService
+}
+
+func NewTemplateHandler(service *services.TemplateService, userService *services.UserService) *TemplateHandler {
+	return &TemplateHandler{service: service, userService: userService}
+}
+
+type saveTemplateRequest struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	StartDate   time.Time `json:"startDate"`
+}
+
+type instantiateTemplateRequest struct {
+	models.Project
+	StartDate time.Time `json:"startDate"`
+}
+
+type cloneProjectRequest struct {
+	Name           string `json:"name"`
+	OwnerID        string `json:"ownerId"`
+	IncludeMembers *bool  `json:"includeMembers"`
+}
+
+// =========================
+// SAVE PROJECT AS TEMPLATE
+// =========================
+func (h *TemplateHandler) SaveTemplate(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var req saveTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	template, err := h.service.SaveTemplate(
+		r.Context(),
+		currentUser,
+		mux.Vars(r)["id"],
+		req.Name,
+		req.Description,
+		req.StartDate,
+	)
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusCreated,
+		"Template saved successfully",
+		template,
+	)
+}
+
+// =========================
+// GET TEMPLATES
+// =========================
+func (h *TemplateHandler) GetTemplates(w http.ResponseWriter, r *http.Request) {
+	if _, err := authenticate(r, h.userService); err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	templates, err := h.service.GetTemplates(r.Context())
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "failed to fetch templates")
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Templates fetched successfully",
+		templates,
+	)
+}
+
+// =========================
+// GET TEMPLATE
+// =========================
+func (h *TemplateHandler) GetTemplate(w http.ResponseWriter, r *http.Request) {
+	if _, err := authenticate(r, h.userService); err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	template, err := h.service.GetTemplate(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Template fetched successfully",
+		template,
+	)
+}
+
+// =========================
+// DELETE TEMPLATE
+// =========================
+func (h *TemplateHandler) DeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := h.service.DeleteTemplate(r.Context(), currentUser, mux.Vars(r)["id"]); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Template deleted successfully",
+		nil,
+	)
+}
+
+// =========================
+// CREATE PROJECT FROM TEMPLATE
+// =========================
+func (h *TemplateHandler) InstantiateTemplate(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var req instantiateTemplateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	project, err := h.service.InstantiateTemplate(
+		r.Context(),
+		currentUser,
+		mux.Vars(r)["id"],
+		&req.Project,
+		req.StartDate,
+	)
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusCreated,
+		"Project created from template successfully",
+		project,
+	)
+}
+
+// =========================
+// CLONE PROJECT
+// =========================
+func (h *TemplateHandler) CloneProject(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var req cloneProjectRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			utils.SendError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	includeMembers := req.IncludeMembers == nil || *req.IncludeMembers
+
+	project, err := h.service.CloneProject(
+		r.Context(),
+		currentUser,
+		mux.Vars(r)["id"],
+		req.Name,
+		req.OwnerID,
+		includeMembers,
+	)
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusCreated,
+		"Project cloned successfully",
+		project,
+	)
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/services"
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type TrashHandler struct {
+	service     *services.TrashService
+	userService *services.UserService
+}
+
+func NewTrashHandler(service *services.TrashService, userService *services.UserService) *TrashHandler {
+	return &TrashHandler{service: service, userService: userService}
+}
+
+// =========================
+// GET TRASH
+// =========================
+func (h *TrashHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	trash, err := h.service.GetTrash(r.Context(), currentUser)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "failed to fetch trash")
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Trash fetched successfully",
+		trash,
+	)
+}
+
+// =========================
+// RESTORE
+// =========================
+func (h *TrashHandler) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	h.restore(w, r, "User restored successfully", h.service.RestoreUser)
+}
+
+func (h *TrashHandler) RestoreProject(w http.ResponseWriter, r *http.Request) {
+	h.restore(w, r, "Project restored successfully", h.service.RestoreProject)
+}
+
+func (h *TrashHandler) RestoreTask(w http.ResponseWriter, r *http.Request) {
+	h.restore(w, r, "Task restored successfully", h.service.RestoreTask)
+}
+
+func (h *TrashHandler) restore(
+	w http.ResponseWriter,
+	r *http.Request,
+	message string,
+	restoreFn func(ctx context.Context, currentUser *models.User, id string) error,
+) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+
+	if err := restoreFn(r.Context(), currentUser, id); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(w, http.StatusOK, message, nil)
+}
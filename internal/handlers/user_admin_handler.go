@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type changeRoleRequest struct {
+	Role string `json:"role"`
+}
+
+type setPasswordRequest struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+}
+
+// =========================
+// CHANGE ROLE
+// =========================
+func (h *UserHandler) ChangeRole(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.service)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var req changeRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := h.service.ChangeRole(r.Context(), currentUser, mux.Vars(r)["id"], req.Role)
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Role changed successfully",
+		user,
+	)
+}
+
+// =========================
+// SET PASSWORD
+// =========================
+func (h *UserHandler) SetPassword(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.service)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var req setPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	err = h.service.SetPassword(r.Context(), currentUser, mux.Vars(r)["id"], req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Password changed successfully",
+		nil,
+	)
+}
@@ -21,17 +21,29 @@ func NewUserHandler(service *services.UserService) *UserHandler {
 	}
 }
 
+// createUserRequest is a user plus the password they will log in with.
+type createUserRequest struct {
+	models.User
+	Password string `json:"password"`
+}
+
 func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
-	var user models.User
+	currentUser, err := authenticate(r, h.service)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var req createUserRequest
 
-	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		utils.SendError(w,http.StatusBadRequest,err.Error())
 		return
 	}
 
-	createdUser, err := h.service.CreateUser(r.Context(), &user)
+	createdUser, err := h.service.CreateUser(r.Context(), currentUser, &req.User, req.Password)
 	if err != nil {
-		utils.SendError(w, http.StatusBadRequest, err.Error())
+		sendServiceError(w, err, http.StatusBadRequest)
 
 		return
 	}
@@ -48,6 +60,11 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *UserHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
+	if _, err := authenticate(r, h.service); err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
 	params := mux.Vars(r)
 	id := params["id"]
 
@@ -68,9 +85,20 @@ func (h *UserHandler) GetUserByID(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *UserHandler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
-	users, err := h.service.GetAllUsers(r.Context())
+	_, err := authenticate(r, h.service)
 	if err != nil {
-		utils.SendError(w,http.StatusInternalServerError,err.Error())
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var users []models.User
+	if status := r.URL.Query().Get("status"); status != "" {
+		users, err = h.service.GetUsersByStatus(r.Context(), status)
+	} else {
+		users, err = h.service.GetAllUsers(r.Context())
+	}
+	if err != nil {
+		sendServiceError(w, err, http.StatusInternalServerError)
 		return
 	}
 
@@ -85,6 +113,12 @@ func (h *UserHandler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.service)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
 	params := mux.Vars(r)
 	id := params["id"]
 
@@ -95,9 +129,9 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.service.UpdateUser(r.Context(), id, updateData)
+	err = h.service.UpdateUser(r.Context(), currentUser, id, updateData)
 	if err != nil {
-		utils.SendError(w, http.StatusBadRequest, err.Error())
+		sendServiceError(w, err, http.StatusBadRequest)
 
 		return
 	}
@@ -106,12 +140,20 @@ func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.service)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
 	params := mux.Vars(r)
 	id := params["id"]
 
-	err := h.service.DeleteUser(r.Context(), id)
+	transferTo := r.URL.Query().Get("transferTo")
+
+	err = h.service.DeleteUser(r.Context(), currentUser, id, transferTo)
 	if err != nil {
-		utils.SendError(w, http.StatusNotFound, err.Error())
+		sendServiceError(w, err, http.StatusNotFound)
 		return
 	}
 
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/repositories"
+	"Concurrent_Task_Management_System/internal/services"
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"github.com/gorilla/mux"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// fakeUserRepository keeps users in memory. Methods the tests do not reach
+// are left to the embedded nil interface and panic if called.
+type fakeUserRepository struct {
+	repositories.UserRepository
+	users   map[primitive.ObjectID]*models.User
+	deleted []primitive.ObjectID
+}
+
+func (r *fakeUserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+	found := *user
+	return &found, nil
+}
+
+func (r *fakeUserRepository) DeleteByID(ctx context.Context, id, deletedBy primitive.ObjectID) error {
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+type fakeUnitOfWork struct{}
+
+func (fakeUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeUnitOfWork) Snapshot(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestDeleteUserPermissions(t *testing.T) {
+	workspace := primitive.NewObjectID()
+	employee := &models.User{ID: primitive.NewObjectID(), Role: models.RoleEmployee}
+	otherEmployee := &models.User{ID: primitive.NewObjectID(), Role: models.RoleEmployee}
+	admin := &models.User{ID: primitive.NewObjectID(), Role: models.RoleAdmin}
+	superAdmin := &models.User{ID: primitive.NewObjectID(), Role: models.RoleSuperAdmin}
+
+	cases := []struct {
+		name   string
+		caller *models.User
+		target *models.User
+		query  string
+	}{
+		{"employee deletes an employee", employee, otherEmployee, ""},
+		{"employee hands over projects", employee, admin, "?transferTo=" + superAdmin.ID.Hex()},
+		{"admin deletes a super_admin", admin, superAdmin, ""},
+		{"admin deletes themselves", admin, admin, ""},
+		{"super_admin deletes themselves", superAdmin, superAdmin, ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			repo := &fakeUserRepository{users: map[primitive.ObjectID]*models.User{}}
+			for _, u := range []*models.User{employee, otherEmployee, admin, superAdmin} {
+				repo.users[u.ID] = u
+			}
+			handler := NewUserHandler(services.NewUserService(repo, nil, nil, fakeUnitOfWork{}))
+
+			token, err := utils.GenerateJWT(c.caller.ID.Hex(), workspace.Hex(), c.caller.Role)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/users/"+c.target.ID.Hex()+c.query, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			req = mux.SetURLVars(req, map[string]string{"id": c.target.ID.Hex()})
+
+			rec := httptest.NewRecorder()
+			handler.DeleteUser(rec, req)
+
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("status = %d, want %d (%s)", rec.Code, http.StatusForbidden, rec.Body.String())
+			}
+			if len(repo.deleted) != 0 {
+				t.Errorf("users were deleted: %v", repo.deleted)
+			}
+		})
+	}
+}
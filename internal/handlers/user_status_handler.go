@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type deactivateRequest struct {
+	SuccessorID string `json:"successorId"`
+}
+
+// =========================
+// SUSPEND USER
+// =========================
+func (h *UserHandler) SuspendUser(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.service)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := h.service.SuspendUser(r.Context(), currentUser, mux.Vars(r)["id"]); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"User suspended successfully",
+		nil,
+	)
+}
+
+// =========================
+// REACTIVATE USER
+// =========================
+func (h *UserHandler) ReactivateUser(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.service)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	if err := h.service.ReactivateUser(r.Context(), currentUser, mux.Vars(r)["id"]); err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"User reactivated successfully",
+		nil,
+	)
+}
+
+// =========================
+// DEACTIVATION PLAN
+// =========================
+func (h *UserHandler) GetDeactivationPlan(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.service)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	plan, err := h.service.PlanDeactivation(
+		r.Context(),
+		currentUser,
+		mux.Vars(r)["id"],
+		r.URL.Query().Get("successorId"),
+	)
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Deactivation plan fetched successfully",
+		plan,
+	)
+}
+
+// =========================
+// DEACTIVATE USER
+// =========================
+func (h *UserHandler) DeactivateUser(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.service)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	var req deactivateRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			utils.SendError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	plan, err := h.service.DeactivateUser(r.Context(), currentUser, mux.Vars(r)["id"], req.SuccessorID)
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"User deactivated successfully",
+		plan,
+	)
+}
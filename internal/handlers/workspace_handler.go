@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/services"
+	"Concurrent_Task_Management_System/internal/utils"
+)
+
+type WorkspaceHandler struct {
+	service     *services.WorkspaceService
+	userService *services.UserService
+}
+
+func NewWorkspaceHandler(service *services.WorkspaceService, userService *services.UserService) *WorkspaceHandler {
+	return &WorkspaceHandler{service: service, userService: userService}
+}
+
+type createWorkspaceRequest struct {
+	Name     string       `json:"name"`
+	Slug     string       `json:"slug"`
+	Admin    *models.User `json:"admin"`
+	Password string       `json:"password"`
+}
+
+// =========================
+// CREATE WORKSPACE (sign up)
+// =========================
+func (h *WorkspaceHandler) CreateWorkspace(w http.ResponseWriter, r *http.Request) {
+	var req createWorkspaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		utils.SendError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	workspace, admin, err := h.service.SignUp(r.Context(), &models.Workspace{
+		Name: req.Name,
+		Slug: req.Slug,
+	}, req.Admin, req.Password)
+	if err != nil {
+		sendServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	token, err := utils.GenerateJWT(admin.ID.Hex(), workspace.ID.Hex(), admin.Role)
+	if err != nil {
+		utils.SendError(w, http.StatusInternalServerError, "Failed to generate token")
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusCreated,
+		"Workspace created successfully",
+		map[string]interface{}{
+			"workspace": workspace,
+			"admin":     admin,
+			"token":     token,
+		},
+	)
+}
+
+// =========================
+// GET CURRENT WORKSPACE
+// =========================
+func (h *WorkspaceHandler) GetCurrentWorkspace(w http.ResponseWriter, r *http.Request) {
+	currentUser, err := authenticate(r, h.userService)
+	if err != nil {
+		utils.SendError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	workspace, err := h.service.GetCurrentWorkspace(r.Context(), currentUser)
+	if err != nil {
+		sendServiceError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	utils.SendSuccess(
+		w,
+		http.StatusOK,
+		"Workspace fetched successfully",
+		workspace,
+	)
+}
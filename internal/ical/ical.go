@@ -0,0 +1,192 @@
+// Package ical renders task feeds as iCalendar (RFC 5545) for calendar
+// clients that subscribe to a URL.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+)
+
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405Z"
+	maxLineOctets  = 75
+)
+
+var todoStatus = map[string]string{
+	models.TaskStatusTodo:       "NEEDS-ACTION",
+	models.TaskStatusInProgress: "IN-PROCESS",
+	models.TaskStatusDone:       "COMPLETED",
+}
+
+// priorities maps task priorities to the iCalendar scale, where 1 is the
+// highest and 9 the lowest.
+var priorities = map[string]int{
+	"High":   1,
+	"Medium": 5,
+	"Low":    9,
+}
+
+// Write renders the feed with one component per task: VTODO when asTodo is
+// set, VEVENT otherwise, since many clients (Google Calendar among them)
+// ignore to-dos. uidDomain makes the UIDs globally unique.
+func Write(w io.Writer, feed *dto.CalendarFeed, asTodo bool, uidDomain string) error {
+	lw := &lineWriter{w: bufio.NewWriter(w)}
+	now := time.Now()
+
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:-//Concurrent Task Management System//Task Feed//EN")
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	lw.line("X-WR-CALNAME:" + escape(feed.Name))
+	lw.line("REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	lw.line("X-PUBLISHED-TTL:PT1H")
+
+	for _, t := range feed.Tasks {
+		component := "VEVENT"
+		if asTodo {
+			component = "VTODO"
+		}
+
+		lw.line("BEGIN:" + component)
+		lw.line("UID:" + t.ID.Hex() + "@" + uidDomain)
+		lw.line("DTSTAMP:" + now.UTC().Format(dateTimeLayout))
+		if !t.CreatedAt.IsZero() {
+			lw.line("CREATED:" + t.CreatedAt.UTC().Format(dateTimeLayout))
+		}
+		if !t.UpdatedAt.IsZero() {
+			lw.line("LAST-MODIFIED:" + t.UpdatedAt.UTC().Format(dateTimeLayout))
+		}
+
+		summary := t.Title
+		if !asTodo && t.Status == models.TaskStatusDone {
+			summary = "✓ " + summary
+		}
+		lw.line("SUMMARY:" + escape(summary))
+		lw.line("DESCRIPTION:" + escape(describe(t, feed.Projects[t.ProjectID])))
+
+		categories := []string{t.Status}
+		if project := feed.Projects[t.ProjectID]; project != "" {
+			categories = append(categories, project)
+		}
+		categories = append(categories, t.Labels...)
+		escaped := make([]string, len(categories))
+		for i, c := range categories {
+			escaped[i] = escape(c)
+		}
+		lw.line("CATEGORIES:" + strings.Join(escaped, ","))
+
+		if p, ok := priorities[t.Priority]; ok {
+			lw.line(fmt.Sprintf("PRIORITY:%d", p))
+		}
+
+		allDay := isMidnightUTC(t.DueDate)
+		if asTodo {
+			if allDay {
+				lw.line("DUE;VALUE=DATE:" + t.DueDate.UTC().Format(dateLayout))
+			} else {
+				lw.line("DUE:" + t.DueDate.UTC().Format(dateTimeLayout))
+			}
+			lw.line("STATUS:" + todoStatus[t.Status])
+			if t.Status == models.TaskStatusDone && !t.UpdatedAt.IsZero() {
+				lw.line("COMPLETED:" + t.UpdatedAt.UTC().Format(dateTimeLayout))
+			}
+		} else {
+			// An all-day event ends the next day; a timed one without an
+			// end is a point in time.
+			if allDay {
+				due := t.DueDate.UTC()
+				lw.line("DTSTART;VALUE=DATE:" + due.Format(dateLayout))
+				lw.line("DTEND;VALUE=DATE:" + due.AddDate(0, 0, 1).Format(dateLayout))
+			} else {
+				lw.line("DTSTART:" + t.DueDate.UTC().Format(dateTimeLayout))
+			}
+			lw.line("STATUS:CONFIRMED")
+			lw.line("TRANSP:TRANSPARENT")
+		}
+
+		lw.line("END:" + component)
+	}
+
+	lw.line("END:VCALENDAR")
+	return lw.flush()
+}
+
+func describe(t models.Task, project string) string {
+	var b strings.Builder
+	if project != "" {
+		fmt.Fprintf(&b, "Project: %s\n", project)
+	}
+	fmt.Fprintf(&b, "Status: %s\n", t.Status)
+	fmt.Fprintf(&b, "Priority: %s\n", t.Priority)
+	if t.Description != "" {
+		b.WriteString("\n" + t.Description)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// Due dates entered as plain dates are stored at midnight UTC and become
+// all-day entries.
+func isMidnightUTC(t time.Time) bool {
+	t = t.UTC()
+	return t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0
+}
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", "",
+	).Replace(s)
+}
+
+// lineWriter ends lines with CRLF and folds them at 75 octets without
+// splitting a UTF-8 sequence. The first error sticks.
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		lw.write(s[:cut] + "\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space, which counts.
+		limit = maxLineOctets - 1
+	}
+	lw.write(s + "\r\n")
+}
+
+func (lw *lineWriter) write(s string) {
+	if lw.err == nil {
+		_, lw.err = lw.w.WriteString(s)
+	}
+}
+
+func (lw *lineWriter) flush() error {
+	if lw.err != nil {
+		return lw.err
+	}
+	return lw.w.Flush()
+}
@@ -0,0 +1,89 @@
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func writeLine(t *testing.T, s string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	lw := &lineWriter{w: bufio.NewWriter(&buf)}
+	lw.line(s)
+	if err := lw.flush(); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	return buf.String()
+}
+
+// unfold reverses folding as a calendar client would (RFC 5545 3.1).
+func unfold(s string) string {
+	return strings.TrimSuffix(strings.ReplaceAll(s, "\r\n ", ""), "\r\n")
+}
+
+func TestLineFolding(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+	}{
+		{"short", "SUMMARY:Ship it"},
+		{"exactly 75", "DESCRIPTION:" + strings.Repeat("a", maxLineOctets-len("DESCRIPTION:"))},
+		{"76", "DESCRIPTION:" + strings.Repeat("a", maxLineOctets-len("DESCRIPTION:")+1)},
+		{"long ascii", "DESCRIPTION:" + strings.Repeat("abcdefghij", 40)},
+		{"two-byte runes", "SUMMARY:" + strings.Repeat("é", 120)},
+		{"four-byte runes", "SUMMARY:" + strings.Repeat("😀", 60)},
+		{"mixed", "DESCRIPTION:" + strings.Repeat("ab€", 70)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			out := writeLine(t, c.in)
+
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("output does not end with CRLF: %q", out)
+			}
+
+			lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			for i, line := range lines {
+				if len(line) > maxLineOctets {
+					t.Errorf("line %d is %d octets long", i, len(line))
+				}
+				if i > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("continuation line %d does not start with a space: %q", i, line)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a UTF-8 sequence: %q", i, line)
+				}
+			}
+
+			if len(c.in) <= maxLineOctets && len(lines) != 1 {
+				t.Errorf("a %d octet line was folded into %d", len(c.in), len(lines))
+			}
+
+			if got := unfold(out); got != c.in {
+				t.Errorf("unfolded output differs from input:\n got %q\nwant %q", got, c.in)
+			}
+		})
+	}
+}
+
+func TestEscape(t *testing.T) {
+	cases := map[string]string{
+		"plain":         "plain",
+		`back\slash`:    `back\\slash`,
+		"a;b,c":         `a\;b\,c`,
+		"line\r\nbreak": `line\nbreak`,
+		"line\nbreak":   `line\nbreak`,
+		"stray\rreturn": "strayreturn",
+	}
+
+	for in, want := range cases {
+		if got := escape(in); got != want {
+			t.Errorf("escape(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,368 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// initialIndexes creates the indexes the application had before migrations
+// existed. On databases that already have them it changes nothing.
+var initialIndexes = Migration{
+	Version: 1,
+	Name:    "initial_indexes",
+	Up:      createInitialIndexes,
+	Down:    dropInitialIndexes,
+}
+
+type collectionIndexes struct {
+	collection string
+	indexes    []mongo.IndexModel
+}
+
+// legacyUserIndexes made user_id and email unique across the whole
+// database; they are now unique per workspace.
+var legacyUserIndexes = []string{"idx_user_user_id", "idx_user_email"}
+
+// supersededIndexes maps indexes below to the ones later migrations
+// replaced them with. Once the replacement exists, running this migration
+// again (cmd/admin indexes) leaves the old index out instead of bringing it
+// back.
+var supersededIndexes = map[string]string{
+	"idx_user_workspace_user_id": "idx_user_workspace_live_user_id",
+	"idx_user_workspace_email":   "idx_user_workspace_live_email",
+}
+
+var initialIndexSpecs = []collectionIndexes{
+	{
+		collection: "workspaces",
+		indexes: []mongo.IndexModel{
+			{
+				Keys: bson.M{"slug": 1},
+				Options: options.Index().
+					SetUnique(true).
+					SetName("idx_workspace_slug"),
+			},
+		},
+	},
+	{
+		collection: "users",
+		indexes: []mongo.IndexModel{
+			{
+				Keys: bson.D{
+					{Key: "workspaceId", Value: 1},
+					{Key: "user_id", Value: 1},
+				},
+				Options: options.Index().
+					SetUnique(true).
+					SetName("idx_user_workspace_user_id"),
+			},
+			{
+				Keys: bson.D{
+					{Key: "workspaceId", Value: 1},
+					{Key: "email", Value: 1},
+				},
+				Options: options.Index().
+					SetUnique(true).
+					SetName("idx_user_workspace_email"),
+			},
+			{
+				Keys: bson.D{
+					{Key: "workspaceId", Value: 1},
+					{Key: "calendarTokenHash", Value: 1},
+				},
+				Options: options.Index().
+					SetUnique(true).
+					SetPartialFilterExpression(bson.M{"calendarTokenHash": bson.M{"$exists": true}}).
+					SetName("idx_user_workspace_calendar_token"),
+			},
+			{
+				Keys: bson.M{"role": 1},
+				Options: options.Index().
+					SetName("idx_user_role"),
+			},
+			{
+				Keys: bson.M{"deletedAt": 1},
+				Options: options.Index().
+					SetSparse(true).
+					SetName("idx_user_deleted_at"),
+			},
+		},
+	},
+	{
+		collection: "projects",
+		indexes: []mongo.IndexModel{
+			{
+				Keys: bson.M{"workspaceId": 1},
+				Options: options.Index().
+					SetName("idx_project_workspace"),
+			},
+			{
+				Keys: bson.M{"ownerId": 1},
+				Options: options.Index().
+					SetName("idx_project_owner"),
+			},
+			{
+				Keys: bson.M{"memberIds": 1},
+				Options: options.Index().
+					SetName("idx_project_members"),
+			},
+			{
+				Keys: bson.M{"deletedAt": 1},
+				Options: options.Index().
+					SetSparse(true).
+					SetName("idx_project_deleted_at"),
+			},
+			{
+				Keys: bson.M{"archivedAt": 1},
+				Options: options.Index().
+					SetSparse(true).
+					SetName("idx_project_archived_at"),
+			},
+			{
+				Keys: bson.M{"teams.teamId": 1},
+				Options: options.Index().
+					SetName("idx_project_teams"),
+			},
+		},
+	},
+	{
+		collection: "tasks",
+		indexes: []mongo.IndexModel{
+			{
+				Keys: bson.M{"workspaceId": 1},
+				Options: options.Index().
+					SetName("idx_task_workspace"),
+			},
+			{
+				Keys: bson.M{"projectId": 1},
+				Options: options.Index().
+					SetName("idx_task_project"),
+			},
+			{
+				Keys: bson.M{"assignedTo": 1},
+				Options: options.Index().
+					SetName("idx_task_assigned"),
+			},
+			{
+				Keys: bson.M{"status": 1},
+				Options: options.Index().
+					SetName("idx_task_status"),
+			},
+			{
+				Keys: bson.M{"deletedAt": 1},
+				Options: options.Index().
+					SetSparse(true).
+					SetName("idx_task_deleted_at"),
+			},
+			{
+				Keys: bson.D{
+					{Key: "projectId", Value: 1},
+					{Key: "status", Value: 1},
+					{Key: "rank", Value: 1},
+				},
+				Options: options.Index().
+					SetName("idx_task_board"),
+			},
+			{
+				Keys: bson.M{"sprintId": 1},
+				Options: options.Index().
+					SetName("idx_task_sprint"),
+			},
+			{
+				Keys: bson.M{"milestoneId": 1},
+				Options: options.Index().
+					SetName("idx_task_milestone"),
+			},
+			{
+				Keys: bson.M{"epicId": 1},
+				Options: options.Index().
+					SetName("idx_task_epic"),
+			},
+			{
+				Keys: bson.M{"labels": 1},
+				Options: options.Index().
+					SetName("idx_task_labels"),
+			},
+			{
+				Keys: bson.M{"assignedTeamId": 1},
+				Options: options.Index().
+					SetName("idx_task_assigned_team"),
+			},
+		},
+	},
+	{
+		collection: "sprints",
+		indexes: []mongo.IndexModel{
+			{
+				Keys: bson.M{"projectId": 1},
+				Options: options.Index().
+					SetName("idx_sprint_project"),
+			},
+			{
+				// At most one active sprint per project.
+				Keys: bson.D{
+					{Key: "projectId", Value: 1},
+					{Key: "state", Value: 1},
+				},
+				Options: options.Index().
+					SetUnique(true).
+					SetPartialFilterExpression(bson.M{"state": "active"}).
+					SetName("idx_sprint_one_active"),
+			},
+		},
+	},
+	{
+		collection: "milestones",
+		indexes: []mongo.IndexModel{
+			{
+				Keys: bson.D{
+					{Key: "projectId", Value: 1},
+					{Key: "targetDate", Value: 1},
+				},
+				Options: options.Index().
+					SetName("idx_milestone_project_target"),
+			},
+		},
+	},
+	{
+		collection: "task_events",
+		indexes: []mongo.IndexModel{
+			{
+				Keys: bson.M{"sprintId": 1},
+				Options: options.Index().
+					SetName("idx_task_event_sprint"),
+			},
+			{
+				Keys: bson.D{
+					{Key: "taskId", Value: 1},
+					{Key: "at", Value: 1},
+				},
+				Options: options.Index().
+					SetName("idx_task_event_task_at"),
+			},
+		},
+	},
+	{
+		collection: "teams",
+		indexes: []mongo.IndexModel{
+			{
+				Keys: bson.D{
+					{Key: "workspaceId", Value: 1},
+					{Key: "name", Value: 1},
+				},
+				Options: options.Index().
+					SetName("idx_team_workspace_name"),
+			},
+			{
+				Keys: bson.M{"memberIds": 1},
+				Options: options.Index().
+					SetName("idx_team_members"),
+			},
+		},
+	},
+	{
+		collection: "project_templates",
+		indexes: []mongo.IndexModel{
+			{
+				Keys: bson.M{"workspaceId": 1},
+				Options: options.Index().
+					SetName("idx_project_template_workspace"),
+			},
+		},
+	},
+	{
+		collection: "invitations",
+		indexes: []mongo.IndexModel{
+			{
+				Keys: bson.D{
+					{Key: "workspaceId", Value: 1},
+					{Key: "tokenHash", Value: 1},
+				},
+				Options: options.Index().
+					SetUnique(true).
+					SetName("idx_invitation_workspace_token"),
+			},
+			{
+				Keys: bson.D{
+					{Key: "workspaceId", Value: 1},
+					{Key: "email", Value: 1},
+				},
+				Options: options.Index().
+					SetName("idx_invitation_workspace_email"),
+			},
+		},
+	},
+}
+
+func createInitialIndexes(ctx context.Context, db *mongo.Database) error {
+	for _, name := range legacyUserIndexes {
+		if _, err := db.Collection("users").Indexes().DropOne(ctx, name); err != nil && !isIndexNotFound(err) {
+			return fmt.Errorf("users: dropping %s: %w", name, err)
+		}
+	}
+
+	for _, spec := range initialIndexSpecs {
+		collection := db.Collection(spec.collection)
+
+		existing, err := indexNames(ctx, collection)
+		if err != nil {
+			return fmt.Errorf("%s: %w", spec.collection, err)
+		}
+
+		indexes := make([]mongo.IndexModel, 0, len(spec.indexes))
+		for _, index := range spec.indexes {
+			if replacement, ok := supersededIndexes[*index.Options.Name]; ok && existing[replacement] {
+				continue
+			}
+			indexes = append(indexes, index)
+		}
+
+		if _, err := collection.Indexes().CreateMany(ctx, indexes); err != nil {
+			return fmt.Errorf("%s: %w", spec.collection, err)
+		}
+	}
+	return nil
+}
+
+// indexNames lists the indexes of a collection, none if it does not exist.
+func indexNames(ctx context.Context, collection *mongo.Collection) (map[string]bool, error) {
+	specs, err := collection.Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		names[spec.Name] = true
+	}
+	return names, nil
+}
+
+// dropInitialIndexes drops every index createInitialIndexes made. The
+// legacy user indexes stay dropped.
+func dropInitialIndexes(ctx context.Context, db *mongo.Database) error {
+	for _, spec := range initialIndexSpecs {
+		for _, index := range spec.indexes {
+			name := *index.Options.Name
+			if _, err := db.Collection(spec.collection).Indexes().DropOne(ctx, name); err != nil && !isIndexNotFound(err) {
+				return fmt.Errorf("%s: dropping %s: %w", spec.collection, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// isIndexNotFound reports whether dropping an index failed only because it
+// does not exist (or its collection does not exist yet).
+func isIndexNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) {
+		return cmdErr.Code == 27 || cmdErr.Code == 26 // IndexNotFound, NamespaceNotFound
+	}
+	return false
+}
@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// presence indexes the presence collection. Its entries only live as long
+// as their heartbeats, so expiresAt is a TTL index.
+var presence = Migration{
+	Version: 2,
+	Name:    "presence",
+	Up:      createPresenceIndexes,
+	Down:    dropPresence,
+}
+
+func createPresenceIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("presence").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "workspaceId", Value: 1},
+				{Key: "resource", Value: 1},
+			},
+			Options: options.Index().
+				SetName("idx_presence_workspace_resource"),
+		},
+		{
+			Keys: bson.M{"connectionId": 1},
+			Options: options.Index().
+				SetName("idx_presence_connection"),
+		},
+		{
+			Keys: bson.M{"expiresAt": 1},
+			Options: options.Index().
+				SetExpireAfterSeconds(0).
+				SetName("idx_presence_expires"),
+		},
+	})
+	return err
+}
+
+// dropPresence drops the whole collection: it holds nothing that outlives a
+// minute.
+func dropPresence(ctx context.Context, db *mongo.Database) error {
+	return db.Collection("presence").Drop(ctx)
+}
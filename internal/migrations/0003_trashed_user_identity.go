@@ -0,0 +1,97 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// trashedUserIdentity frees the email and user_id of users in the trash,
+// so they can be given to someone else right away. The unique indexes only
+// have to hold among live users. A partial index on
+// {deletedAt: {$exists: false}} would say that directly, but MongoDB does
+// not accept negations in a partialFilterExpression. Instead deletedAt
+// joins the keys: every live user has none, so they still collide with
+// each other, while trashed users are told apart by when they were
+// deleted.
+//
+// Down fails while a live user shares an email or user_id with a trashed
+// one.
+var trashedUserIdentity = Migration{
+	Version: 3,
+	Name:    "trashed_user_identity",
+	Up:      freeTrashedUserIdentity,
+	Down:    restoreStrictUserIdentity,
+}
+
+var strictUserIdentityIndexes = []mongo.IndexModel{
+	{
+		Keys: bson.D{
+			{Key: "workspaceId", Value: 1},
+			{Key: "user_id", Value: 1},
+		},
+		Options: options.Index().
+			SetUnique(true).
+			SetName("idx_user_workspace_user_id"),
+	},
+	{
+		Keys: bson.D{
+			{Key: "workspaceId", Value: 1},
+			{Key: "email", Value: 1},
+		},
+		Options: options.Index().
+			SetUnique(true).
+			SetName("idx_user_workspace_email"),
+	},
+}
+
+var liveUserIdentityIndexes = []mongo.IndexModel{
+	{
+		Keys: bson.D{
+			{Key: "workspaceId", Value: 1},
+			{Key: "user_id", Value: 1},
+			{Key: "deletedAt", Value: 1},
+		},
+		Options: options.Index().
+			SetUnique(true).
+			SetName("idx_user_workspace_live_user_id"),
+	},
+	{
+		Keys: bson.D{
+			{Key: "workspaceId", Value: 1},
+			{Key: "email", Value: 1},
+			{Key: "deletedAt", Value: 1},
+		},
+		Options: options.Index().
+			SetUnique(true).
+			SetName("idx_user_workspace_live_email"),
+	},
+}
+
+// Both directions build the new indexes before dropping the old ones, so
+// uniqueness is enforced throughout.
+
+func freeTrashedUserIdentity(ctx context.Context, db *mongo.Database) error {
+	return replaceIndexes(ctx, db.Collection("users"), liveUserIdentityIndexes, strictUserIdentityIndexes)
+}
+
+func restoreStrictUserIdentity(ctx context.Context, db *mongo.Database) error {
+	return replaceIndexes(ctx, db.Collection("users"), strictUserIdentityIndexes, liveUserIdentityIndexes)
+}
+
+func replaceIndexes(ctx context.Context, collection *mongo.Collection, create, drop []mongo.IndexModel) error {
+	if _, err := collection.Indexes().CreateMany(ctx, create); err != nil {
+		return fmt.Errorf("%s: %w", collection.Name(), err)
+	}
+
+	for _, index := range drop {
+		name := *index.Options.Name
+		if _, err := collection.Indexes().DropOne(ctx, name); err != nil && !isIndexNotFound(err) {
+			return fmt.Errorf("%s: dropping %s: %w", collection.Name(), name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package migrations
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const lockID = "migrations"
+
+var (
+	ErrLocked   = errors.New("another migration run holds the lock")
+	ErrLockLost = errors.New("migration lock was lost")
+)
+
+// lockDoc is the single document of the lock collection. A lock past
+// expiresAt is free, so a crashed runner blocks others for one TTL at most.
+type lockDoc struct {
+	ID         string    `bson:"_id"`
+	Owner      string    `bson:"owner"`
+	AcquiredAt time.Time `bson:"acquiredAt"`
+	ExpiresAt  time.Time `bson:"expiresAt"`
+}
+
+type lock struct {
+	coll  *mongo.Collection
+	owner string
+	ttl   time.Duration
+}
+
+// acquireLock takes the lock, polling until wait has passed.
+func acquireLock(ctx context.Context, coll *mongo.Collection, ttl, wait time.Duration) (*lock, error) {
+	l := &lock{coll: coll, owner: lockOwner(), ttl: ttl}
+	deadline := time.Now().Add(wait)
+
+	for {
+		now := time.Now()
+
+		// The filter only matches an expired lock. When the lock is held,
+		// the upsert tries to insert a second document with the same _id
+		// and fails with a duplicate key error.
+		_, err := coll.UpdateOne(ctx,
+			bson.M{"_id": lockID, "expiresAt": bson.M{"$lt": now}},
+			bson.M{"$set": bson.M{"owner": l.owner, "acquiredAt": now, "expiresAt": now.Add(ttl)}},
+			options.Update().SetUpsert(true),
+		)
+		if err == nil {
+			return l, nil
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return nil, err
+		}
+
+		if now.After(deadline) {
+			var held lockDoc
+			if coll.FindOne(ctx, bson.M{"_id": lockID}).Decode(&held) == nil {
+				return nil, fmt.Errorf("%w: held by %s since %s", ErrLocked, held.Owner, held.AcquiredAt.Format(time.RFC3339))
+			}
+			return nil, ErrLocked
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// keepAlive extends the lock while migrations run. The returned context is
+// cancelled if the lock cannot be extended, so a runner that lost it stops
+// instead of racing the next one.
+func (l *lock) keepAlive(ctx context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		ticker := time.NewTicker(l.ttl / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				result, err := l.coll.UpdateOne(ctx,
+					bson.M{"_id": lockID, "owner": l.owner},
+					bson.M{"$set": bson.M{"expiresAt": time.Now().Add(l.ttl)}},
+				)
+				if err == nil && result.MatchedCount == 0 {
+					err = ErrLockLost
+				}
+				if err != nil {
+					cancel(fmt.Errorf("%w: %v", ErrLockLost, err))
+					return
+				}
+			}
+		}
+	}()
+
+	return ctx, func() {
+		close(done)
+		cancel(nil)
+	}
+}
+
+func (l *lock) release(ctx context.Context) {
+	l.coll.DeleteOne(ctx, bson.M{"_id": lockID, "owner": l.owner})
+}
+
+// lockOwner names this process in the lock, for whoever finds it held.
+func lockOwner() string {
+	host, _ := os.Hostname()
+	b := make([]byte, 4)
+	rand.Read(b)
+	return fmt.Sprintf("%s/%d/%s", host, os.Getpid(), hex.EncodeToString(b))
+}
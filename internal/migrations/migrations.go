@@ -0,0 +1,364 @@
+// Package migrations versions the database schema. Each migration has a
+// number, an Up and optionally a Down, and is recorded in the migrations
+// collection once applied. A lock document keeps concurrent runners (for
+// instance several servers starting at once) from applying the same
+// migration twice.
+//
+// MongoDB cannot run index builds in a transaction, so a migration that
+// fails half way is not rolled back. Every Up and Down must therefore be
+// idempotent: running it again after a partial run must finish the job.
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection holds one record per applied migration; LockCollection holds
+// the lock. Neither belongs in a backup: a restore re-runs every migration.
+const (
+	Collection     = "migrations"
+	LockCollection = "migration_lock"
+)
+
+// Latest targets the newest migration known to this binary.
+const Latest = -1
+
+var (
+	ErrUnknownTarget    = errors.New("no migration with this version")
+	ErrUnknownMigration = errors.New("database has a migration this binary does not know")
+	ErrIrreversible     = errors.New("migration cannot be rolled back")
+)
+
+// Migration is one numbered schema change. Down may be nil for changes that
+// cannot be undone.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, db *mongo.Database) error
+	Down    func(ctx context.Context, db *mongo.Database) error
+}
+
+// All lists every migration in version order. New ones are appended with
+// the next number; applied ones are never renumbered or edited.
+func All() []Migration {
+	return []Migration{
+		initialIndexes,
+		presence,
+		trashedUserIdentity,
+	}
+}
+
+// record is what the migrations collection stores per applied migration.
+type record struct {
+	Version    int       `bson:"_id"`
+	Name       string    `bson:"name"`
+	AppliedAt  time.Time `bson:"appliedAt"`
+	DurationMS int64     `bson:"durationMs"`
+}
+
+// Status describes one migration. Known is false for migrations recorded in
+// the database but missing from this binary, usually because a newer
+// version of the application applied them.
+type Status struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+	Known     bool       `json:"known"`
+}
+
+// Step is one migration to run in one direction.
+type Step struct {
+	Version   int    `json:"version"`
+	Name      string `json:"name"`
+	Direction string `json:"direction"`
+}
+
+const (
+	DirectionUp   = "up"
+	DirectionDown = "down"
+)
+
+// Runner plans and applies migrations against one database.
+type Runner struct {
+	db         *mongo.Database
+	migrations []Migration
+	logf       func(format string, args ...interface{})
+
+	// LockWait is how long Migrate waits for another runner to finish
+	// before giving up.
+	LockWait time.Duration
+
+	// LockTTL is how long a lock survives a runner that died without
+	// releasing it. Live runners keep extending it.
+	LockTTL time.Duration
+}
+
+func NewRunner(
+	db *mongo.Database,
+	migrations []Migration,
+	logf func(format string, args ...interface{}),
+) *Runner {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	for i, m := range sorted {
+		if m.Version < 1 || m.Up == nil || (i > 0 && sorted[i-1].Version == m.Version) {
+			panic(fmt.Sprintf("migrations: invalid or duplicate migration %d %q", m.Version, m.Name))
+		}
+	}
+
+	return &Runner{
+		db:         db,
+		migrations: sorted,
+		logf:       logf,
+		LockWait:   2 * time.Minute,
+		LockTTL:    5 * time.Minute,
+	}
+}
+
+// =====================
+// STATUS & PLAN
+// =====================
+
+// Status lists every known migration and every recorded one, by version.
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(r.migrations))
+	for _, m := range r.migrations {
+		s := Status{Version: m.Version, Name: m.Name, Known: true}
+		if rec, ok := applied[m.Version]; ok {
+			s.Applied = true
+			s.AppliedAt = &rec.AppliedAt
+			delete(applied, m.Version)
+		}
+		statuses = append(statuses, s)
+	}
+
+	for _, rec := range applied {
+		rec := rec
+		statuses = append(statuses, Status{
+			Version:   rec.Version,
+			Name:      rec.Name,
+			Applied:   true,
+			AppliedAt: &rec.AppliedAt,
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, nil
+}
+
+// Plan lists the steps Migrate would run to reach target: rollbacks of
+// applied migrations above it in descending order, then pending migrations
+// up to it in ascending order. Target 0 rolls back everything.
+func (r *Runner) Plan(ctx context.Context, target int) ([]Step, error) {
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return r.plan(applied, target)
+}
+
+func (r *Runner) plan(applied map[int]record, target int) ([]Step, error) {
+	// Latest never rolls back: migrations applied by a newer binary are
+	// left alone, so an older server can still start during a deploy.
+	latest := target == Latest
+
+	if latest {
+		target = 0
+		if n := len(r.migrations); n > 0 {
+			target = r.migrations[n-1].Version
+		}
+	} else if target != 0 && r.find(target) == nil {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownTarget, target)
+	}
+
+	steps := []Step{}
+
+	var rollback []int
+	for version := range applied {
+		if version > target && !latest {
+			rollback = append(rollback, version)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(rollback)))
+
+	for _, version := range rollback {
+		m := r.find(version)
+		switch {
+		case m == nil:
+			return nil, fmt.Errorf("%w: %d %q", ErrUnknownMigration, version, applied[version].Name)
+		case m.Down == nil:
+			return nil, fmt.Errorf("%w: %d %q", ErrIrreversible, m.Version, m.Name)
+		}
+		steps = append(steps, Step{Version: m.Version, Name: m.Name, Direction: DirectionDown})
+	}
+
+	for _, m := range r.migrations {
+		if _, ok := applied[m.Version]; !ok && m.Version <= target {
+			steps = append(steps, Step{Version: m.Version, Name: m.Name, Direction: DirectionUp})
+		}
+	}
+
+	return steps, nil
+}
+
+// =====================
+// MIGRATE
+// =====================
+
+// Migrate takes the lock and runs the plan for target, recording each step
+// as soon as it succeeds. It returns the steps that ran; on error, the ones
+// before the failing step are applied and recorded.
+func (r *Runner) Migrate(ctx context.Context, target int) ([]Step, error) {
+	l, err := acquireLock(ctx, r.db.Collection(LockCollection), r.LockTTL, r.LockWait)
+	if err != nil {
+		return nil, err
+	}
+	defer l.release(context.WithoutCancel(ctx))
+
+	ctx, stop := l.keepAlive(ctx)
+	defer stop()
+
+	// Plan under the lock: whoever held it before may have done the work.
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := r.plan(applied, target)
+	if err != nil {
+		return nil, err
+	}
+
+	records := r.db.Collection(Collection)
+	done := make([]Step, 0, len(steps))
+
+	for _, step := range steps {
+		m := r.find(step.Version)
+		r.logf("migration %d %s: %s", m.Version, m.Name, step.Direction)
+
+		if step.Direction == DirectionUp {
+			err = r.up(ctx, m)
+		} else {
+			if err := m.Down(ctx, r.db); err != nil {
+				return done, fmt.Errorf("migration %d %s down: %w", m.Version, m.Name, cause(ctx, err))
+			}
+			if _, err := records.DeleteOne(ctx, bson.M{"_id": m.Version}); err != nil {
+				return done, fmt.Errorf("recording migration %d: %w", m.Version, cause(ctx, err))
+			}
+		}
+		if err != nil {
+			return done, err
+		}
+
+		done = append(done, step)
+	}
+
+	return done, nil
+}
+
+// Reapply runs the Up of every applied migration again, in order, for
+// instance to recreate indexes that were dropped by hand. Since migrations
+// are idempotent this only restores what is missing. Pending migrations are
+// left alone.
+func (r *Runner) Reapply(ctx context.Context) ([]Step, error) {
+	l, err := acquireLock(ctx, r.db.Collection(LockCollection), r.LockTTL, r.LockWait)
+	if err != nil {
+		return nil, err
+	}
+	defer l.release(context.WithoutCancel(ctx))
+
+	ctx, stop := l.keepAlive(ctx)
+	defer stop()
+
+	applied, err := r.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	done := []Step{}
+	for i := range r.migrations {
+		m := &r.migrations[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+
+		r.logf("migration %d %s: reapply", m.Version, m.Name)
+		if err := r.up(ctx, m); err != nil {
+			return done, err
+		}
+		done = append(done, Step{Version: m.Version, Name: m.Name, Direction: DirectionUp})
+	}
+
+	return done, nil
+}
+
+// up runs m.Up and records it.
+func (r *Runner) up(ctx context.Context, m *Migration) error {
+	start := time.Now()
+
+	if err := m.Up(ctx, r.db); err != nil {
+		return fmt.Errorf("migration %d %s up: %w", m.Version, m.Name, cause(ctx, err))
+	}
+
+	_, err := r.db.Collection(Collection).ReplaceOne(ctx, bson.M{"_id": m.Version}, record{
+		Version:    m.Version,
+		Name:       m.Name,
+		AppliedAt:  time.Now(),
+		DurationMS: time.Since(start).Milliseconds(),
+	}, options.Replace().SetUpsert(true))
+	if err != nil {
+		return fmt.Errorf("recording migration %d: %w", m.Version, cause(ctx, err))
+	}
+	return nil
+}
+
+// cause replaces a cancellation error with the reason the context was
+// cancelled, such as a lost lock.
+func cause(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+	return err
+}
+
+func (r *Runner) applied(ctx context.Context) (map[int]record, error) {
+	cursor, err := r.db.Collection(Collection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var records []record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]record, len(records))
+	for _, rec := range records {
+		applied[rec.Version] = rec
+	}
+	return applied, nil
+}
+
+func (r *Runner) find(version int) *Migration {
+	for i := range r.migrations {
+		if r.migrations[i].Version == version {
+			return &r.migrations[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func noop(ctx context.Context, db *mongo.Database) error { return nil }
+
+func testRunner() *Runner {
+	return NewRunner(nil, []Migration{
+		{Version: 3, Name: "three", Up: noop, Down: noop},
+		{Version: 1, Name: "one", Up: noop, Down: noop},
+		{Version: 2, Name: "two", Up: noop},
+	}, nil)
+}
+
+func appliedVersions(versions ...int) map[int]record {
+	applied := make(map[int]record, len(versions))
+	for _, v := range versions {
+		applied[v] = record{Version: v, Name: "recorded"}
+	}
+	return applied
+}
+
+func up(version int, name string) Step {
+	return Step{Version: version, Name: name, Direction: DirectionUp}
+}
+
+func down(version int, name string) Step {
+	return Step{Version: version, Name: name, Direction: DirectionDown}
+}
+
+func TestPlan(t *testing.T) {
+	cases := []struct {
+		name    string
+		applied map[int]record
+		target  int
+		want    []Step
+	}{
+		{
+			name:    "latest on an empty database",
+			applied: appliedVersions(),
+			target:  Latest,
+			want:    []Step{up(1, "one"), up(2, "two"), up(3, "three")},
+		},
+		{
+			name:    "latest when up to date",
+			applied: appliedVersions(1, 2, 3),
+			target:  Latest,
+			want:    []Step{},
+		},
+		{
+			name:    "latest fills gaps",
+			applied: appliedVersions(1, 3),
+			target:  Latest,
+			want:    []Step{up(2, "two")},
+		},
+		{
+			name:    "latest leaves newer migrations alone",
+			applied: appliedVersions(1, 2, 3, 4),
+			target:  Latest,
+			want:    []Step{},
+		},
+		{
+			name:    "up to a target",
+			applied: appliedVersions(),
+			target:  2,
+			want:    []Step{up(1, "one"), up(2, "two")},
+		},
+		{
+			name:    "down to a target",
+			applied: appliedVersions(1, 2, 3),
+			target:  2,
+			want:    []Step{down(3, "three")},
+		},
+		{
+			name:    "down and up at once",
+			applied: appliedVersions(3),
+			target:  2,
+			want:    []Step{down(3, "three"), up(1, "one"), up(2, "two")},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			steps, err := testRunner().plan(c.applied, c.target)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(steps, c.want) {
+				t.Errorf("plan = %v, want %v", steps, c.want)
+			}
+		})
+	}
+}
+
+func TestPlanErrors(t *testing.T) {
+	cases := []struct {
+		name    string
+		applied map[int]record
+		target  int
+		want    error
+	}{
+		{"unknown target", appliedVersions(), 7, ErrUnknownTarget},
+		{"rolling back an irreversible migration", appliedVersions(1, 2), 1, ErrIrreversible},
+		{"rolling back everything past an irreversible one", appliedVersions(1, 2, 3), 0, ErrIrreversible},
+		{"rolling back a migration this binary does not know", appliedVersions(1, 4), 1, ErrUnknownMigration},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := testRunner().plan(c.applied, c.target)
+			if !errors.Is(err, c.want) {
+				t.Errorf("err = %v, want %v", err, c.want)
+			}
+		})
+	}
+}
+
+func TestPlanRollsBackEverything(t *testing.T) {
+	r := NewRunner(nil, []Migration{
+		{Version: 1, Name: "one", Up: noop, Down: noop},
+		{Version: 2, Name: "two", Up: noop, Down: noop},
+	}, nil)
+
+	steps, err := r.plan(appliedVersions(1, 2), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []Step{down(2, "two"), down(1, "one")}
+	if !reflect.DeepEqual(steps, want) {
+		t.Errorf("plan = %v, want %v", steps, want)
+	}
+}
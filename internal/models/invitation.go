@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Invitation lets someone join a workspace by email. Only a hash of the
+// emailed token is stored; the token can be used once, before ExpiresAt.
+type Invitation struct {
+	ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	WorkspaceID primitive.ObjectID  `bson:"workspaceId" json:"workspaceId"`
+	Email       string              `bson:"email" json:"email"`
+	Role        string              `bson:"role" json:"role"`
+	Projects    []InvitedProject    `bson:"projects,omitempty" json:"projects,omitempty"`
+	TokenHash   string              `bson:"tokenHash" json:"-"`
+	InvitedBy   primitive.ObjectID  `bson:"invitedBy" json:"invitedBy"`
+	CreatedAt   time.Time           `bson:"createdAt" json:"createdAt"`
+	ExpiresAt   time.Time           `bson:"expiresAt" json:"expiresAt"`
+	AcceptedAt  *time.Time          `bson:"acceptedAt,omitempty" json:"acceptedAt,omitempty"`
+	AcceptedBy  *primitive.ObjectID `bson:"acceptedBy,omitempty" json:"acceptedBy,omitempty"`
+	RevokedAt   *time.Time          `bson:"revokedAt,omitempty" json:"revokedAt,omitempty"`
+}
+
+// InvitedProject is a project the invitee joins on acceptance.
+type InvitedProject struct {
+	ProjectID primitive.ObjectID `bson:"projectId" json:"projectId"`
+	Role      string             `bson:"role" json:"role"`
+}
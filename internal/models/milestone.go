@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Milestone groups tasks of a project towards a target date. Epics are
+// stored the same way with Kind "epic"; a task can belong to one milestone
+// and one epic at the same time.
+type Milestone struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	WorkspaceID primitive.ObjectID `bson:"workspaceId" json:"workspaceId"`
+	ProjectID   primitive.ObjectID `bson:"projectId" json:"projectId"`
+	Kind        string             `bson:"kind" json:"kind"`
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description" json:"description"`
+	TargetDate  time.Time          `bson:"targetDate" json:"targetDate"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+const (
+	MilestoneKindMilestone = "milestone"
+	MilestoneKindEpic      = "epic"
+)
+
+// MilestoneTaskField is the task field that links a task to a grouping of
+// the given kind.
+func MilestoneTaskField(kind string) string {
+	if kind == MilestoneKindEpic {
+		return "epicId"
+	}
+	return "milestoneId"
+}
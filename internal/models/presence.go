@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Presence records that a user has a project board or a task open on one
+// connection. Clients refresh it with heartbeats; an entry whose client
+// stopped sending them expires on its own.
+type Presence struct {
+	// ID is the connection id and the resource, so that a connection is
+	// present on a resource at most once.
+	ID           string             `bson:"_id" json:"-"`
+	WorkspaceID  primitive.ObjectID `bson:"workspaceId" json:"-"`
+	ConnectionID string             `bson:"connectionId" json:"-"`
+	Resource     string             `bson:"resource" json:"resource"`
+	ProjectID    primitive.ObjectID `bson:"projectId" json:"projectId"`
+	TaskID       primitive.ObjectID `bson:"taskId,omitempty" json:"taskId,omitempty"`
+	UserID       primitive.ObjectID `bson:"userId" json:"userId"`
+	Name         string             `bson:"name" json:"name"`
+	Mode         string             `bson:"mode" json:"mode"`
+	Since        time.Time          `bson:"since" json:"since"`
+	ExpiresAt    time.Time          `bson:"expiresAt" json:"-"`
+}
+
+const (
+	PresenceViewing = "viewing"
+	PresenceEditing = "editing"
+)
@@ -8,9 +8,53 @@ import (
 
 type Project struct {
 	ID          primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	WorkspaceID primitive.ObjectID   `bson:"workspaceId" json:"workspaceId"`
 	Name        string               `bson:"name" json:"name"`
 	Description string               `bson:"description" json:"description"`
 	OwnerID     primitive.ObjectID   `bson:"ownerId" json:"ownerId"`
 	MemberIDs   []primitive.ObjectID `bson:"memberIds" json:"memberIds"`
+	Members     []ProjectMember      `bson:"members" json:"members"`
+	Teams       []ProjectTeam        `bson:"teams,omitempty" json:"teams,omitempty"`
+	WIPLimits   []WIPLimit           `bson:"wipLimits,omitempty" json:"wipLimits,omitempty"`
 	CreatedAt   time.Time            `bson:"createdAt" json:"createdAt"`
+
+	ArchivedAt *time.Time          `bson:"archivedAt,omitempty" json:"archivedAt,omitempty"`
+	ArchivedBy *primitive.ObjectID `bson:"archivedBy,omitempty" json:"archivedBy,omitempty"`
+
+	DeletedAt *time.Time          `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+	DeletedBy *primitive.ObjectID `bson:"deletedBy,omitempty" json:"deletedBy,omitempty"`
+}
+
+// ProjectMember carries a member's role inside one project. MemberIDs is kept
+// in sync with Members so existing queries and indexes keep working.
+type ProjectMember struct {
+	UserID  primitive.ObjectID `bson:"userId" json:"userId"`
+	Role    string             `bson:"role" json:"role"`
+	AddedAt time.Time          `bson:"addedAt" json:"addedAt"`
+}
+
+// ProjectTeam gives every member of a team the same role in the project.
+// Membership follows the team, so people joining or leaving it gain or lose
+// access without the project being touched.
+type ProjectTeam struct {
+	TeamID  primitive.ObjectID `bson:"teamId" json:"teamId"`
+	Role    string             `bson:"role" json:"role"`
+	AddedAt time.Time          `bson:"addedAt" json:"addedAt"`
 }
+
+// WIPLimit caps how many tasks may sit in one status column. Zero means
+// no limit for that dimension.
+type WIPLimit struct {
+	Status         string `bson:"status" json:"status"`
+	Max            int    `bson:"max,omitempty" json:"max,omitempty"`
+	MaxPerAssignee int    `bson:"maxPerAssignee,omitempty" json:"maxPerAssignee,omitempty"`
+}
+
+const (
+	ProjectRoleViewer      = "viewer"
+	ProjectRoleContributor = "contributor"
+	ProjectRoleMaintainer  = "maintainer"
+
+	// ProjectRoleOwner is never stored in Members; it is derived from OwnerID.
+	ProjectRoleOwner = "owner"
+)
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProjectTemplate is a reusable set of tasks saved from a project. Due dates
+// are stored as day offsets from the template's start, so instantiating it
+// with a new start date shifts every task by the same amount. WIPLimits is
+// the project workflow configuration carried over to new projects.
+type ProjectTemplate struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	WorkspaceID     primitive.ObjectID `bson:"workspaceId" json:"workspaceId"`
+	Name            string             `bson:"name" json:"name"`
+	Description     string             `bson:"description" json:"description"`
+	SourceProjectID primitive.ObjectID `bson:"sourceProjectId" json:"sourceProjectId"`
+	CreatedBy       primitive.ObjectID `bson:"createdBy" json:"createdBy"`
+	Tasks           []TemplateTask     `bson:"tasks" json:"tasks"`
+	WIPLimits       []WIPLimit         `bson:"wipLimits,omitempty" json:"wipLimits,omitempty"`
+	CreatedAt       time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// TemplateTask is a task without its project-specific state: no status,
+// assignee, sprint or milestone. Tasks are kept in board order.
+type TemplateTask struct {
+	Title         string   `bson:"title" json:"title"`
+	Description   string   `bson:"description" json:"description"`
+	Priority      string   `bson:"priority" json:"priority"`
+	Labels        []string `bson:"labels,omitempty" json:"labels,omitempty"`
+	DueOffsetDays *int     `bson:"dueOffsetDays,omitempty" json:"dueOffsetDays,omitempty"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Sprint struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	WorkspaceID primitive.ObjectID `bson:"workspaceId" json:"workspaceId"`
+	ProjectID   primitive.ObjectID `bson:"projectId" json:"projectId"`
+	Name        string             `bson:"name" json:"name"`
+	Goal        string             `bson:"goal" json:"goal"`
+	StartDate   time.Time          `bson:"startDate" json:"startDate"`
+	EndDate     time.Time          `bson:"endDate" json:"endDate"`
+	State       string             `bson:"state" json:"state"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+
+	StartedAt     *time.Time          `bson:"startedAt,omitempty" json:"startedAt,omitempty"`
+	ClosedAt      *time.Time          `bson:"closedAt,omitempty" json:"closedAt,omitempty"`
+	CarriedOver   int                 `bson:"carriedOver,omitempty" json:"carriedOver,omitempty"`
+	CarriedOverTo *primitive.ObjectID `bson:"carriedOverTo,omitempty" json:"carriedOverTo,omitempty"`
+}
+
+const (
+	SprintStatePlanned = "planned"
+	SprintStateActive  = "active"
+	SprintStateClosed  = "closed"
+)
@@ -7,14 +7,37 @@ import (
 )
 
 type Task struct {
-	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Title       string             `bson:"title" json:"title"`
-	Description string             `bson:"description" json:"description"`
-	Status      string             `bson:"status" json:"status"`
-	Priority    string             `bson:"priority" json:"priority"`
-	DueDate     time.Time          `bson:"dueDate" json:"dueDate"`
-	ProjectID   primitive.ObjectID `bson:"projectId" json:"projectId"`
-	AssignedTo  primitive.ObjectID `bson:"assignedTo" json:"assignedTo"`
-	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	WorkspaceID    primitive.ObjectID `bson:"workspaceId" json:"workspaceId"`
+	Title          string             `bson:"title" json:"title"`
+	Description    string             `bson:"description" json:"description"`
+	Status         string             `bson:"status" json:"status"`
+	Priority       string             `bson:"priority" json:"priority"`
+	Labels         []string           `bson:"labels,omitempty" json:"labels,omitempty"`
+	DueDate        time.Time          `bson:"dueDate" json:"dueDate"`
+	ProjectID      primitive.ObjectID `bson:"projectId" json:"projectId"`
+	AssignedTo     primitive.ObjectID `bson:"assignedTo" json:"assignedTo"`
+	AssignedTeamID primitive.ObjectID `bson:"assignedTeamId" json:"assignedTeamId"`
+	SprintID       primitive.ObjectID `bson:"sprintId" json:"sprintId"`
+	MilestoneID    primitive.ObjectID `bson:"milestoneId" json:"milestoneId"`
+	EpicID         primitive.ObjectID `bson:"epicId" json:"epicId"`
+	Rank           string             `bson:"rank" json:"rank"`
+	CreatedAt      time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt      time.Time          `bson:"updatedAt" json:"updatedAt"`
+
+	DeletedAt *time.Time          `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+	DeletedBy *primitive.ObjectID `bson:"deletedBy,omitempty" json:"deletedBy,omitempty"`
+
+	// PreviousProjectID is the project the task was last moved out of. Live
+	// updates use it to tell that project's clients the task left.
+	PreviousProjectID primitive.ObjectID `bson:"previousProjectId,omitempty" json:"-"`
 }
+
+const (
+	TaskStatusTodo       = "Todo"
+	TaskStatusInProgress = "In Progress"
+	TaskStatusDone       = "Done"
+)
+
+// TaskStatuses lists the workflow statuses in board column order.
+var TaskStatuses = []string{TaskStatusTodo, TaskStatusInProgress, TaskStatusDone}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TaskEvent is a snapshot of the task fields that sprint reports depend on,
+// written every time one of them changes. Replaying a task's events gives
+// its status and sprint at any point in time.
+type TaskEvent struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	WorkspaceID primitive.ObjectID `bson:"workspaceId" json:"workspaceId"`
+	TaskID      primitive.ObjectID `bson:"taskId" json:"taskId"`
+	ProjectID   primitive.ObjectID `bson:"projectId" json:"projectId"`
+	SprintID    primitive.ObjectID `bson:"sprintId" json:"sprintId"`
+	Status      string             `bson:"status" json:"status"`
+	Deleted     bool               `bson:"deleted,omitempty" json:"deleted,omitempty"`
+	At          time.Time          `bson:"at" json:"at"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Team groups users so they can be added to projects and assigned tasks as
+// a unit. Members' User.TeamIDs mirror MemberIDs, which lets project role
+// checks see team membership without an extra lookup.
+type Team struct {
+	ID          primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	WorkspaceID primitive.ObjectID   `bson:"workspaceId" json:"workspaceId"`
+	Name        string               `bson:"name" json:"name"`
+	Description string               `bson:"description" json:"description"`
+	LeadID      primitive.ObjectID   `bson:"leadId" json:"leadId"`
+	MemberIDs   []primitive.ObjectID `bson:"memberIds" json:"memberIds"`
+	CreatedAt   time.Time            `bson:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time            `bson:"updatedAt" json:"updatedAt"`
+}
@@ -7,10 +7,48 @@ import (
 )
 
 type User struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	UserID    string             `bson:"user_id" json:"user_id"`
-	Name      string             `bson:"name" json:"name"`
-	Email     string             `bson:"email" json:"email"`
-	Role      string             `bson:"role" json:"role"`
-	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	ID          primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	WorkspaceID primitive.ObjectID   `bson:"workspaceId" json:"workspaceId"`
+	UserID      string               `bson:"user_id" json:"user_id"`
+	Name        string               `bson:"name" json:"name"`
+	Email       string               `bson:"email" json:"email"`
+	Role        string               `bson:"role" json:"role"`
+	TeamIDs     []primitive.ObjectID `bson:"teamIds,omitempty" json:"teamIds,omitempty"`
+
+	// PasswordHash is a bcrypt hash. Users created before passwords
+	// existed have none and cannot log in until an admin sets one.
+	PasswordHash string `bson:"passwordHash,omitempty" json:"-"`
+
+	// CalendarTokenHash is the hash of the secret in the user's calendar
+	// feed URLs. Issuing a new token replaces it; empty means no feed.
+	CalendarTokenHash string `bson:"calendarTokenHash,omitempty" json:"-"`
+
+	// Status is empty for users created before statuses existed, which
+	// counts as active.
+	Status          string     `bson:"status,omitempty" json:"status"`
+	StatusChangedAt *time.Time `bson:"statusChangedAt,omitempty" json:"statusChangedAt,omitempty"`
+
+	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+
+	DeletedAt *time.Time          `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+	DeletedBy *primitive.ObjectID `bson:"deletedBy,omitempty" json:"deletedBy,omitempty"`
+}
+
+const (
+	RoleSuperAdmin = "super_admin"
+	RoleAdmin      = "admin"
+	RoleEmployee   = "employee"
+)
+
+// Suspended and deactivated users cannot log in or be assigned work.
+// Deactivation is for people who left; their work is handed to a successor.
+const (
+	UserStatusActive      = "active"
+	UserStatusSuspended   = "suspended"
+	UserStatusDeactivated = "deactivated"
+)
+
+// IsActive treats a missing status as active.
+func (u *User) IsActive() bool {
+	return u.Status == "" || u.Status == UserStatusActive
 }
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Workspace is a tenant. Users, projects and everything hanging off a
+// project belong to exactly one workspace and never see another one.
+type Workspace struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name      string             `bson:"name" json:"name"`
+	Slug      string             `bson:"slug" json:"slug"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// DefaultWorkspaceSlug names the workspace that documents created before
+// workspaces existed are moved into.
+const DefaultWorkspaceSlug = "default"
+
+// The repositories stamp the caller's workspace on every document they
+// insert through SetWorkspaceID.
+
+func (u *User) SetWorkspaceID(id primitive.ObjectID)            { u.WorkspaceID = id }
+func (p *Project) SetWorkspaceID(id primitive.ObjectID)         { p.WorkspaceID = id }
+func (t *Task) SetWorkspaceID(id primitive.ObjectID)            { t.WorkspaceID = id }
+func (s *Sprint) SetWorkspaceID(id primitive.ObjectID)          { s.WorkspaceID = id }
+func (m *Milestone) SetWorkspaceID(id primitive.ObjectID)       { m.WorkspaceID = id }
+func (e *TaskEvent) SetWorkspaceID(id primitive.ObjectID)       { e.WorkspaceID = id }
+func (t *ProjectTemplate) SetWorkspaceID(id primitive.ObjectID) { t.WorkspaceID = id }
+func (t *Team) SetWorkspaceID(id primitive.ObjectID)            { t.WorkspaceID = id }
+func (i *Invitation) SetWorkspaceID(id primitive.ObjectID)      { i.WorkspaceID = id }
+func (p *Presence) SetWorkspaceID(id primitive.ObjectID)        { p.WorkspaceID = id }
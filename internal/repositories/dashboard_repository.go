@@ -9,21 +9,22 @@ import (
 )
 
 type DashboardRepository interface {
-	GetAdminDashboard(ctx context.Context, adminID string) ([]bson.M, error)
+	GetAdminDashboard(ctx context.Context, adminID string, includeArchived bool) ([]bson.M, error)
 }
 
 type dashboardRepository struct {
-	db *mongo.Database
+	users *tenantCollection
 }
 
 func NewDashboardRepository(db *mongo.Database) DashboardRepository {
-	return &dashboardRepository{db: db}
+	return &dashboardRepository{users: newTenantCollection(db, "users")}
 }
 
 // ADMIN DASHBOARD (Aggregation)
 func (r *dashboardRepository) GetAdminDashboard(
 	ctx context.Context,
 	adminID string,
+	includeArchived bool,
 ) ([]bson.M, error) {
 
 	adminObjID, err := primitive.ObjectIDFromHex(adminID)
@@ -31,26 +32,73 @@ func (r *dashboardRepository) GetAdminDashboard(
 		return nil, err
 	}
 
+	// The joined collections are not scoped by the wrapper, so both lookups
+	// match the workspace explicitly.
+	tenantMatch, err := tenantLookupMatch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	projectMatch := bson.M{
+		"$expr": bson.M{
+			"$and": bson.A{
+				bson.M{"$eq": bson.A{"$ownerId", adminObjID}},
+				bson.M{"$in": bson.A{"$$userId", "$memberIds"}},
+			},
+		},
+		"deletedAt": bson.M{"$exists": false},
+	}
+
+	taskMatch := bson.M{
+		"$expr":     bson.M{"$eq": bson.A{"$assignedTo", "$$userId"}},
+		"deletedAt": bson.M{"$exists": false},
+	}
+
+	for k, v := range tenantMatch {
+		projectMatch[k] = v
+		taskMatch[k] = v
+	}
+
+	taskPipeline := bson.A{
+		bson.M{"$match": taskMatch},
+	}
+
+	// Archived projects and their tasks are left out unless asked for.
+	if !includeArchived {
+		projectMatch["archivedAt"] = bson.M{"$exists": false}
+
+		taskPipeline = append(taskPipeline,
+			bson.M{"$lookup": bson.M{
+				"from": "projects",
+				"let":  bson.M{"projectId": "$projectId"},
+				"pipeline": bson.A{
+					bson.M{"$match": bson.M{
+						"$expr":      bson.M{"$eq": bson.A{"$_id", "$$projectId"}},
+						"archivedAt": bson.M{"$exists": true},
+					}},
+					bson.M{"$project": bson.M{"_id": 1}},
+				},
+				"as": "archivedProject",
+			}},
+			bson.M{"$match": bson.M{"archivedProject.0": bson.M{"$exists": false}}},
+			bson.M{"$project": bson.M{"archivedProject": 0}},
+		)
+	}
+
 	pipeline := mongo.Pipeline{
 
 		// Only employees
-		{{Key: "$match", Value: bson.M{"role": "employee"}}},
+		{{Key: "$match", Value: bson.M{
+			"role":      "employee",
+			"deletedAt": bson.M{"$exists": false},
+		}}},
 
 		// Join projects OWNED by this admin
 		{{Key: "$lookup", Value: bson.M{
 			"from": "projects",
 			"let": bson.M{"userId": "$_id"},
 			"pipeline": bson.A{
-				bson.M{
-					"$match": bson.M{
-						"$expr": bson.M{
-							"$and": bson.A{
-								bson.M{"$eq": bson.A{"$ownerId", adminObjID}},
-								bson.M{"$in": bson.A{"$$userId", "$memberIds"}},
-							},
-						},
-					},
-				},
+				bson.M{"$match": projectMatch},
 			},
 			"as": "projects",
 		}}},
@@ -63,9 +111,9 @@ func (r *dashboardRepository) GetAdminDashboard(
 		// Join tasks
 		{{Key: "$lookup", Value: bson.M{
 			"from": "tasks",
-			"localField": "_id",
-			"foreignField": "assignedTo",
-			"as": "tasks",
+			"let": bson.M{"userId": "$_id"},
+			"pipeline": taskPipeline,
+			"as":       "tasks",
 		}}},
 
 		// Final shape
@@ -79,7 +127,7 @@ func (r *dashboardRepository) GetAdminDashboard(
 		}}},
 	}
 
-	cursor, err := r.db.Collection("users").Aggregate(ctx, pipeline)
+	cursor, err := r.users.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type InvitationRepository interface {
+	Create(ctx context.Context, invitation *models.Invitation) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Invitation, error)
+	FindByTokenHash(ctx context.Context, tokenHash string) (*models.Invitation, error)
+	FindPending(ctx context.Context, now time.Time) ([]models.Invitation, error)
+
+	Accept(ctx context.Context, id, userID primitive.ObjectID, now time.Time) (bool, error)
+	Revoke(ctx context.Context, id primitive.ObjectID, now time.Time) (bool, error)
+	RevokePendingForEmail(ctx context.Context, email string, now time.Time) (int64, error)
+}
+
+type invitationRepository struct {
+	collection *tenantCollection
+}
+
+func NewInvitationRepository(db *mongo.Database) InvitationRepository {
+	return &invitationRepository{
+		collection: newTenantCollection(db, "invitations"),
+	}
+}
+
+// pending narrows a filter to invitations that can still be accepted.
+func pending(filter bson.M, now time.Time) bson.M {
+	filter["acceptedAt"] = bson.M{"$exists": false}
+	filter["revokedAt"] = bson.M{"$exists": false}
+	filter["expiresAt"] = bson.M{"$gt": now}
+	return filter
+}
+
+func (r *invitationRepository) Create(ctx context.Context, invitation *models.Invitation) error {
+	result, err := r.collection.InsertOne(ctx, invitation)
+	if err != nil {
+		return err
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		invitation.ID = oid
+	}
+
+	return nil
+}
+
+func (r *invitationRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&invitation)
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*models.Invitation, error) {
+	var invitation models.Invitation
+	err := r.collection.FindOne(ctx, bson.M{"tokenHash": tokenHash}).Decode(&invitation)
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) FindPending(ctx context.Context, now time.Time) ([]models.Invitation, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, pending(bson.M{}, now), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	invitations := []models.Invitation{}
+	if err := cursor.All(ctx, &invitations); err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// Accept and Revoke only succeed on a pending invitation, which is what
+// makes a token single-use even under concurrent requests.
+
+func (r *invitationRepository) Accept(ctx context.Context, id, userID primitive.ObjectID, now time.Time) (bool, error) {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		pending(bson.M{"_id": id}, now),
+		bson.M{"$set": bson.M{"acceptedAt": now, "acceptedBy": userID}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *invitationRepository) Revoke(ctx context.Context, id primitive.ObjectID, now time.Time) (bool, error) {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		pending(bson.M{"_id": id}, now),
+		bson.M{"$set": bson.M{"revokedAt": now}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *invitationRepository) RevokePendingForEmail(ctx context.Context, email string, now time.Time) (int64, error) {
+	result, err := r.collection.UpdateMany(
+		ctx,
+		pending(bson.M{"email": email}, now),
+		bson.M{"$set": bson.M{"revokedAt": now}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type MilestoneRepository interface {
+	Create(ctx context.Context, milestone *models.Milestone) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Milestone, error)
+	FindByProjectID(ctx context.Context, projectID primitive.ObjectID, kind string) ([]models.Milestone, error)
+	UpdateByID(ctx context.Context, id primitive.ObjectID, update bson.M) error
+	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+}
+
+type milestoneRepository struct {
+	collection *tenantCollection
+}
+
+func NewMilestoneRepository(db *mongo.Database) MilestoneRepository {
+	return &milestoneRepository{
+		collection: newTenantCollection(db, "milestones"),
+	}
+}
+
+func (r *milestoneRepository) Create(ctx context.Context, milestone *models.Milestone) error {
+	result, err := r.collection.InsertOne(ctx, milestone)
+	if err != nil {
+		return err
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		milestone.ID = oid
+	}
+
+	return nil
+}
+
+func (r *milestoneRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Milestone, error) {
+	var milestone models.Milestone
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&milestone)
+	if err != nil {
+		return nil, err
+	}
+	return &milestone, nil
+}
+
+// FindByProjectID lists a project's milestones and epics by target date.
+// An empty kind returns both.
+func (r *milestoneRepository) FindByProjectID(
+	ctx context.Context,
+	projectID primitive.ObjectID,
+	kind string,
+) ([]models.Milestone, error) {
+
+	filter := bson.M{"projectId": projectID}
+	if kind != "" {
+		filter["kind"] = kind
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "targetDate", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	milestones := []models.Milestone{}
+	if err := cursor.All(ctx, &milestones); err != nil {
+		return nil, err
+	}
+	return milestones, nil
+}
+
+func (r *milestoneRepository) UpdateByID(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *milestoneRepository) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
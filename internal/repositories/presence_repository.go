@@ -0,0 +1,106 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PresenceRepository stores who has which board or task open. Entries carry
+// an expiry that heartbeats push forward; a TTL index removes the ones left
+// behind by clients or server instances that went away, and reads ignore
+// expired entries the TTL monitor has not removed yet.
+type PresenceRepository interface {
+	Upsert(ctx context.Context, presence *models.Presence) error
+	Refresh(ctx context.Context, connectionID string, expiresAt time.Time) error
+	Delete(ctx context.Context, connectionID string, resource string) error
+	DeleteConnection(ctx context.Context, connectionID string) error
+	FindActive(ctx context.Context, resources []string, now time.Time) ([]models.Presence, error)
+}
+
+type presenceRepository struct {
+	collection *tenantCollection
+}
+
+func NewPresenceRepository(db *mongo.Database) PresenceRepository {
+	return &presenceRepository{
+		collection: newTenantCollection(db, "presence"),
+	}
+}
+
+// Upsert records the presence, or updates the mode and expiry of an existing
+// one while keeping when it started.
+func (r *presenceRepository) Upsert(ctx context.Context, presence *models.Presence) error {
+	set := bson.M{
+		"connectionId": presence.ConnectionID,
+		"resource":     presence.Resource,
+		"projectId":    presence.ProjectID,
+		"userId":       presence.UserID,
+		"name":         presence.Name,
+		"mode":         presence.Mode,
+		"expiresAt":    presence.ExpiresAt,
+	}
+	if !presence.TaskID.IsZero() {
+		set["taskId"] = presence.TaskID
+	}
+
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": presence.ID},
+		bson.M{
+			"$set":         set,
+			"$setOnInsert": bson.M{"since": presence.Since},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// Refresh extends every presence of the connection.
+func (r *presenceRepository) Refresh(ctx context.Context, connectionID string, expiresAt time.Time) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"connectionId": connectionID},
+		bson.M{"$set": bson.M{"expiresAt": expiresAt}},
+	)
+	return err
+}
+
+func (r *presenceRepository) Delete(ctx context.Context, connectionID string, resource string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"connectionId": connectionID, "resource": resource})
+	return err
+}
+
+func (r *presenceRepository) DeleteConnection(ctx context.Context, connectionID string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"connectionId": connectionID})
+	return err
+}
+
+// FindActive returns the unexpired presence on the given resources, oldest
+// first.
+func (r *presenceRepository) FindActive(ctx context.Context, resources []string, now time.Time) ([]models.Presence, error) {
+	if len(resources) == 0 {
+		return nil, nil
+	}
+
+	cursor, err := r.collection.Find(ctx,
+		bson.M{
+			"resource":  bson.M{"$in": resources},
+			"expiresAt": bson.M{"$gt": now},
+		},
+		options.Find().SetSort(bson.D{{Key: "since", Value: 1}, {Key: "_id", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var presence []models.Presence
+	if err := cursor.All(ctx, &presence); err != nil {
+		return nil, err
+	}
+	return presence, nil
+}
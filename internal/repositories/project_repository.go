@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"Concurrent_Task_Management_System/internal/models"
 
@@ -13,21 +14,48 @@ import (
 type ProjectRepository interface {
 	Create(ctx context.Context, project *models.Project) error
 	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Project, error)
-	FindAll(ctx context.Context) ([]models.Project, error)
-	FindByOwnerID(ctx context.Context, ownerID primitive.ObjectID) ([]models.Project, error)
-	FindByMemberID(ctx context.Context, userID primitive.ObjectID) ([]models.Project, error)
+	FindAll(ctx context.Context, includeArchived bool) ([]models.Project, error)
+	FindByOwnerID(ctx context.Context, ownerID primitive.ObjectID, includeArchived bool) ([]models.Project, error)
+	FindByMemberID(ctx context.Context, userID primitive.ObjectID, includeArchived bool) ([]models.Project, error)
+	FindByTeamIDs(ctx context.Context, teamIDs []primitive.ObjectID, includeArchived bool) ([]models.Project, error)
 
 	UpdateByID(ctx context.Context, id primitive.ObjectID, update bson.M) error
-	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+	DeleteByID(ctx context.Context, id primitive.ObjectID, deletedBy primitive.ObjectID) error
+
+	FindDeleted(ctx context.Context) ([]models.Project, error)
+	FindDeletedBy(ctx context.Context, deletedBy primitive.ObjectID) ([]models.Project, error)
+	FindDeletedByID(ctx context.Context, id primitive.ObjectID) (*models.Project, error)
+	RestoreByID(ctx context.Context, id primitive.ObjectID) error
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+
+	CountByOwnerID(ctx context.Context, ownerID primitive.ObjectID) (int64, error)
+	TransferOwnership(ctx context.Context, fromID, toID primitive.ObjectID) (int64, error)
+	RemoveMemberFromAll(ctx context.Context, userID primitive.ObjectID) (int64, error)
+
+	AddMember(ctx context.Context, projectID primitive.ObjectID, member models.ProjectMember) (bool, error)
+	UpdateMemberRole(ctx context.Context, projectID, userID primitive.ObjectID, role string) (bool, error)
+	RemoveMember(ctx context.Context, projectID, userID primitive.ObjectID) (bool, error)
+	ChangeOwner(ctx context.Context, projectID, fromID, toID primitive.ObjectID) (bool, error)
+
+	AddTeam(ctx context.Context, projectID primitive.ObjectID, team models.ProjectTeam) (bool, error)
+	UpdateTeamRole(ctx context.Context, projectID, teamID primitive.ObjectID, role string) (bool, error)
+	RemoveTeam(ctx context.Context, projectID, teamID primitive.ObjectID) (bool, error)
+	RemoveTeamFromAll(ctx context.Context, teamID primitive.ObjectID) (int64, error)
+
+	Archive(ctx context.Context, id primitive.ObjectID, archivedBy primitive.ObjectID) (bool, error)
+	Unarchive(ctx context.Context, id primitive.ObjectID) (bool, error)
+
+	SetWIPLimits(ctx context.Context, projectID primitive.ObjectID, limits []models.WIPLimit) error
+	Lock(ctx context.Context, projectID primitive.ObjectID) error
 }
 
 type projectRepository struct {
-	collection *mongo.Collection
+	collection *tenantCollection
 }
 
 func NewProjectRepository(db *mongo.Database) ProjectRepository {
 	return &projectRepository{
-		collection: db.Collection("projects"),
+		collection: newTenantCollection(db, "projects"),
 	}
 }
 
@@ -47,15 +75,15 @@ func (r *projectRepository) Create(ctx context.Context, project *models.Project)
 
 func (r *projectRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Project, error) {
 	var project models.Project
-	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&project)
+	err := r.collection.FindOne(ctx, notDeleted(bson.M{"_id": id})).Decode(&project)
 	if err != nil {
 		return nil, err
 	}
 	return &project, nil
 }
 
-func (r *projectRepository) FindAll(ctx context.Context) ([]models.Project, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
+func (r *projectRepository) FindAll(ctx context.Context, includeArchived bool) ([]models.Project, error) {
+	cursor, err := r.collection.Find(ctx, notDeleted(archivedFilter(bson.M{}, includeArchived)))
 	if err != nil {
 		return nil, err
 	}
@@ -77,7 +105,7 @@ func (r *projectRepository) FindByUserID(ctx context.Context, userID primitive.O
 		},
 	}
 
-	cursor, err := r.collection.Find(ctx, filter)
+	cursor, err := r.collection.Find(ctx, notDeleted(filter))
 	if err != nil {
 		return nil, err
 	}
@@ -94,25 +122,59 @@ func (r *projectRepository) FindByUserID(ctx context.Context, userID primitive.O
 func (r *projectRepository) UpdateByID(ctx context.Context, id primitive.ObjectID, update bson.M) error {
 	_, err := r.collection.UpdateOne(
 		ctx,
-		bson.M{"_id": id},
+		notDeleted(bson.M{"_id": id}),
 		bson.M{"$set": update},
 	)
 	return err
 }
 
-func (r *projectRepository) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
-	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
-	return err
+func (r *projectRepository) DeleteByID(ctx context.Context, id primitive.ObjectID, deletedBy primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		notDeleted(bson.M{"_id": id}),
+		softDeleteUpdate(deletedBy),
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
 }
 // OWNER ONLY
 func (r *projectRepository) FindByOwnerID(
 	ctx context.Context,
 	ownerID primitive.ObjectID,
+	includeArchived bool,
 ) ([]models.Project, error) {
 
-	filter := bson.M{"ownerId": ownerID}
+	filter := archivedFilter(bson.M{"ownerId": ownerID}, includeArchived)
 
-	cursor, err := r.collection.Find(ctx, filter)
+	cursor, err := r.collection.Find(ctx, notDeleted(filter))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var projects []models.Project
+	if err := cursor.All(ctx, &projects); err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+// TEAM MEMBERSHIP
+func (r *projectRepository) FindByTeamIDs(
+	ctx context.Context,
+	teamIDs []primitive.ObjectID,
+	includeArchived bool,
+) ([]models.Project, error) {
+
+	filter := archivedFilter(bson.M{"teams.teamId": bson.M{"$in": teamIDs}}, includeArchived)
+
+	cursor, err := r.collection.Find(ctx, notDeleted(filter))
 	if err != nil {
 		return nil, err
 	}
@@ -130,10 +192,63 @@ func (r *projectRepository) FindByOwnerID(
 func (r *projectRepository) FindByMemberID(
 	ctx context.Context,
 	userID primitive.ObjectID,
+	includeArchived bool,
 ) ([]models.Project, error) {
 
-	filter := bson.M{"memberIds": userID}
+	filter := archivedFilter(bson.M{"memberIds": userID}, includeArchived)
+
+	cursor, err := r.collection.Find(ctx, notDeleted(filter))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var projects []models.Project
+	if err := cursor.All(ctx, &projects); err != nil {
+		return nil, err
+	}
+
+	return projects, nil
+}
+
+// TRASH
+func (r *projectRepository) FindDeleted(ctx context.Context) ([]models.Project, error) {
+	return r.findProjects(ctx, onlyDeleted(bson.M{}))
+}
 
+func (r *projectRepository) FindDeletedBy(ctx context.Context, deletedBy primitive.ObjectID) ([]models.Project, error) {
+	return r.findProjects(ctx, onlyDeleted(bson.M{"deletedBy": deletedBy}))
+}
+
+func (r *projectRepository) FindDeletedByID(ctx context.Context, id primitive.ObjectID) (*models.Project, error) {
+	var project models.Project
+	err := r.collection.FindOne(ctx, onlyDeleted(bson.M{"_id": id})).Decode(&project)
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+func (r *projectRepository) RestoreByID(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(ctx, onlyDeleted(bson.M{"_id": id}), restoreUpdate())
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *projectRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"deletedAt": bson.M{"$lt": cutoff}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (r *projectRepository) findProjects(ctx context.Context, filter bson.M) ([]models.Project, error) {
 	cursor, err := r.collection.Find(ctx, filter)
 	if err != nil {
 		return nil, err
@@ -147,3 +262,303 @@ func (r *projectRepository) FindByMemberID(
 
 	return projects, nil
 }
+
+// CASCADES
+
+// The following methods deliberately ignore deletedAt so that projects in the
+// trash never come back pointing at a user that no longer exists.
+
+func (r *projectRepository) CountByOwnerID(ctx context.Context, ownerID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"ownerId": ownerID})
+}
+
+func (r *projectRepository) TransferOwnership(ctx context.Context, fromID, toID primitive.ObjectID) (int64, error) {
+	result, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"ownerId": fromID},
+		bson.M{"$set": bson.M{"ownerId": toID}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *projectRepository) RemoveMemberFromAll(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	result, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"memberIds": userID},
+		bson.M{"$pull": bson.M{
+			"memberIds": userID,
+			"members":   bson.M{"userId": userID},
+		}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// MEMBERSHIP
+
+// Every membership change is a single conditional update on the project
+// document, so concurrent edits can neither duplicate nor lose members. The
+// boolean result reports whether the condition matched.
+
+func (r *projectRepository) AddMember(
+	ctx context.Context,
+	projectID primitive.ObjectID,
+	member models.ProjectMember,
+) (bool, error) {
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		notDeleted(bson.M{
+			"_id":       projectID,
+			"ownerId":   bson.M{"$ne": member.UserID},
+			"memberIds": bson.M{"$ne": member.UserID},
+		}),
+		bson.M{
+			"$addToSet": bson.M{"memberIds": member.UserID},
+			"$push":     bson.M{"members": member},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *projectRepository) UpdateMemberRole(
+	ctx context.Context,
+	projectID, userID primitive.ObjectID,
+	role string,
+) (bool, error) {
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		notDeleted(bson.M{"_id": projectID, "members.userId": userID}),
+		bson.M{"$set": bson.M{"members.$.role": role}},
+	)
+	if err != nil {
+		return false, err
+	}
+	if result.MatchedCount == 1 {
+		return true, nil
+	}
+
+	// Members added before roles existed only appear in memberIds.
+	result, err = r.collection.UpdateOne(
+		ctx,
+		notDeleted(bson.M{
+			"_id":            projectID,
+			"memberIds":      userID,
+			"members.userId": bson.M{"$ne": userID},
+		}),
+		bson.M{"$push": bson.M{"members": models.ProjectMember{
+			UserID:  userID,
+			Role:    role,
+			AddedAt: time.Now(),
+		}}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+func (r *projectRepository) RemoveMember(ctx context.Context, projectID, userID primitive.ObjectID) (bool, error) {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		notDeleted(bson.M{"_id": projectID, "memberIds": userID}),
+		bson.M{"$pull": bson.M{
+			"memberIds": userID,
+			"members":   bson.M{"userId": userID},
+		}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// ChangeOwner hands the project from fromID to toID only if fromID is still
+// the owner. The previous owner stays on as a maintainer and the new owner is
+// dropped from the member list, all in one pipeline update.
+func (r *projectRepository) ChangeOwner(ctx context.Context, projectID, fromID, toID primitive.ObjectID) (bool, error) {
+	keep := func(path string) bson.M {
+		return bson.M{"$and": bson.A{
+			bson.M{"$ne": bson.A{path, fromID}},
+			bson.M{"$ne": bson.A{path, toID}},
+		}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"ownerId": toID,
+			"memberIds": bson.M{"$concatArrays": bson.A{
+				bson.M{"$filter": bson.M{
+					"input": bson.M{"$ifNull": bson.A{"$memberIds", bson.A{}}},
+					"cond":  keep("$$this"),
+				}},
+				bson.A{fromID},
+			}},
+			"members": bson.M{"$concatArrays": bson.A{
+				bson.M{"$filter": bson.M{
+					"input": bson.M{"$ifNull": bson.A{"$members", bson.A{}}},
+					"cond":  keep("$$this.userId"),
+				}},
+				bson.A{bson.M{
+					"userId":  fromID,
+					"role":    models.ProjectRoleMaintainer,
+					"addedAt": time.Now(),
+				}},
+			}},
+		}}},
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		notDeleted(bson.M{"_id": projectID, "ownerId": fromID}),
+		pipeline,
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// TEAMS
+
+func (r *projectRepository) AddTeam(
+	ctx context.Context,
+	projectID primitive.ObjectID,
+	team models.ProjectTeam,
+) (bool, error) {
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		notDeleted(bson.M{"_id": projectID, "teams.teamId": bson.M{"$ne": team.TeamID}}),
+		bson.M{"$push": bson.M{"teams": team}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *projectRepository) UpdateTeamRole(
+	ctx context.Context,
+	projectID, teamID primitive.ObjectID,
+	role string,
+) (bool, error) {
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		notDeleted(bson.M{"_id": projectID, "teams.teamId": teamID}),
+		bson.M{"$set": bson.M{"teams.$.role": role}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+func (r *projectRepository) RemoveTeam(ctx context.Context, projectID, teamID primitive.ObjectID) (bool, error) {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		notDeleted(bson.M{"_id": projectID, "teams.teamId": teamID}),
+		bson.M{"$pull": bson.M{"teams": bson.M{"teamId": teamID}}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// RemoveTeamFromAll also reaches trashed projects so a restored project
+// never grants access through a team that no longer exists.
+func (r *projectRepository) RemoveTeamFromAll(ctx context.Context, teamID primitive.ObjectID) (int64, error) {
+	result, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"teams.teamId": teamID},
+		bson.M{"$pull": bson.M{"teams": bson.M{"teamId": teamID}}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// ARCHIVE
+
+// archivedFilter narrows a filter to projects that are not archived,
+// unless includeArchived is set.
+func archivedFilter(filter bson.M, includeArchived bool) bson.M {
+	if !includeArchived {
+		filter["archivedAt"] = bson.M{"$exists": false}
+	}
+	return filter
+}
+
+// Archive reports false if the project was already archived.
+func (r *projectRepository) Archive(ctx context.Context, id primitive.ObjectID, archivedBy primitive.ObjectID) (bool, error) {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		notDeleted(bson.M{"_id": id, "archivedAt": bson.M{"$exists": false}}),
+		bson.M{"$set": bson.M{"archivedAt": time.Now(), "archivedBy": archivedBy}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// Unarchive reports false if the project was not archived.
+func (r *projectRepository) Unarchive(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		notDeleted(bson.M{"_id": id, "archivedAt": bson.M{"$exists": true}}),
+		bson.M{"$unset": bson.M{"archivedAt": "", "archivedBy": ""}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// WIP LIMITS
+
+func (r *projectRepository) SetWIPLimits(ctx context.Context, projectID primitive.ObjectID, limits []models.WIPLimit) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		notDeleted(bson.M{"_id": projectID}),
+		bson.M{"$set": bson.M{"wipLimits": limits}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// Lock writes to the project document inside the caller's transaction.
+// Two transactions that lock the same project conflict, so one of them is
+// aborted and retried after the other commits; this serializes the
+// count-then-write sequence used to enforce WIP limits.
+func (r *projectRepository) Lock(ctx context.Context, projectID primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		notDeleted(bson.M{"_id": projectID}),
+		bson.M{"$inc": bson.M{"lockVersion": 1}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ProjectTemplateRepository interface {
+	Create(ctx context.Context, template *models.ProjectTemplate) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.ProjectTemplate, error)
+	FindAll(ctx context.Context) ([]models.ProjectTemplate, error)
+	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+}
+
+type projectTemplateRepository struct {
+	collection *tenantCollection
+}
+
+func NewProjectTemplateRepository(db *mongo.Database) ProjectTemplateRepository {
+	return &projectTemplateRepository{
+		collection: newTenantCollection(db, "project_templates"),
+	}
+}
+
+func (r *projectTemplateRepository) Create(ctx context.Context, template *models.ProjectTemplate) error {
+	result, err := r.collection.InsertOne(ctx, template)
+	if err != nil {
+		return err
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		template.ID = oid
+	}
+
+	return nil
+}
+
+func (r *projectTemplateRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.ProjectTemplate, error) {
+	var template models.ProjectTemplate
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&template)
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// FindAll lists templates by name without their task lists.
+func (r *projectTemplateRepository) FindAll(ctx context.Context) ([]models.ProjectTemplate, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "name", Value: 1}}).
+		SetProjection(bson.M{"tasks": 0})
+
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	templates := []models.ProjectTemplate{}
+	if err := cursor.All(ctx, &templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (r *projectTemplateRepository) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Documents are never removed by DeleteByID. Instead they are stamped with
+// deletedAt/deletedBy and hidden from every Find* query until they are either
+// restored or purged once the trash retention window has passed.
+
+// notDeleted narrows a filter to documents that are not in the trash.
+func notDeleted(filter bson.M) bson.M {
+	filter["deletedAt"] = bson.M{"$exists": false}
+	return filter
+}
+
+// onlyDeleted narrows a filter to documents that are in the trash.
+func onlyDeleted(filter bson.M) bson.M {
+	filter["deletedAt"] = bson.M{"$exists": true}
+	return filter
+}
+
+func softDeleteUpdate(deletedBy primitive.ObjectID) bson.M {
+	return bson.M{"$set": bson.M{
+		"deletedAt": time.Now(),
+		"deletedBy": deletedBy,
+	}}
+}
+
+func restoreUpdate() bson.M {
+	return bson.M{"$unset": bson.M{
+		"deletedAt": "",
+		"deletedBy": "",
+	}}
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SprintRepository interface {
+	Create(ctx context.Context, sprint *models.Sprint) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Sprint, error)
+	FindByProjectID(ctx context.Context, projectID primitive.ObjectID) ([]models.Sprint, error)
+	UpdateByID(ctx context.Context, id primitive.ObjectID, update bson.M) error
+
+	Start(ctx context.Context, id primitive.ObjectID, at time.Time) (bool, error)
+	Close(ctx context.Context, id primitive.ObjectID, at time.Time, carriedOver int, carriedOverTo *primitive.ObjectID) (bool, error)
+}
+
+type sprintRepository struct {
+	collection *tenantCollection
+}
+
+func NewSprintRepository(db *mongo.Database) SprintRepository {
+	return &sprintRepository{
+		collection: newTenantCollection(db, "sprints"),
+	}
+}
+
+func (r *sprintRepository) Create(ctx context.Context, sprint *models.Sprint) error {
+	result, err := r.collection.InsertOne(ctx, sprint)
+	if err != nil {
+		return err
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		sprint.ID = oid
+	}
+
+	return nil
+}
+
+func (r *sprintRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Sprint, error) {
+	var sprint models.Sprint
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&sprint)
+	if err != nil {
+		return nil, err
+	}
+	return &sprint, nil
+}
+
+func (r *sprintRepository) FindByProjectID(ctx context.Context, projectID primitive.ObjectID) ([]models.Sprint, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "startDate", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"projectId": projectID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	sprints := []models.Sprint{}
+	if err := cursor.All(ctx, &sprints); err != nil {
+		return nil, err
+	}
+	return sprints, nil
+}
+
+func (r *sprintRepository) UpdateByID(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// Start moves a planned sprint to active. It reports false if the sprint was
+// not planned any more. The partial unique index idx_sprint_one_active
+// rejects a second active sprint in the same project with a duplicate key
+// error.
+func (r *sprintRepository) Start(ctx context.Context, id primitive.ObjectID, at time.Time) (bool, error) {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "state": models.SprintStatePlanned},
+		bson.M{"$set": bson.M{"state": models.SprintStateActive, "startedAt": at}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// Close moves an active sprint to closed. It reports false if the sprint
+// was not active any more.
+func (r *sprintRepository) Close(
+	ctx context.Context,
+	id primitive.ObjectID,
+	at time.Time,
+	carriedOver int,
+	carriedOverTo *primitive.ObjectID,
+) (bool, error) {
+
+	set := bson.M{
+		"state":       models.SprintStateClosed,
+		"closedAt":    at,
+		"carriedOver": carriedOver,
+	}
+	if carriedOverTo != nil {
+		set["carriedOverTo"] = *carriedOverTo
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": id, "state": models.SprintStateActive},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TaskEventRepository interface {
+	Create(ctx context.Context, event *models.TaskEvent) error
+	CreateMany(ctx context.Context, events []models.TaskEvent) error
+	FindTaskIDsBySprint(ctx context.Context, sprintID primitive.ObjectID) ([]primitive.ObjectID, error)
+	FindByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID, until time.Time) ([]models.TaskEvent, error)
+	EachByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID, until time.Time, fn func(*models.TaskEvent) error) error
+}
+
+type taskEventRepository struct {
+	collection *tenantCollection
+}
+
+func NewTaskEventRepository(db *mongo.Database) TaskEventRepository {
+	return &taskEventRepository{
+		collection: newTenantCollection(db, "task_events"),
+	}
+}
+
+func (r *taskEventRepository) Create(ctx context.Context, event *models.TaskEvent) error {
+	result, err := r.collection.InsertOne(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		event.ID = oid
+	}
+
+	return nil
+}
+
+func (r *taskEventRepository) CreateMany(ctx context.Context, events []models.TaskEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(events))
+	for i := range events {
+		docs[i] = &events[i]
+	}
+
+	result, err := r.collection.InsertMany(ctx, docs)
+	if err != nil {
+		return err
+	}
+
+	for i, id := range result.InsertedIDs {
+		if oid, ok := id.(primitive.ObjectID); ok {
+			events[i].ID = oid
+		}
+	}
+
+	return nil
+}
+
+// FindTaskIDsBySprint returns every task that has ever been in the sprint.
+func (r *taskEventRepository) FindTaskIDsBySprint(ctx context.Context, sprintID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	values, err := r.collection.Distinct(ctx, "taskId", bson.M{"sprintId": sprintID})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(primitive.ObjectID); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// FindByTaskIDs returns the events of the given tasks up to until, oldest first.
+func (r *taskEventRepository) FindByTaskIDs(
+	ctx context.Context,
+	taskIDs []primitive.ObjectID,
+	until time.Time,
+) ([]models.TaskEvent, error) {
+
+	filter := bson.M{
+		"taskId": bson.M{"$in": taskIDs},
+		"at":     bson.M{"$lt": until},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var events []models.TaskEvent
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// EachByTaskIDs calls fn with the events of the given tasks up to until,
+// grouped by task and oldest first within each, reading them from a cursor.
+// It stops at the first error fn returns.
+func (r *taskEventRepository) EachByTaskIDs(
+	ctx context.Context,
+	taskIDs []primitive.ObjectID,
+	until time.Time,
+	fn func(*models.TaskEvent) error,
+) error {
+
+	filter := bson.M{
+		"taskId": bson.M{"$in": taskIDs},
+		"at":     bson.M{"$lt": until},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "taskId", Value: 1}, {Key: "at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var event models.TaskEvent
+		if err := cursor.Decode(&event); err != nil {
+			return err
+		}
+		if err := fn(&event); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
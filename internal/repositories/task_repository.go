@@ -2,16 +2,20 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"Concurrent_Task_Management_System/internal/models"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type TaskRepository interface {
 	Create(ctx context.Context, task *models.Task) error
+	CreateMany(ctx context.Context, tasks []models.Task) error
 	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Task, error)
 	FindAll(ctx context.Context) ([]models.Task, error)
 	FindByProjectID(ctx context.Context, projectID primitive.ObjectID) ([]models.Task, error)
@@ -21,16 +25,72 @@ type TaskRepository interface {
 	FindByAssignedUser(ctx context.Context, userID primitive.ObjectID) ([]models.Task, error)
 	FindByStatus(ctx context.Context, status string) ([]models.Task, error)
 	UpdateByID(ctx context.Context, id primitive.ObjectID, update bson.M) error
-	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+	DeleteByID(ctx context.Context, id primitive.ObjectID, deletedBy primitive.ObjectID) error
+
+	FindDeleted(ctx context.Context) ([]models.Task, error)
+	FindDeletedBy(ctx context.Context, deletedBy primitive.ObjectID) ([]models.Task, error)
+	FindDeletedByID(ctx context.Context, id primitive.ObjectID) (*models.Task, error)
+	RestoreByID(ctx context.Context, id primitive.ObjectID) error
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+
+	DeleteByProjectID(ctx context.Context, projectID primitive.ObjectID, deletedBy primitive.ObjectID) (int64, error)
+	RestoreByProjectID(ctx context.Context, projectID primitive.ObjectID, deletedSince time.Time) (int64, error)
+	UnassignUser(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	UnassignUserInProject(ctx context.Context, projectID, userID primitive.ObjectID) (int64, error)
+	FindOpenByAssignee(ctx context.Context, userID primitive.ObjectID) ([]models.Task, error)
+	FindInProjectMatching(ctx context.Context, projectID primitive.ObjectID, match bson.M, limit int64) ([]models.Task, error)
+	BulkWrite(ctx context.Context, writes []TaskWrite) (int64, error)
+	ReassignOpen(ctx context.Context, fromID, toID primitive.ObjectID, projectIDs []primitive.ObjectID) (int64, error)
+
+	FindBoard(ctx context.Context, projectID primitive.ObjectID) ([]models.Task, error)
+	FindColumn(ctx context.Context, projectID primitive.ObjectID, status string) ([]models.Task, error)
+	FindLastInColumn(ctx context.Context, projectID primitive.ObjectID, status string) (*models.Task, error)
+	EachInColumn(ctx context.Context, projectID primitive.ObjectID, status string, fn func(*models.Task) error) error
+	FindAssignees(ctx context.Context, projectID primitive.ObjectID) ([]primitive.ObjectID, []primitive.ObjectID, error)
+	FindNextInColumn(ctx context.Context, projectID primitive.ObjectID, status, rank string) (*models.Task, error)
+	FindPrevInColumn(ctx context.Context, projectID primitive.ObjectID, status, rank string) (*models.Task, error)
+
+	CountInColumn(ctx context.Context, projectID primitive.ObjectID, status string) (int64, error)
+	CountInColumnForAssignee(ctx context.Context, projectID primitive.ObjectID, status string, assignee primitive.ObjectID) (int64, error)
+	CountByStatusAndAssignee(ctx context.Context, projectID primitive.ObjectID) ([]ColumnCount, error)
+
+	FindBySprintID(ctx context.Context, sprintID primitive.ObjectID) ([]models.Task, error)
+	FindUnfinishedInSprint(ctx context.Context, sprintID primitive.ObjectID) ([]models.Task, error)
+	SetSprint(ctx context.Context, ids []primitive.ObjectID, sprintID primitive.ObjectID) (int64, error)
+
+	FindByMilestone(ctx context.Context, field string, milestoneID primitive.ObjectID) ([]models.Task, error)
+	CountProgress(ctx context.Context, projectID primitive.ObjectID, field string) ([]GroupProgress, error)
+	UnlinkMilestone(ctx context.Context, field string, milestoneID primitive.ObjectID) (int64, error)
+
+	FindTeamQueue(ctx context.Context, teamID primitive.ObjectID, memberIDs []primitive.ObjectID, status string) ([]models.Task, error)
+	UnassignTeam(ctx context.Context, teamID primitive.ObjectID) (int64, error)
+	UnassignTeamInProject(ctx context.Context, projectID, teamID primitive.ObjectID) (int64, error)
+
+	Watch(ctx context.Context, projectID primitive.ObjectID, resumeToken string) (TaskChangeStream, error)
+	WatchAll(ctx context.Context) (TaskChangeStream, error)
+}
+
+// GroupProgress counts the live tasks linked to one milestone or epic.
+type GroupProgress struct {
+	ID    primitive.ObjectID `bson:"_id"`
+	Total int                `bson:"total"`
+	Done  int                `bson:"done"`
+}
+
+// ColumnCount is the number of live tasks of a project per status and assignee.
+type ColumnCount struct {
+	Status     string             `bson:"status"`
+	AssignedTo primitive.ObjectID `bson:"assignedTo"`
+	Count      int                `bson:"count"`
 }
 
 type taskRepository struct {
-	collection *mongo.Collection
+	collection *tenantCollection
 }
 
 func NewTaskRepository(db *mongo.Database) TaskRepository {
 	return &taskRepository{
-		collection: db.Collection("tasks"),
+		collection: newTenantCollection(db, "tasks"),
 	}
 }
 
@@ -48,9 +108,34 @@ func (r *taskRepository) Create(ctx context.Context, task *models.Task) error {
 }
 
 
+// CreateMany inserts the tasks in one round trip and fills in their ids.
+func (r *taskRepository) CreateMany(ctx context.Context, tasks []models.Task) error {
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(tasks))
+	for i := range tasks {
+		docs[i] = &tasks[i]
+	}
+
+	result, err := r.collection.InsertMany(ctx, docs)
+	if err != nil {
+		return err
+	}
+
+	for i, id := range result.InsertedIDs {
+		if oid, ok := id.(primitive.ObjectID); ok {
+			tasks[i].ID = oid
+		}
+	}
+
+	return nil
+}
+
 func (r *taskRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Task, error) {
 	var task models.Task
-	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&task)
+	err := r.collection.FindOne(ctx, notDeleted(bson.M{"_id": id})).Decode(&task)
 	if err != nil {
 		return nil, err
 	}
@@ -58,7 +143,7 @@ func (r *taskRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*
 }
 
 func (r *taskRepository) FindAll(ctx context.Context) ([]models.Task, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
+	cursor, err := r.collection.Find(ctx, notDeleted(bson.M{}))
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +157,7 @@ func (r *taskRepository) FindAll(ctx context.Context) ([]models.Task, error) {
 }
 
 func (r *taskRepository) FindByProjectID(ctx context.Context, projectID primitive.ObjectID) ([]models.Task, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{"projectId": projectID})
+	cursor, err := r.collection.Find(ctx, notDeleted(bson.M{"projectId": projectID}))
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +171,7 @@ func (r *taskRepository) FindByProjectID(ctx context.Context, projectID primitiv
 }
 
 func (r *taskRepository) FindByAssignedUser(ctx context.Context, userID primitive.ObjectID) ([]models.Task, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{"assignedTo": userID})
+	cursor, err := r.collection.Find(ctx, notDeleted(bson.M{"assignedTo": userID}))
 	if err != nil {
 		return nil, err
 	}
@@ -100,7 +185,7 @@ func (r *taskRepository) FindByAssignedUser(ctx context.Context, userID primitiv
 }
 
 func (r *taskRepository) FindByStatus(ctx context.Context, status string) ([]models.Task, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{"status": status})
+	cursor, err := r.collection.Find(ctx, notDeleted(bson.M{"status": status}))
 	if err != nil {
 		return nil, err
 	}
@@ -116,15 +201,25 @@ func (r *taskRepository) FindByStatus(ctx context.Context, status string) ([]mod
 func (r *taskRepository) UpdateByID(ctx context.Context, id primitive.ObjectID, update bson.M) error {
 	_, err := r.collection.UpdateOne(
 		ctx,
-		bson.M{"_id": id},
+		notDeleted(bson.M{"_id": id}),
 		bson.M{"$set": update},
 	)
 	return err
 }
 
-func (r *taskRepository) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
-	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
-	return err
+func (r *taskRepository) DeleteByID(ctx context.Context, id primitive.ObjectID, deletedBy primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		notDeleted(bson.M{"_id": id}),
+		softDeleteUpdate(deletedBy),
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
 }
 func (r *taskRepository) FindByProjectIDs(
 	ctx context.Context,
@@ -135,6 +230,58 @@ func (r *taskRepository) FindByProjectIDs(
 		"projectId": bson.M{"$in": projectIDs},
 	}
 
+	cursor, err := r.collection.Find(ctx, notDeleted(filter))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tasks []models.Task
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// TRASH
+func (r *taskRepository) FindDeleted(ctx context.Context) ([]models.Task, error) {
+	return r.findTasks(ctx, onlyDeleted(bson.M{}))
+}
+
+func (r *taskRepository) FindDeletedBy(ctx context.Context, deletedBy primitive.ObjectID) ([]models.Task, error) {
+	return r.findTasks(ctx, onlyDeleted(bson.M{"deletedBy": deletedBy}))
+}
+
+func (r *taskRepository) FindDeletedByID(ctx context.Context, id primitive.ObjectID) (*models.Task, error) {
+	var task models.Task
+	err := r.collection.FindOne(ctx, onlyDeleted(bson.M{"_id": id})).Decode(&task)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (r *taskRepository) RestoreByID(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(ctx, onlyDeleted(bson.M{"_id": id}), restoreUpdate())
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *taskRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"deletedAt": bson.M{"$lt": cutoff}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (r *taskRepository) findTasks(ctx context.Context, filter bson.M) ([]models.Task, error) {
 	cursor, err := r.collection.Find(ctx, filter)
 	if err != nil {
 		return nil, err
@@ -148,3 +295,541 @@ func (r *taskRepository) FindByProjectIDs(
 
 	return tasks, nil
 }
+
+// CASCADES
+
+// DeleteByProjectID moves every live task of a project to the trash.
+func (r *taskRepository) DeleteByProjectID(
+	ctx context.Context,
+	projectID primitive.ObjectID,
+	deletedBy primitive.ObjectID,
+) (int64, error) {
+
+	result, err := r.collection.UpdateMany(
+		ctx,
+		notDeleted(bson.M{"projectId": projectID}),
+		softDeleteUpdate(deletedBy),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// RestoreByProjectID brings back the tasks trashed together with their
+// project, i.e. those deleted at or after the project itself. Tasks that were
+// deleted on their own before that stay in the trash.
+func (r *taskRepository) RestoreByProjectID(
+	ctx context.Context,
+	projectID primitive.ObjectID,
+	deletedSince time.Time,
+) (int64, error) {
+
+	result, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{
+			"projectId": projectID,
+			"deletedAt": bson.M{"$gte": deletedSince},
+		},
+		restoreUpdate(),
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// UnassignUser clears assignedTo on every task, live or trashed, assigned to the user.
+func (r *taskRepository) UnassignUser(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	result, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"assignedTo": userID},
+		bson.M{"$set": bson.M{
+			"assignedTo": primitive.NilObjectID,
+			"updatedAt":  time.Now(),
+		}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// FindInProjectMatching returns at most limit live tasks of the project that
+// also match match, in board order.
+func (r *taskRepository) FindInProjectMatching(
+	ctx context.Context,
+	projectID primitive.ObjectID,
+	match bson.M,
+	limit int64,
+) ([]models.Task, error) {
+
+	filter := bson.M{}
+	for k, v := range match {
+		filter[k] = v
+	}
+	filter["projectId"] = projectID
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "status", Value: 1}, {Key: "rank", Value: 1}}).
+		SetLimit(limit)
+
+	cursor, err := r.collection.Find(ctx, notDeleted(filter), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tasks := []models.Task{}
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// TaskWrite is one entry of TaskRepository.BulkWrite: either a $set on a
+// live task or, with Delete, moving it to the trash. UpdatedAt is the
+// task's updatedAt as the caller read it; the write only applies if the
+// task has not been updated since.
+type TaskWrite struct {
+	ID        primitive.ObjectID
+	UpdatedAt time.Time
+	Set       bson.M
+	Delete    bool
+	DeletedBy primitive.ObjectID
+}
+
+// BulkWrite sends all writes in one ordered BulkWrite and returns how many
+// tasks matched. Tasks that are already in the trash or were updated after
+// the caller read them do not match.
+func (r *taskRepository) BulkWrite(ctx context.Context, writes []TaskWrite) (int64, error) {
+	if len(writes) == 0 {
+		return 0, nil
+	}
+
+	batch := make([]mongo.WriteModel, 0, len(writes))
+	for _, w := range writes {
+		update := bson.M{"$set": w.Set}
+		if w.Delete {
+			update = softDeleteUpdate(w.DeletedBy)
+		}
+
+		// Tasks written before updatedAt existed have no such field.
+		var updatedAt interface{} = w.UpdatedAt
+		if w.UpdatedAt.IsZero() {
+			updatedAt = bson.M{"$in": bson.A{w.UpdatedAt, nil}}
+		}
+
+		batch = append(batch, mongo.NewUpdateOneModel().
+			SetFilter(notDeleted(bson.M{"_id": w.ID, "updatedAt": updatedAt})).
+			SetUpdate(update))
+	}
+
+	result, err := r.collection.BulkWrite(ctx, batch, options.BulkWrite().SetOrdered(true))
+	if err != nil {
+		return 0, err
+	}
+	return result.MatchedCount, nil
+}
+
+// openAssignedTo matches the live, unfinished tasks of a user.
+func openAssignedTo(userID primitive.ObjectID) bson.M {
+	return notDeleted(bson.M{
+		"assignedTo": userID,
+		"status":     bson.M{"$ne": models.TaskStatusDone},
+	})
+}
+
+func (r *taskRepository) FindOpenByAssignee(ctx context.Context, userID primitive.ObjectID) ([]models.Task, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "projectId", Value: 1}, {Key: "dueDate", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, openAssignedTo(userID), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tasks := []models.Task{}
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// ReassignOpen hands fromID's open tasks in projectIDs to toID.
+func (r *taskRepository) ReassignOpen(ctx context.Context, fromID, toID primitive.ObjectID, projectIDs []primitive.ObjectID) (int64, error) {
+	filter := openAssignedTo(fromID)
+	filter["projectId"] = bson.M{"$in": projectIDs}
+
+	result, err := r.collection.UpdateMany(
+		ctx,
+		filter,
+		bson.M{"$set": bson.M{
+			"assignedTo": toID,
+			"updatedAt":  time.Now(),
+		}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *taskRepository) UnassignUserInProject(ctx context.Context, projectID, userID primitive.ObjectID) (int64, error) {
+	result, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"projectId": projectID, "assignedTo": userID},
+		bson.M{"$set": bson.M{
+			"assignedTo": primitive.NilObjectID,
+			"updatedAt":  time.Now(),
+		}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// BOARD
+
+// Within a column tasks are ordered by rank. Tasks created before ranks
+// existed have an empty rank and fall back to creation order.
+var boardOrder = bson.D{{Key: "rank", Value: 1}, {Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}
+
+func (r *taskRepository) FindBoard(ctx context.Context, projectID primitive.ObjectID) ([]models.Task, error) {
+	cursor, err := r.collection.Find(
+		ctx,
+		notDeleted(bson.M{"projectId": projectID}),
+		options.Find().SetSort(boardOrder),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tasks []models.Task
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (r *taskRepository) FindColumn(ctx context.Context, projectID primitive.ObjectID, status string) ([]models.Task, error) {
+	cursor, err := r.collection.Find(
+		ctx,
+		notDeleted(bson.M{"projectId": projectID, "status": status}),
+		options.Find().SetSort(boardOrder),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tasks []models.Task
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// EachInColumn calls fn with the tasks of a column in board order, reading
+// them from a cursor instead of loading the column at once. It stops at the
+// first error fn returns.
+func (r *taskRepository) EachInColumn(
+	ctx context.Context,
+	projectID primitive.ObjectID,
+	status string,
+	fn func(*models.Task) error,
+) error {
+
+	cursor, err := r.collection.Find(
+		ctx,
+		notDeleted(bson.M{"projectId": projectID, "status": status}),
+		options.Find().SetSort(boardOrder),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var task models.Task
+		if err := cursor.Decode(&task); err != nil {
+			return err
+		}
+		if err := fn(&task); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// FindAssignees returns the distinct users and teams the live tasks of a
+// project are assigned to.
+func (r *taskRepository) FindAssignees(
+	ctx context.Context,
+	projectID primitive.ObjectID,
+) ([]primitive.ObjectID, []primitive.ObjectID, error) {
+
+	filter := notDeleted(bson.M{"projectId": projectID})
+
+	users, err := r.distinctIDs(ctx, "assignedTo", filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	teams, err := r.distinctIDs(ctx, "assignedTeamId", filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	return users, teams, nil
+}
+
+func (r *taskRepository) distinctIDs(ctx context.Context, field string, filter bson.M) ([]primitive.ObjectID, error) {
+	values, err := r.collection.Distinct(ctx, field, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(values))
+	for _, v := range values {
+		if id, ok := v.(primitive.ObjectID); ok && !id.IsZero() {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// FindLastInColumn returns the bottom task of a column, or nil if it is empty.
+func (r *taskRepository) FindLastInColumn(ctx context.Context, projectID primitive.ObjectID, status string) (*models.Task, error) {
+	return r.findOneInColumn(
+		ctx,
+		bson.M{"projectId": projectID, "status": status},
+		bson.D{{Key: "rank", Value: -1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}},
+	)
+}
+
+// FindNextInColumn returns the task directly below rank, or nil if there is none.
+func (r *taskRepository) FindNextInColumn(ctx context.Context, projectID primitive.ObjectID, status, rank string) (*models.Task, error) {
+	return r.findOneInColumn(
+		ctx,
+		bson.M{"projectId": projectID, "status": status, "rank": bson.M{"$gt": rank}},
+		bson.D{{Key: "rank", Value: 1}},
+	)
+}
+
+// FindPrevInColumn returns the task directly above rank, or nil if there is none.
+func (r *taskRepository) FindPrevInColumn(ctx context.Context, projectID primitive.ObjectID, status, rank string) (*models.Task, error) {
+	return r.findOneInColumn(
+		ctx,
+		bson.M{"projectId": projectID, "status": status, "rank": bson.M{"$lt": rank}},
+		bson.D{{Key: "rank", Value: -1}},
+	)
+}
+
+func (r *taskRepository) findOneInColumn(ctx context.Context, filter bson.M, sort bson.D) (*models.Task, error) {
+	var task models.Task
+	err := r.collection.FindOne(ctx, notDeleted(filter), options.FindOne().SetSort(sort)).Decode(&task)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &task, nil
+}
+
+// WIP COUNTS
+
+func (r *taskRepository) CountInColumn(ctx context.Context, projectID primitive.ObjectID, status string) (int64, error) {
+	return r.collection.CountDocuments(ctx, notDeleted(bson.M{"projectId": projectID, "status": status}))
+}
+
+func (r *taskRepository) CountInColumnForAssignee(
+	ctx context.Context,
+	projectID primitive.ObjectID,
+	status string,
+	assignee primitive.ObjectID,
+) (int64, error) {
+	return r.collection.CountDocuments(ctx, notDeleted(bson.M{
+		"projectId":  projectID,
+		"status":     status,
+		"assignedTo": assignee,
+	}))
+}
+
+func (r *taskRepository) CountByStatusAndAssignee(ctx context.Context, projectID primitive.ObjectID) ([]ColumnCount, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: notDeleted(bson.M{"projectId": projectID})}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"status": "$status", "assignedTo": "$assignedTo"},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":        0,
+			"status":     "$_id.status",
+			"assignedTo": "$_id.assignedTo",
+			"count":      1,
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var counts []ColumnCount
+	if err := cursor.All(ctx, &counts); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// SPRINTS
+
+func (r *taskRepository) FindBySprintID(ctx context.Context, sprintID primitive.ObjectID) ([]models.Task, error) {
+	return r.findTasks(ctx, notDeleted(bson.M{"sprintId": sprintID}))
+}
+
+func (r *taskRepository) FindUnfinishedInSprint(ctx context.Context, sprintID primitive.ObjectID) ([]models.Task, error) {
+	return r.findTasks(ctx, notDeleted(bson.M{
+		"sprintId": sprintID,
+		"status":   bson.M{"$ne": models.TaskStatusDone},
+	}))
+}
+
+func (r *taskRepository) SetSprint(ctx context.Context, ids []primitive.ObjectID, sprintID primitive.ObjectID) (int64, error) {
+	result, err := r.collection.UpdateMany(
+		ctx,
+		notDeleted(bson.M{"_id": bson.M{"$in": ids}}),
+		bson.M{"$set": bson.M{"sprintId": sprintID, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// MILESTONES & EPICS
+//
+// field is the task field holding the link: "milestoneId" or "epicId".
+
+func (r *taskRepository) FindByMilestone(
+	ctx context.Context,
+	field string,
+	milestoneID primitive.ObjectID,
+) ([]models.Task, error) {
+	return r.findTasks(ctx, notDeleted(bson.M{field: milestoneID}))
+}
+
+func (r *taskRepository) CountProgress(
+	ctx context.Context,
+	projectID primitive.ObjectID,
+	field string,
+) ([]GroupProgress, error) {
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: notDeleted(bson.M{
+			"projectId": projectID,
+			field:       bson.M{"$nin": bson.A{nil, primitive.NilObjectID}},
+		})}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$" + field,
+			"total": bson.M{"$sum": 1},
+			"done": bson.M{"$sum": bson.M{
+				"$cond": bson.A{bson.M{"$eq": bson.A{"$status", models.TaskStatusDone}}, 1, 0},
+			}},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var progress []GroupProgress
+	if err := cursor.All(ctx, &progress); err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+// UnlinkMilestone clears the link on every task, trashed ones included, so
+// that a restored task does not point at a deleted milestone.
+func (r *taskRepository) UnlinkMilestone(
+	ctx context.Context,
+	field string,
+	milestoneID primitive.ObjectID,
+) (int64, error) {
+
+	result, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{field: milestoneID},
+		bson.M{"$set": bson.M{field: primitive.NilObjectID}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// TEAMS
+
+// FindTeamQueue lists live tasks assigned to the team itself or to any of
+// its members, soonest due first. An empty status matches every status.
+func (r *taskRepository) FindTeamQueue(
+	ctx context.Context,
+	teamID primitive.ObjectID,
+	memberIDs []primitive.ObjectID,
+	status string,
+) ([]models.Task, error) {
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{"assignedTeamId": teamID},
+		bson.M{"assignedTo": bson.M{"$in": memberIDs}},
+	}}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{
+		{Key: "dueDate", Value: 1},
+		{Key: "createdAt", Value: 1},
+		{Key: "_id", Value: 1},
+	})
+
+	cursor, err := r.collection.Find(ctx, notDeleted(filter), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tasks := []models.Task{}
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func (r *taskRepository) UnassignTeam(ctx context.Context, teamID primitive.ObjectID) (int64, error) {
+	return r.unassignTeam(ctx, bson.M{"assignedTeamId": teamID})
+}
+
+func (r *taskRepository) UnassignTeamInProject(ctx context.Context, projectID, teamID primitive.ObjectID) (int64, error) {
+	return r.unassignTeam(ctx, bson.M{"projectId": projectID, "assignedTeamId": teamID})
+}
+
+func (r *taskRepository) unassignTeam(ctx context.Context, filter bson.M) (int64, error) {
+	result, err := r.collection.UpdateMany(
+		ctx,
+		filter,
+		bson.M{"$set": bson.M{
+			"assignedTeamId": primitive.NilObjectID,
+			"updatedAt":      time.Now(),
+		}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
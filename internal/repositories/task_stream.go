@@ -0,0 +1,247 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	ErrChangeStreamsUnsupported = errors.New("change streams need MongoDB running as a replica set")
+	ErrInvalidResumeToken       = errors.New("invalid change stream resume token")
+	ErrResumeTokenExpired       = errors.New("change stream can no longer resume from this token")
+)
+
+// Server error codes the change stream reports.
+const (
+	codeChangeStreamHistoryLost = 286
+	codeNotReplicaSet           = 40573
+)
+
+var resumeTokenPattern = regexp.MustCompile(`^[0-9A-Fa-f]+$`)
+
+// TaskChange is one write to the tasks collection as read from a change
+// stream. Token identifies it and resumes the stream right after it. Tokens
+// of one collection compare as strings in stream order.
+type TaskChange struct {
+	Token         string
+	Operation     string
+	Task          models.Task
+	UpdatedFields []string
+	RemovedFields []string
+	At            time.Time
+}
+
+// TaskChangeStream delivers TaskChanges in order. Next blocks until a change
+// arrives, the stream fails or ctx is done. TryNext does not wait for new
+// writes and returns nil once the stream has caught up.
+type TaskChangeStream interface {
+	Next(ctx context.Context) (*TaskChange, error)
+	TryNext(ctx context.Context) (*TaskChange, error)
+	Close(ctx context.Context) error
+}
+
+// Watch opens a change stream of the inserts and updates of the workspace's
+// tasks, or of one project's when projectID is set. A project's stream also
+// carries the update that moves a task out of it. Soft deletes are updates;
+// purges are not reported. Because it reads the oplog, it sees writes made
+// through every server instance. With a resumeToken it starts right after
+// that change.
+func (r *taskRepository) Watch(
+	ctx context.Context,
+	projectID primitive.ObjectID,
+	resumeToken string,
+) (TaskChangeStream, error) {
+
+	tenant, ok := TenantFrom(ctx)
+	if !ok {
+		return nil, ErrNoTenant
+	}
+
+	match := bson.M{
+		"operationType":            bson.M{"$in": bson.A{"insert", "update", "replace"}},
+		"fullDocument.workspaceId": tenant,
+	}
+	if !projectID.IsZero() {
+		match["$or"] = bson.A{
+			bson.M{"fullDocument.projectId": projectID},
+			bson.M{"fullDocument.previousProjectId": projectID},
+		}
+	}
+
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	if resumeToken != "" {
+		if !resumeTokenPattern.MatchString(resumeToken) {
+			return nil, ErrInvalidResumeToken
+		}
+		opts.SetResumeAfter(bson.M{"_data": resumeToken})
+	}
+
+	return r.watch(ctx, match, opts, resumeToken != "")
+}
+
+// WatchAll opens a change stream of the task writes of every workspace,
+// starting now. It is meant to be opened once per server instance and
+// shared; each TaskChange carries the workspace of its task.
+func (r *taskRepository) WatchAll(ctx context.Context) (TaskChangeStream, error) {
+	match := bson.M{
+		"operationType": bson.M{"$in": bson.A{"insert", "update", "replace"}},
+	}
+
+	return r.watch(ctx, match, options.ChangeStream().SetFullDocument(options.UpdateLookup), false)
+}
+
+func (r *taskRepository) watch(
+	ctx context.Context,
+	match bson.M,
+	opts *options.ChangeStreamOptions,
+	resuming bool,
+) (TaskChangeStream, error) {
+
+	stream, err := r.collection.Collection.Watch(ctx, mongo.Pipeline{{{Key: "$match", Value: match}}}, opts)
+	if err != nil {
+		return nil, changeStreamError(err, resuming)
+	}
+
+	return &taskChangeStream{stream: stream}, nil
+}
+
+type taskChangeStream struct {
+	stream *mongo.ChangeStream
+}
+
+type taskChangeEvent struct {
+	OperationType     string              `bson:"operationType"`
+	ClusterTime       primitive.Timestamp `bson:"clusterTime"`
+	FullDocument      *models.Task        `bson:"fullDocument"`
+	UpdateDescription struct {
+		UpdatedFields bson.Raw `bson:"updatedFields"`
+		RemovedFields []string `bson:"removedFields"`
+	} `bson:"updateDescription"`
+}
+
+func (s *taskChangeStream) Next(ctx context.Context) (*TaskChange, error) {
+	for s.stream.Next(ctx) {
+		change, err := s.decode()
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			return change, nil
+		}
+	}
+
+	if err := s.stream.Err(); err != nil {
+		return nil, changeStreamError(err, false)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+func (s *taskChangeStream) TryNext(ctx context.Context) (*TaskChange, error) {
+	for s.stream.TryNext(ctx) {
+		change, err := s.decode()
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			return change, nil
+		}
+	}
+
+	if err := s.stream.Err(); err != nil {
+		return nil, changeStreamError(err, false)
+	}
+	return nil, ctx.Err()
+}
+
+// decode reads the current event, or returns nil for a task that was purged
+// before the lookup could read it.
+func (s *taskChangeStream) decode() (*TaskChange, error) {
+	var event taskChangeEvent
+	if err := s.stream.Decode(&event); err != nil {
+		return nil, err
+	}
+
+	if event.FullDocument == nil {
+		return nil, nil
+	}
+
+	change := &TaskChange{
+		Token:         s.stream.ResumeToken().Lookup("_data").StringValue(),
+		Operation:     event.OperationType,
+		Task:          *event.FullDocument,
+		RemovedFields: topLevelFields(event.UpdateDescription.RemovedFields),
+		At:            time.Unix(int64(event.ClusterTime.T), 0).UTC(),
+	}
+
+	if event.UpdateDescription.UpdatedFields != nil {
+		elements, err := event.UpdateDescription.UpdatedFields.Elements()
+		if err != nil {
+			return nil, err
+		}
+		keys := make([]string, len(elements))
+		for i, e := range elements {
+			keys[i] = e.Key()
+		}
+		change.UpdatedFields = topLevelFields(keys)
+	}
+
+	return change, nil
+}
+
+func (s *taskChangeStream) Close(ctx context.Context) error {
+	return s.stream.Close(ctx)
+}
+
+// topLevelFields turns paths such as "labels.2" into their top-level field,
+// without duplicates.
+func topLevelFields(paths []string) []string {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(paths))
+	fields := make([]string, 0, len(paths))
+	for _, p := range paths {
+		field, _, _ := strings.Cut(p, ".")
+		if !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// changeStreamError maps server errors; resuming is set while opening a
+// stream from a token.
+func changeStreamError(err error, resuming bool) error {
+	var cmdErr mongo.CommandError
+	if !errors.As(err, &cmdErr) {
+		return err
+	}
+
+	switch {
+	case cmdErr.Code == codeNotReplicaSet:
+		return ErrChangeStreamsUnsupported
+	case cmdErr.Code == codeChangeStreamHistoryLost:
+		return ErrResumeTokenExpired
+	case resuming:
+		// Opening with a token that does not decode, or that belongs to
+		// another collection, is rejected with assorted parse errors.
+		return ErrInvalidResumeToken
+	}
+	return err
+}
@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type TeamRepository interface {
+	Create(ctx context.Context, team *models.Team) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Team, error)
+	FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.Team, error)
+	FindAll(ctx context.Context) ([]models.Team, error)
+	UpdateByID(ctx context.Context, id primitive.ObjectID, update bson.M) error
+	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+
+	AddMember(ctx context.Context, teamID, userID primitive.ObjectID) (bool, error)
+	RemoveMember(ctx context.Context, teamID, userID primitive.ObjectID) (bool, error)
+	RemoveUserFromAll(ctx context.Context, userID primitive.ObjectID) (int64, error)
+}
+
+type teamRepository struct {
+	collection *tenantCollection
+}
+
+func NewTeamRepository(db *mongo.Database) TeamRepository {
+	return &teamRepository{
+		collection: newTenantCollection(db, "teams"),
+	}
+}
+
+func (r *teamRepository) Create(ctx context.Context, team *models.Team) error {
+	result, err := r.collection.InsertOne(ctx, team)
+	if err != nil {
+		return err
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		team.ID = oid
+	}
+
+	return nil
+}
+
+func (r *teamRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Team, error) {
+	var team models.Team
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&team)
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+func (r *teamRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.Team, error) {
+	return r.findTeams(ctx, bson.M{"_id": bson.M{"$in": ids}})
+}
+
+func (r *teamRepository) FindAll(ctx context.Context) ([]models.Team, error) {
+	return r.findTeams(ctx, bson.M{})
+}
+
+func (r *teamRepository) findTeams(ctx context.Context, filter bson.M) ([]models.Team, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	teams := []models.Team{}
+	if err := cursor.All(ctx, &teams); err != nil {
+		return nil, err
+	}
+	return teams, nil
+}
+
+func (r *teamRepository) UpdateByID(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": update})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *teamRepository) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// MEMBERSHIP
+
+// AddMember reports false if the user already is a member.
+func (r *teamRepository) AddMember(ctx context.Context, teamID, userID primitive.ObjectID) (bool, error) {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": teamID, "memberIds": bson.M{"$ne": userID}},
+		bson.M{
+			"$push": bson.M{"memberIds": userID},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// RemoveMember reports false if the user is not a member. The lead cannot
+// be removed this way.
+func (r *teamRepository) RemoveMember(ctx context.Context, teamID, userID primitive.ObjectID) (bool, error) {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": teamID, "memberIds": userID, "leadId": bson.M{"$ne": userID}},
+		bson.M{
+			"$pull": bson.M{"memberIds": userID},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// RemoveUserFromAll drops the user from every team and leaves teams they
+// led without a lead.
+func (r *teamRepository) RemoveUserFromAll(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	if _, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"leadId": userID},
+		bson.M{"$set": bson.M{"leadId": primitive.NilObjectID}},
+	); err != nil {
+		return 0, err
+	}
+
+	result, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"memberIds": userID},
+		bson.M{"$pull": bson.M{"memberIds": userID}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,297 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Every document that belongs to a workspace carries a workspaceId. Instead of
+// trusting each query to remember it, repositories talk to their collection
+// through tenantCollection, which reads the workspace from ctx and adds it to
+// every filter, pipeline and inserted document. A ctx without a workspace is
+// rejected, so a forgotten WithTenant fails loudly instead of leaking data.
+
+var (
+	ErrNoTenant      = errors.New("no workspace in context")
+	ErrTenantMissing = errors.New("document cannot be assigned to a workspace")
+)
+
+type tenantKey struct{}
+
+// allTenants marks a system context that may read and write across
+// workspaces, e.g. the trash purge loop.
+var allTenants = primitive.NilObjectID
+
+// WithTenant scopes every repository call made with the returned context to
+// the given workspace.
+func WithTenant(ctx context.Context, workspaceID primitive.ObjectID) context.Context {
+	return context.WithValue(ctx, tenantKey{}, workspaceID)
+}
+
+// WithAllTenants returns a context for background jobs that operate on every
+// workspace. It must never be derived from a request.
+func WithAllTenants(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tenantKey{}, allTenants)
+}
+
+// TenantFrom returns the workspace ctx is scoped to.
+func TenantFrom(ctx context.Context) (primitive.ObjectID, bool) {
+	id, ok := ctx.Value(tenantKey{}).(primitive.ObjectID)
+	if !ok || id == allTenants {
+		return primitive.NilObjectID, false
+	}
+	return id, true
+}
+
+// tenantScoped is implemented by models stored in a workspace.
+type tenantScoped interface {
+	SetWorkspaceID(id primitive.ObjectID)
+}
+
+type tenantCollection struct {
+	*mongo.Collection
+}
+
+func newTenantCollection(db *mongo.Database, name string) *tenantCollection {
+	return &tenantCollection{Collection: db.Collection(name)}
+}
+
+// scope returns the workspace of ctx, or allTenants for a system context.
+func scope(ctx context.Context) (primitive.ObjectID, error) {
+	id, ok := ctx.Value(tenantKey{}).(primitive.ObjectID)
+	if !ok {
+		return primitive.NilObjectID, ErrNoTenant
+	}
+	return id, nil
+}
+
+func scopedFilter(ctx context.Context, filter interface{}) (interface{}, error) {
+	tenant, err := scope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tenant == allTenants {
+		return filter, nil
+	}
+
+	if m, ok := filter.(bson.M); ok {
+		scoped := make(bson.M, len(m)+1)
+		for k, v := range m {
+			scoped[k] = v
+		}
+		scoped["workspaceId"] = tenant
+		return scoped, nil
+	}
+	return bson.M{"$and": bson.A{filter, bson.M{"workspaceId": tenant}}}, nil
+}
+
+// scopedUpdate keeps updates from moving a document to another workspace.
+func scopedUpdate(update interface{}) interface{} {
+	m, ok := update.(bson.M)
+	if !ok {
+		return update
+	}
+	set, ok := m["$set"].(bson.M)
+	if !ok {
+		return update
+	}
+	if _, ok := set["workspaceId"]; !ok {
+		return update
+	}
+
+	cleaned := make(bson.M, len(set))
+	for k, v := range set {
+		if k != "workspaceId" {
+			cleaned[k] = v
+		}
+	}
+	scoped := make(bson.M, len(m))
+	for k, v := range m {
+		scoped[k] = v
+	}
+	scoped["$set"] = cleaned
+	return scoped
+}
+
+func stamp(ctx context.Context, document interface{}) error {
+	tenant, ok := TenantFrom(ctx)
+	if !ok {
+		return ErrNoTenant
+	}
+	doc, ok := document.(tenantScoped)
+	if !ok {
+		return ErrTenantMissing
+	}
+	doc.SetWorkspaceID(tenant)
+	return nil
+}
+
+func (c *tenantCollection) InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	if err := stamp(ctx, document); err != nil {
+		return nil, err
+	}
+	return c.Collection.InsertOne(ctx, document, opts...)
+}
+
+func (c *tenantCollection) InsertMany(ctx context.Context, documents []interface{}, opts ...*options.InsertManyOptions) (*mongo.InsertManyResult, error) {
+	for _, document := range documents {
+		if err := stamp(ctx, document); err != nil {
+			return nil, err
+		}
+	}
+	return c.Collection.InsertMany(ctx, documents, opts...)
+}
+
+func (c *tenantCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	scoped, err := scopedFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return c.Collection.Find(ctx, scoped, opts...)
+}
+
+func (c *tenantCollection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	scoped, err := scopedFilter(ctx, filter)
+	if err != nil {
+		return mongo.NewSingleResultFromDocument(bson.M{}, err, nil)
+	}
+	return c.Collection.FindOne(ctx, scoped, opts...)
+}
+
+func (c *tenantCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	scoped, err := scopedFilter(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return c.Collection.CountDocuments(ctx, scoped, opts...)
+}
+
+func (c *tenantCollection) Distinct(ctx context.Context, fieldName string, filter interface{}, opts ...*options.DistinctOptions) ([]interface{}, error) {
+	scoped, err := scopedFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return c.Collection.Distinct(ctx, fieldName, scoped, opts...)
+}
+
+func (c *tenantCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	scoped, err := scopedFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return c.Collection.UpdateOne(ctx, scoped, scopedUpdate(update), opts...)
+}
+
+func (c *tenantCollection) UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	scoped, err := scopedFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return c.Collection.UpdateMany(ctx, scoped, scopedUpdate(update), opts...)
+}
+
+func (c *tenantCollection) DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	scoped, err := scopedFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return c.Collection.DeleteOne(ctx, scoped, opts...)
+}
+
+func (c *tenantCollection) DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	scoped, err := scopedFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return c.Collection.DeleteMany(ctx, scoped, opts...)
+}
+
+// BulkWrite scopes every write model the same way as the single-document
+// methods do.
+func (c *tenantCollection) BulkWrite(ctx context.Context, writes []mongo.WriteModel, opts ...*options.BulkWriteOptions) (*mongo.BulkWriteResult, error) {
+	scoped := make([]mongo.WriteModel, len(writes))
+
+	for i, write := range writes {
+		switch m := write.(type) {
+		case *mongo.InsertOneModel:
+			if err := stamp(ctx, m.Document); err != nil {
+				return nil, err
+			}
+			scoped[i] = m
+		case *mongo.UpdateOneModel:
+			filter, err := scopedFilter(ctx, m.Filter)
+			if err != nil {
+				return nil, err
+			}
+			model := *m
+			model.Filter, model.Update = filter, scopedUpdate(m.Update)
+			scoped[i] = &model
+		case *mongo.UpdateManyModel:
+			filter, err := scopedFilter(ctx, m.Filter)
+			if err != nil {
+				return nil, err
+			}
+			model := *m
+			model.Filter, model.Update = filter, scopedUpdate(m.Update)
+			scoped[i] = &model
+		case *mongo.DeleteOneModel:
+			filter, err := scopedFilter(ctx, m.Filter)
+			if err != nil {
+				return nil, err
+			}
+			model := *m
+			model.Filter = filter
+			scoped[i] = &model
+		case *mongo.DeleteManyModel:
+			filter, err := scopedFilter(ctx, m.Filter)
+			if err != nil {
+				return nil, err
+			}
+			model := *m
+			model.Filter = filter
+			scoped[i] = &model
+		default:
+			return nil, fmt.Errorf("tenant scoped bulk writes do not support %T", write)
+		}
+	}
+
+	return c.Collection.BulkWrite(ctx, scoped, opts...)
+}
+
+// Aggregate prepends a workspace $match. Pipelines that $lookup other
+// collections must match workspaceId themselves, see tenantLookupMatch.
+func (c *tenantCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (*mongo.Cursor, error) {
+	tenant, err := scope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tenant != allTenants {
+		stages, ok := pipeline.(mongo.Pipeline)
+		if !ok {
+			return nil, errors.New("tenant scoped aggregations require a mongo.Pipeline")
+		}
+		scoped := make(mongo.Pipeline, 0, len(stages)+1)
+		scoped = append(scoped, bson.D{{Key: "$match", Value: bson.M{"workspaceId": tenant}}})
+		pipeline = append(scoped, stages...)
+	}
+	return c.Collection.Aggregate(ctx, pipeline, opts...)
+}
+
+// tenantLookupMatch is the $match condition a $lookup sub-pipeline needs to
+// stay inside the caller's workspace.
+func tenantLookupMatch(ctx context.Context) (bson.M, error) {
+	tenant, err := scope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tenant == allTenants {
+		return bson.M{}, nil
+	}
+	return bson.M{"workspaceId": tenant}, nil
+}
@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTenantFrom(t *testing.T) {
+	workspace := primitive.NewObjectID()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want primitive.ObjectID
+		ok   bool
+	}{
+		{"workspace", WithTenant(context.Background(), workspace), workspace, true},
+		{"none", context.Background(), primitive.NilObjectID, false},
+		{"all workspaces", WithAllTenants(context.Background()), primitive.NilObjectID, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := TenantFrom(tt.ctx)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("TenantFrom = %v, %v; want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestScopedFilter(t *testing.T) {
+	workspace := primitive.NewObjectID()
+	ctx := WithTenant(context.Background(), workspace)
+
+	t.Run("adds the workspace to a bson.M", func(t *testing.T) {
+		other := primitive.NewObjectID()
+		filter := bson.M{"status": "todo", "workspaceId": other}
+
+		got, err := scopedFilter(ctx, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := bson.M{"status": "todo", "workspaceId": workspace}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("scopedFilter = %v, want %v", got, want)
+		}
+		if filter["workspaceId"] != other {
+			t.Error("scopedFilter changed the caller's filter")
+		}
+	})
+
+	t.Run("wraps other filters in $and", func(t *testing.T) {
+		filter := bson.D{{Key: "status", Value: "todo"}}
+
+		got, err := scopedFilter(ctx, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := bson.M{"$and": bson.A{filter, bson.M{"workspaceId": workspace}}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("scopedFilter = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("leaves system contexts unscoped", func(t *testing.T) {
+		filter := bson.M{"status": "todo"}
+
+		got, err := scopedFilter(WithAllTenants(context.Background()), filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, filter) {
+			t.Errorf("scopedFilter = %v, want %v", got, filter)
+		}
+	})
+
+	t.Run("rejects a context without a workspace", func(t *testing.T) {
+		if _, err := scopedFilter(context.Background(), bson.M{}); !errors.Is(err, ErrNoTenant) {
+			t.Errorf("err = %v, want ErrNoTenant", err)
+		}
+	})
+}
+
+func TestScopedUpdate(t *testing.T) {
+	tests := []struct {
+		name   string
+		update interface{}
+		want   interface{}
+	}{
+		{
+			name:   "drops workspaceId from $set",
+			update: bson.M{"$set": bson.M{"title": "x", "workspaceId": primitive.NilObjectID}, "$inc": bson.M{"n": 1}},
+			want:   bson.M{"$set": bson.M{"title": "x"}, "$inc": bson.M{"n": 1}},
+		},
+		{
+			name:   "keeps other updates",
+			update: bson.M{"$set": bson.M{"title": "x"}},
+			want:   bson.M{"$set": bson.M{"title": "x"}},
+		},
+		{
+			name:   "keeps pipelines",
+			update: bson.A{bson.M{"$set": bson.M{"title": "x"}}},
+			want:   bson.A{bson.M{"$set": bson.M{"title": "x"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scopedUpdate(tt.update); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("scopedUpdate = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStamp(t *testing.T) {
+	workspace := primitive.NewObjectID()
+
+	task := &models.Task{}
+	if err := stamp(WithTenant(context.Background(), workspace), task); err != nil {
+		t.Fatal(err)
+	}
+	if task.WorkspaceID != workspace {
+		t.Errorf("WorkspaceID = %v, want %v", task.WorkspaceID, workspace)
+	}
+
+	if err := stamp(WithAllTenants(context.Background()), &models.Task{}); !errors.Is(err, ErrNoTenant) {
+		t.Errorf("system context: err = %v, want ErrNoTenant", err)
+	}
+	if err := stamp(WithTenant(context.Background(), workspace), bson.M{}); !errors.Is(err, ErrTenantMissing) {
+		t.Errorf("untyped document: err = %v, want ErrTenantMissing", err)
+	}
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// UnitOfWork groups repository calls into a single MongoDB multi-document
+// transaction. Repositories take part in it simply by being called with the
+// ctx handed to fn, so no repository needs to know about sessions.
+//
+// Transactions require MongoDB to run as a replica set (a single-node replica
+// set is enough for local development).
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+	Snapshot(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type mongoUnitOfWork struct {
+	client *mongo.Client
+}
+
+func NewUnitOfWork(client *mongo.Client) UnitOfWork {
+	return &mongoUnitOfWork{client: client}
+}
+
+// Do commits when fn returns nil and aborts otherwise. fn may be retried by
+// the driver on transient transaction errors, so it must not have side effects
+// outside the database. Calls nested inside a running transaction join it.
+func (u *mongoUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if session := mongo.SessionFromContext(ctx); session != nil {
+		return fn(ctx)
+	}
+
+	session, err := u.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
+
+// Snapshot runs fn with every read made through ctx seeing the database as
+// of one point in time, without the size limits of a transaction. It is
+// meant for long reads such as exports; fn must only read, and has to
+// finish within the server's snapshot history window
+// (minSnapshotHistoryWindowInSeconds, 5 minutes by default).
+func (u *mongoUnitOfWork) Snapshot(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := u.client.StartSession(options.Session().SetSnapshot(true))
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	return mongo.WithSession(ctx, session, func(sc mongo.SessionContext) error {
+		return fn(sc)
+	})
+}
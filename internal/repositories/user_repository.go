@@ -2,6 +2,8 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"Concurrent_Task_Management_System/internal/models"
 
@@ -14,18 +16,66 @@ type UserRepository interface {
 	Create(ctx context.Context, user *models.User) error
 	FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error)
 	FindAll(ctx context.Context) ([]models.User, error)
+	FindByStatus(ctx context.Context, status string) ([]models.User, error)
 	UpdateByID(ctx context.Context, id primitive.ObjectID, update bson.M) error
-	DeleteByID(ctx context.Context, id primitive.ObjectID) error
+	DeleteByID(ctx context.Context, id primitive.ObjectID, deletedBy primitive.ObjectID) error
 	FindByUserID(ctx context.Context, userID string) (*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.User, error)
+	FindByCalendarTokenHash(ctx context.Context, hash string) (*models.User, error)
+	SetCalendarTokenHash(ctx context.Context, id primitive.ObjectID, hash string) error
+
+	FindDeleted(ctx context.Context) ([]models.User, error)
+	FindDeletedBy(ctx context.Context, deletedBy primitive.ObjectID) ([]models.User, error)
+	FindDeletedByID(ctx context.Context, id primitive.ObjectID) (*models.User, error)
+	RestoreByID(ctx context.Context, id primitive.ObjectID) error
+	PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+
+	AddTeam(ctx context.Context, userID, teamID primitive.ObjectID) error
+	RemoveTeam(ctx context.Context, userID, teamID primitive.ObjectID) error
+	RemoveTeamFromAll(ctx context.Context, teamID primitive.ObjectID) (int64, error)
+}
+
+// codeDuplicateKey is the server error code of a unique index violation.
+const codeDuplicateKey = 11000
+
+// DuplicateKeyField names the field, besides the workspace, of the unique
+// index that a duplicate key error hit, such as "email" or "user_id". It
+// returns "" for any other error.
+func DuplicateKeyField(err error) string {
+	var we mongo.WriteException
+	if !errors.As(err, &we) {
+		return ""
+	}
+
+	for _, e := range we.WriteErrors {
+		if e.Code != codeDuplicateKey {
+			continue
+		}
+		pattern, ok := e.Raw.Lookup("keyPattern").DocumentOK()
+		if !ok {
+			continue
+		}
+		elements, err := pattern.Elements()
+		if err != nil {
+			continue
+		}
+		for _, element := range elements {
+			if element.Key() != "workspaceId" {
+				return element.Key()
+			}
+		}
+	}
+	return ""
 }
 
 type userRepository struct {
-	collection *mongo.Collection
+	collection *tenantCollection
 }
 
 func NewUserRepository(db *mongo.Database) UserRepository {
 	return &userRepository{
-		collection: db.Collection("users"),
+		collection: newTenantCollection(db, "users"),
 	}
 }
 func (r *userRepository) Create(ctx context.Context, user *models.User) error {
@@ -44,7 +94,7 @@ func (r *userRepository) Create(ctx context.Context, user *models.User) error {
 
 func (r *userRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error) {
 	var user models.User
-	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&user)
+	err := r.collection.FindOne(ctx, notDeleted(bson.M{"_id": id})).Decode(&user)
 	if err != nil {
 		return nil, err
 	}
@@ -53,15 +103,55 @@ func (r *userRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*
 
 func (r *userRepository) FindByUserID(ctx context.Context, userID string) (*models.User, error) {
 	var user models.User
-	err := r.collection.FindOne(ctx, bson.M{"user_id": userID}).Decode(&user)
+	err := r.collection.FindOne(ctx, notDeleted(bson.M{"user_id": userID})).Decode(&user)
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	err := r.collection.FindOne(ctx, notDeleted(bson.M{"email": email})).Decode(&user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) FindByCalendarTokenHash(ctx context.Context, hash string) (*models.User, error) {
+	var user models.User
+	err := r.collection.FindOne(ctx, notDeleted(bson.M{"calendarTokenHash": hash})).Decode(&user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// SetCalendarTokenHash replaces the user's calendar token; an empty hash
+// removes it.
+func (r *userRepository) SetCalendarTokenHash(ctx context.Context, id primitive.ObjectID, hash string) error {
+	update := bson.M{"$set": bson.M{"calendarTokenHash": hash}}
+	if hash == "" {
+		update = bson.M{"$unset": bson.M{"calendarTokenHash": ""}}
+	}
+
+	result, err := r.collection.UpdateOne(ctx, notDeleted(bson.M{"_id": id}), update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *userRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.User, error) {
+	return r.findUsers(ctx, notDeleted(bson.M{"_id": bson.M{"$in": ids}}))
+}
+
 func (r *userRepository) FindAll(ctx context.Context) ([]models.User, error) {
-	cursor, err := r.collection.Find(ctx, bson.M{})
+	cursor, err := r.collection.Find(ctx, notDeleted(bson.M{}))
 	if err != nil {
 		return nil, err
 	}
@@ -75,16 +165,123 @@ func (r *userRepository) FindAll(ctx context.Context) ([]models.User, error) {
 	return users, nil
 }
 
+// FindByStatus also returns users without a status when asked for active
+// ones.
+func (r *userRepository) FindByStatus(ctx context.Context, status string) ([]models.User, error) {
+	filter := bson.M{"status": status}
+	if status == models.UserStatusActive {
+		filter = bson.M{"status": bson.M{"$in": bson.A{status, nil}}}
+	}
+	return r.findUsers(ctx, notDeleted(filter))
+}
+
 func (r *userRepository) UpdateByID(ctx context.Context, id primitive.ObjectID, update bson.M) error {
 	_, err := r.collection.UpdateOne(
 		ctx,
-		bson.M{"_id": id},
+		notDeleted(bson.M{"_id": id}),
 		bson.M{"$set": update},
 	)
 	return err
 }
 
-func (r *userRepository) DeleteByID(ctx context.Context, id primitive.ObjectID) error {
-	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+func (r *userRepository) DeleteByID(ctx context.Context, id primitive.ObjectID, deletedBy primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(
+		ctx,
+		notDeleted(bson.M{"_id": id}),
+		softDeleteUpdate(deletedBy),
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// TRASH
+func (r *userRepository) FindDeleted(ctx context.Context) ([]models.User, error) {
+	return r.findUsers(ctx, onlyDeleted(bson.M{}))
+}
+
+func (r *userRepository) FindDeletedBy(ctx context.Context, deletedBy primitive.ObjectID) ([]models.User, error) {
+	return r.findUsers(ctx, onlyDeleted(bson.M{"deletedBy": deletedBy}))
+}
+
+func (r *userRepository) FindDeletedByID(ctx context.Context, id primitive.ObjectID) (*models.User, error) {
+	var user models.User
+	err := r.collection.FindOne(ctx, onlyDeleted(bson.M{"_id": id})).Decode(&user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) RestoreByID(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.collection.UpdateOne(ctx, onlyDeleted(bson.M{"_id": id}), restoreUpdate())
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+func (r *userRepository) PurgeDeletedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"deletedAt": bson.M{"$lt": cutoff}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (r *userRepository) findUsers(ctx context.Context, filter bson.M) ([]models.User, error) {
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []models.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// TEAMS
+
+// User.TeamIDs mirrors Team.MemberIDs; TeamService keeps both in step inside
+// one transaction.
+
+func (r *userRepository) AddTeam(ctx context.Context, userID, teamID primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": userID},
+		bson.M{"$addToSet": bson.M{"teamIds": teamID}},
+	)
 	return err
 }
+
+func (r *userRepository) RemoveTeam(ctx context.Context, userID, teamID primitive.ObjectID) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": userID},
+		bson.M{"$pull": bson.M{"teamIds": teamID}},
+	)
+	return err
+}
+
+func (r *userRepository) RemoveTeamFromAll(ctx context.Context, teamID primitive.ObjectID) (int64, error) {
+	result, err := r.collection.UpdateMany(
+		ctx,
+		bson.M{"teamIds": teamID},
+		bson.M{"$pull": bson.M{"teamIds": teamID}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,174 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// tenantCollections lists every collection whose documents carry a
+// workspaceId.
+var tenantCollections = []string{
+	"users",
+	"projects",
+	"tasks",
+	"sprints",
+	"task_events",
+	"milestones",
+	"project_templates",
+	"teams",
+	"invitations",
+}
+
+// WorkspaceRepository is the only repository that is not tenant scoped: it
+// is what resolves a tenant in the first place.
+type WorkspaceRepository interface {
+	Create(ctx context.Context, workspace *models.Workspace) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Workspace, error)
+	FindBySlug(ctx context.Context, slug string) (*models.Workspace, error)
+	FindAll(ctx context.Context) ([]models.Workspace, error)
+	AdoptOrphans(ctx context.Context, id primitive.ObjectID) (int64, error)
+	CountDocuments(ctx context.Context, id primitive.ObjectID) (map[string]int64, error)
+	CountBy(ctx context.Context, id primitive.ObjectID, collection, field string) (map[string]int64, error)
+}
+
+type workspaceRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewWorkspaceRepository(db *mongo.Database) WorkspaceRepository {
+	return &workspaceRepository{
+		db:         db,
+		collection: db.Collection("workspaces"),
+	}
+}
+
+func (r *workspaceRepository) Create(ctx context.Context, workspace *models.Workspace) error {
+	result, err := r.collection.InsertOne(ctx, workspace)
+	if err != nil {
+		return err
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		workspace.ID = oid
+	}
+
+	return nil
+}
+
+func (r *workspaceRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Workspace, error) {
+	var workspace models.Workspace
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&workspace)
+	if err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+func (r *workspaceRepository) FindBySlug(ctx context.Context, slug string) (*models.Workspace, error) {
+	var workspace models.Workspace
+	err := r.collection.FindOne(ctx, bson.M{"slug": slug}).Decode(&workspace)
+	if err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+func (r *workspaceRepository) FindAll(ctx context.Context) ([]models.Workspace, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	workspaces := []models.Workspace{}
+	if err := cursor.All(ctx, &workspaces); err != nil {
+		return nil, err
+	}
+	return workspaces, nil
+}
+
+// AdoptOrphans moves documents written before workspaces existed into the
+// given workspace. It returns how many documents were updated.
+func (r *workspaceRepository) AdoptOrphans(ctx context.Context, id primitive.ObjectID) (int64, error) {
+	var total int64
+
+	for _, name := range tenantCollections {
+		result, err := r.db.Collection(name).UpdateMany(ctx,
+			bson.M{"workspaceId": bson.M{"$exists": false}},
+			bson.M{"$set": bson.M{"workspaceId": id}},
+		)
+		if err != nil {
+			return total, err
+		}
+		total += result.ModifiedCount
+	}
+
+	return total, nil
+}
+
+// CountDocuments counts the workspace's documents in every tenant
+// collection, trashed ones included.
+func (r *workspaceRepository) CountDocuments(ctx context.Context, id primitive.ObjectID) (map[string]int64, error) {
+	counts := make(map[string]int64, len(tenantCollections))
+
+	for _, name := range tenantCollections {
+		n, err := r.db.Collection(name).CountDocuments(ctx, bson.M{"workspaceId": id})
+		if err != nil {
+			return nil, err
+		}
+		counts[name] = n
+	}
+
+	return counts, nil
+}
+
+// CountBy groups the workspace's live documents in collection by field. A
+// missing field is counted under "".
+func (r *workspaceRepository) CountBy(
+	ctx context.Context,
+	id primitive.ObjectID,
+	collection string,
+	field string,
+) (map[string]int64, error) {
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"workspaceId": id,
+			"deletedAt":   bson.M{"$exists": false},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"$ifNull": bson.A{"$" + field, ""}},
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+
+	cursor, err := r.db.Collection(collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rows []struct {
+		Key   interface{} `bson:"_id"`
+		Count int64       `bson:"count"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[fmt.Sprint(row.Key)] += row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"Concurrent_Task_Management_System/internal/handlers"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterCalendarRoutes(router *mux.Router, calendarHandler *handlers.CalendarHandler) {
+
+	router.HandleFunc("/users/{id}/calendar-token", calendarHandler.IssueFeedToken).Methods("POST")
+	router.HandleFunc("/users/{id}/calendar-token", calendarHandler.RevokeFeedToken).Methods("DELETE")
+
+	router.HandleFunc("/calendar/tasks.ics", calendarHandler.UserFeed).Methods("GET")
+	router.HandleFunc("/calendar/projects/{id}.ics", calendarHandler.ProjectFeed).Methods("GET")
+}
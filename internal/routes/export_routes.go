@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"Concurrent_Task_Management_System/internal/handlers"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterExportRoutes(router *mux.Router, exportHandler *handlers.ExportHandler) {
+
+	router.HandleFunc("/projects/import", exportHandler.ImportProject).Methods("POST")
+	router.HandleFunc("/projects/import/trello", exportHandler.ImportTrelloBoard).Methods("POST")
+	router.HandleFunc("/projects/{id}/export", exportHandler.ExportProject).Methods("GET")
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"Concurrent_Task_Management_System/internal/handlers"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterInvitationRoutes(router *mux.Router, invitationHandler *handlers.InvitationHandler) {
+
+	router.HandleFunc("/invitations", invitationHandler.Invite).Methods("POST")
+	router.HandleFunc("/invitations", invitationHandler.GetPendingInvitations).Methods("GET")
+	router.HandleFunc("/invitations/accept", invitationHandler.AcceptInvitation).Methods("POST")
+	router.HandleFunc("/invitations/{id}", invitationHandler.RevokeInvitation).Methods("DELETE")
+}
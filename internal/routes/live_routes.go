@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"Concurrent_Task_Management_System/internal/handlers"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterLiveRoutes(router *mux.Router, liveHandler *handlers.LiveHandler) {
+
+	router.HandleFunc("/projects/{id}/events", liveHandler.ProjectEvents).Methods("GET")
+	router.HandleFunc("/users/{id}/events", liveHandler.UserEvents).Methods("GET")
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"Concurrent_Task_Management_System/internal/handlers"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterMilestoneRoutes(router *mux.Router, milestoneHandler *handlers.MilestoneHandler) {
+
+	router.HandleFunc("/projects/{id}/milestones", milestoneHandler.CreateMilestone).Methods("POST")
+	router.HandleFunc("/projects/{id}/milestones", milestoneHandler.GetMilestones).Methods("GET")
+
+	router.HandleFunc("/milestones/{id}", milestoneHandler.GetMilestone).Methods("GET")
+	router.HandleFunc("/milestones/{id}", milestoneHandler.UpdateMilestone).Methods("PUT")
+	router.HandleFunc("/milestones/{id}", milestoneHandler.DeleteMilestone).Methods("DELETE")
+}
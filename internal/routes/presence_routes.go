@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"Concurrent_Task_Management_System/internal/handlers"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterPresenceRoutes(router *mux.Router, presenceHandler *handlers.PresenceHandler) {
+
+	router.HandleFunc("/presence", presenceHandler.Presence).Methods("GET")
+}
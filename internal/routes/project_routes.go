@@ -14,6 +14,18 @@ func RegisterProjectRoutes(router *mux.Router, projectHandler *handlers.ProjectH
 	router.HandleFunc("/projects/{id}", projectHandler.GetProjectByID).Methods("GET")
 	router.HandleFunc("/projects/{id}", projectHandler.UpdateProject).Methods("PUT")
 	router.HandleFunc("/projects/{id}", projectHandler.DeleteProject).Methods("DELETE")
+	router.HandleFunc("/projects/{id}/archive", projectHandler.ArchiveProject).Methods("POST")
+	router.HandleFunc("/projects/{id}/unarchive", projectHandler.UnarchiveProject).Methods("POST")
+
+	router.HandleFunc("/projects/{id}/members", projectHandler.GetMembers).Methods("GET")
+	router.HandleFunc("/projects/{id}/members", projectHandler.AddMember).Methods("POST")
+	router.HandleFunc("/projects/{id}/members/{userId}", projectHandler.UpdateMemberRole).Methods("PUT")
+	router.HandleFunc("/projects/{id}/members/{userId}", projectHandler.RemoveMember).Methods("DELETE")
+	router.HandleFunc("/projects/{id}/teams", projectHandler.AddTeam).Methods("POST")
+	router.HandleFunc("/projects/{id}/teams/{teamId}", projectHandler.UpdateTeamRole).Methods("PUT")
+	router.HandleFunc("/projects/{id}/teams/{teamId}", projectHandler.RemoveTeam).Methods("DELETE")
+	router.HandleFunc("/projects/{id}/transfer-ownership", projectHandler.TransferOwnership).Methods("POST")
+	router.HandleFunc("/projects/{id}/wip-limits", projectHandler.SetWIPLimits).Methods("PUT")
 
 	router.HandleFunc("/users/{userId}/projects", projectHandler.GetProjectsByUser).Methods("GET")
 }
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"Concurrent_Task_Management_System/internal/handlers"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterSprintRoutes(router *mux.Router, sprintHandler *handlers.SprintHandler) {
+
+	router.HandleFunc("/projects/{id}/sprints", sprintHandler.CreateSprint).Methods("POST")
+	router.HandleFunc("/projects/{id}/sprints", sprintHandler.GetSprints).Methods("GET")
+
+	router.HandleFunc("/sprints/{id}", sprintHandler.GetSprint).Methods("GET")
+	router.HandleFunc("/sprints/{id}", sprintHandler.UpdateSprint).Methods("PUT")
+	router.HandleFunc("/sprints/{id}/start", sprintHandler.StartSprint).Methods("POST")
+	router.HandleFunc("/sprints/{id}/close", sprintHandler.CloseSprint).Methods("POST")
+	router.HandleFunc("/sprints/{id}/burndown", sprintHandler.GetBurndown).Methods("GET")
+}
@@ -10,12 +10,17 @@ func RegisterTaskRoutes(router *mux.Router, taskHandler *handlers.TaskHandler) {
 
 	router.HandleFunc("/tasks", taskHandler.CreateTask).Methods("POST")
 	router.HandleFunc("/tasks", taskHandler.GetAllTasks).Methods("GET")
+	router.HandleFunc("/tasks/bulk", taskHandler.BulkTasks).Methods("POST")
 
 	router.HandleFunc("/tasks/{id}", taskHandler.GetTaskByID).Methods("GET")
 	router.HandleFunc("/tasks/{id}", taskHandler.UpdateTask).Methods("PUT")
 	router.HandleFunc("/tasks/{id}", taskHandler.DeleteTask).Methods("DELETE")
 
+	router.HandleFunc("/tasks/{id}/move", taskHandler.MoveTask).Methods("POST")
+
 	router.HandleFunc("/projects/{projectId}/tasks", taskHandler.GetTasksByProject).Methods("GET")
+	router.HandleFunc("/projects/{id}/board", taskHandler.GetBoard).Methods("GET")
+	router.HandleFunc("/projects/{id}/import", taskHandler.ImportTasks).Methods("POST")
 	router.HandleFunc("/users/{userId}/tasks", taskHandler.GetTasksByAssignedUser).Methods("GET")
 	router.HandleFunc("/tasks/status/{status}", taskHandler.GetTasksByStatus).Methods("GET")
 }
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"Concurrent_Task_Management_System/internal/handlers"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterTeamRoutes(router *mux.Router, teamHandler *handlers.TeamHandler) {
+
+	router.HandleFunc("/teams", teamHandler.CreateTeam).Methods("POST")
+	router.HandleFunc("/teams", teamHandler.GetTeams).Methods("GET")
+
+	router.HandleFunc("/teams/{id}", teamHandler.GetTeam).Methods("GET")
+	router.HandleFunc("/teams/{id}", teamHandler.UpdateTeam).Methods("PUT")
+	router.HandleFunc("/teams/{id}", teamHandler.DeleteTeam).Methods("DELETE")
+	router.HandleFunc("/teams/{id}/tasks", teamHandler.GetTeamQueue).Methods("GET")
+
+	router.HandleFunc("/teams/{id}/members", teamHandler.AddMember).Methods("POST")
+	router.HandleFunc("/teams/{id}/members/{userId}", teamHandler.RemoveMember).Methods("DELETE")
+}
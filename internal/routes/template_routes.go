@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"Concurrent_Task_Management_System/internal/handlers"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterTemplateRoutes(router *mux.Router, templateHandler *handlers.TemplateHandler) {
+
+	router.HandleFunc("/templates", templateHandler.GetTemplates).Methods("GET")
+	router.HandleFunc("/templates/{id}", templateHandler.GetTemplate).Methods("GET")
+	router.HandleFunc("/templates/{id}", templateHandler.DeleteTemplate).Methods("DELETE")
+	router.HandleFunc("/templates/{id}/instantiate", templateHandler.InstantiateTemplate).Methods("POST")
+
+	router.HandleFunc("/projects/{id}/template", templateHandler.SaveTemplate).Methods("POST")
+	router.HandleFunc("/projects/{id}/clone", templateHandler.CloneProject).Methods("POST")
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"Concurrent_Task_Management_System/internal/handlers"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterTrashRoutes(router *mux.Router, trashHandler *handlers.TrashHandler) {
+
+	router.HandleFunc("/trash", trashHandler.GetTrash).Methods("GET")
+
+	router.HandleFunc("/users/{id}/restore", trashHandler.RestoreUser).Methods("POST")
+	router.HandleFunc("/projects/{id}/restore", trashHandler.RestoreProject).Methods("POST")
+	router.HandleFunc("/tasks/{id}/restore", trashHandler.RestoreTask).Methods("POST")
+}
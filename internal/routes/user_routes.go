@@ -14,4 +14,11 @@ func RegisterUserRoutes(router *mux.Router, userHandler *handlers.UserHandler) {
 	router.HandleFunc("/users/{id}", userHandler.GetUserByID).Methods("GET")
 	router.HandleFunc("/users/{id}", userHandler.UpdateUser).Methods("PUT")
 	router.HandleFunc("/users/{id}", userHandler.DeleteUser).Methods("DELETE")
+	router.HandleFunc("/users/{id}/role", userHandler.ChangeRole).Methods("PUT")
+	router.HandleFunc("/users/{id}/password", userHandler.SetPassword).Methods("PUT")
+
+	router.HandleFunc("/users/{id}/suspend", userHandler.SuspendUser).Methods("POST")
+	router.HandleFunc("/users/{id}/reactivate", userHandler.ReactivateUser).Methods("POST")
+	router.HandleFunc("/users/{id}/deactivation-plan", userHandler.GetDeactivationPlan).Methods("GET")
+	router.HandleFunc("/users/{id}/deactivate", userHandler.DeactivateUser).Methods("POST")
 }
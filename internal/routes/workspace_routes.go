@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"Concurrent_Task_Management_System/internal/handlers"
+
+	"github.com/gorilla/mux"
+)
+
+func RegisterWorkspaceRoutes(router *mux.Router, workspaceHandler *handlers.WorkspaceHandler) {
+
+	router.HandleFunc("/workspaces", workspaceHandler.CreateWorkspace).Methods("POST")
+	router.HandleFunc("/workspace", workspaceHandler.GetCurrentWorkspace).Methods("GET")
+}
@@ -0,0 +1,456 @@
+// Package seed fills a workspace with generated admins, employees, projects
+// and tasks for load and dashboard testing. The same seed and reference time
+// always produce the same documents, ObjectIDs included.
+package seed
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/repositories"
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const taskBatchSize = 1000
+
+// Config sizes the generated data. Counts are totals except MembersPerProject
+// and TasksPerProject, which are averages; the actual numbers vary per project.
+type Config struct {
+	Seed              int64
+	Now               time.Time
+	Admins            int
+	Employees         int
+	Projects          int
+	MembersPerProject int
+	TasksPerProject   int
+
+	// PasswordHash is given to every generated user. Hashing once keeps
+	// large runs fast.
+	PasswordHash string
+}
+
+// Repositories are the stores the generator writes through. The context
+// passed to Generate must be scoped to the target workspace.
+type Repositories struct {
+	Users    repositories.UserRepository
+	Projects repositories.ProjectRepository
+	Tasks    repositories.TaskRepository
+}
+
+// Result counts what was written.
+type Result struct {
+	Admins           int            `json:"admins"`
+	Employees        int            `json:"employees"`
+	Projects         int            `json:"projects"`
+	ArchivedProjects int            `json:"archivedProjects"`
+	Memberships      int            `json:"memberships"`
+	Tasks            int            `json:"tasks"`
+	TasksByStatus    map[string]int `json:"tasksByStatus"`
+	OverdueTasks     int            `json:"overdueTasks"`
+}
+
+func (c Config) validate() error {
+	switch {
+	case c.Admins < 1:
+		return fmt.Errorf("at least one admin is needed to own projects")
+	case c.Employees < 0 || c.Projects < 0 || c.MembersPerProject < 0 || c.TasksPerProject < 0:
+		return fmt.Errorf("counts cannot be negative")
+	case c.Now.IsZero():
+		return fmt.Errorf("reference time is required")
+	}
+	return nil
+}
+
+// Generate writes the data and reports what it wrote. Users come first,
+// then each project followed by its tasks, so an interrupted run leaves
+// consistent, if incomplete, data behind.
+func Generate(
+	ctx context.Context,
+	repos Repositories,
+	cfg Config,
+	logf func(format string, args ...interface{}),
+) (*Result, error) {
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		rng: rand.New(rand.NewSource(cfg.Seed)),
+		cfg: cfg,
+		now: cfg.Now.UTC().Truncate(time.Second),
+	}
+	result := &Result{TasksByStatus: make(map[string]int)}
+
+	admins, err := g.users(ctx, repos.Users, models.RoleAdmin, cfg.Admins)
+	if err != nil {
+		return nil, err
+	}
+	employees, err := g.users(ctx, repos.Users, models.RoleEmployee, cfg.Employees)
+	if err != nil {
+		return nil, err
+	}
+	result.Admins, result.Employees = len(admins), len(employees)
+	logf("created %d admins and %d employees", len(admins), len(employees))
+
+	for i := 0; i < cfg.Projects; i++ {
+		project := g.project(i, admins, employees)
+		if err := repos.Projects.Create(ctx, project); err != nil {
+			return result, fmt.Errorf("project %d: %w", i+1, err)
+		}
+
+		result.Projects++
+		result.Memberships += len(project.Members)
+		if project.ArchivedAt != nil {
+			result.ArchivedProjects++
+		}
+
+		tasks := g.tasks(project)
+		for start := 0; start < len(tasks); start += taskBatchSize {
+			end := start + taskBatchSize
+			if end > len(tasks) {
+				end = len(tasks)
+			}
+			if err := repos.Tasks.CreateMany(ctx, tasks[start:end]); err != nil {
+				return result, fmt.Errorf("tasks of project %d: %w", i+1, err)
+			}
+		}
+
+		for _, t := range tasks {
+			result.Tasks++
+			result.TasksByStatus[t.Status]++
+			if t.Status != models.TaskStatusDone && !t.DueDate.IsZero() && t.DueDate.Before(g.now) {
+				result.OverdueTasks++
+			}
+		}
+
+		if (i+1)%50 == 0 || i+1 == cfg.Projects {
+			logf("created %d/%d projects, %d tasks", i+1, cfg.Projects, result.Tasks)
+		}
+	}
+
+	return result, nil
+}
+
+// generator draws everything from one random source, in a fixed order, so
+// the output depends on the seed alone.
+type generator struct {
+	rng *rand.Rand
+	cfg Config
+	now time.Time
+}
+
+// =====================
+// USERS
+// =====================
+
+func (g *generator) users(
+	ctx context.Context,
+	repo repositories.UserRepository,
+	role string,
+	n int,
+) ([]models.User, error) {
+
+	users := make([]models.User, 0, n)
+
+	for i := 0; i < n; i++ {
+		first := firstNames[g.rng.Intn(len(firstNames))]
+		last := lastNames[g.rng.Intn(len(lastNames))]
+		createdAt := g.before(g.now, 2*365*24*time.Hour)
+
+		// The suffix keeps user_id and email unique however the names fall.
+		userID := fmt.Sprintf("%s.%s.%s%d", strings.ToLower(first), strings.ToLower(last), role[:1], i+1)
+
+		user := models.User{
+			ID:           g.id(createdAt),
+			UserID:       userID,
+			Name:         first + " " + last,
+			Email:        userID + "@example.test",
+			Role:         role,
+			PasswordHash: g.cfg.PasswordHash,
+			Status:       models.UserStatusActive,
+			CreatedAt:    createdAt,
+		}
+
+		// A few employees are suspended, which the dashboards must cope with.
+		if role == models.RoleEmployee && g.rng.Float64() < 0.04 {
+			user.Status = models.UserStatusSuspended
+			changed := g.between(createdAt, g.now)
+			user.StatusChangedAt = &changed
+		}
+
+		if err := repo.Create(ctx, &user); err != nil {
+			return nil, fmt.Errorf("%s %s: %w", role, userID, err)
+		}
+		users = append(users, user)
+	}
+
+	return users, nil
+}
+
+// =====================
+// PROJECTS
+// =====================
+
+func (g *generator) project(i int, admins, employees []models.User) *models.Project {
+	// Some admins own many projects and some few.
+	owner := admins[g.skewed(len(admins))]
+	createdAt := g.before(g.now, 365*24*time.Hour)
+
+	project := &models.Project{
+		ID:          g.id(createdAt),
+		Name:        fmt.Sprintf("%s %s", projectAdjectives[g.rng.Intn(len(projectAdjectives))], projectNouns[g.rng.Intn(len(projectNouns))]),
+		Description: fmt.Sprintf("Generated project %d.", i+1),
+		OwnerID:     owner.ID,
+		MemberIDs:   []primitive.ObjectID{},
+		Members:     []models.ProjectMember{},
+		CreatedAt:   createdAt,
+	}
+
+	if g.rng.Float64() < 0.15 {
+		archivedAt := g.between(createdAt, g.now)
+		project.ArchivedAt = &archivedAt
+		project.ArchivedBy = &owner.ID
+	}
+
+	size := g.vary(g.cfg.MembersPerProject)
+	if size > len(employees) {
+		size = len(employees)
+	}
+
+	for _, idx := range g.rng.Perm(len(employees))[:size] {
+		role := models.ProjectRoleContributor
+		switch r := g.rng.Float64(); {
+		case r < 0.10:
+			role = models.ProjectRoleMaintainer
+		case r < 0.25:
+			role = models.ProjectRoleViewer
+		}
+
+		project.Members = append(project.Members, models.ProjectMember{
+			UserID:  employees[idx].ID,
+			Role:    role,
+			AddedAt: g.between(createdAt, createdAt.Add(30*24*time.Hour)),
+		})
+		project.MemberIDs = append(project.MemberIDs, employees[idx].ID)
+	}
+
+	return project
+}
+
+// =====================
+// TASKS
+// =====================
+
+func (g *generator) tasks(project *models.Project) []models.Task {
+	var assignees []primitive.ObjectID
+	for _, m := range project.Members {
+		if m.Role != models.ProjectRoleViewer {
+			assignees = append(assignees, m.UserID)
+		}
+	}
+
+	end := g.now
+	if project.ArchivedAt != nil {
+		end = *project.ArchivedAt
+	}
+
+	n := g.vary(g.cfg.TasksPerProject)
+	tasks := make([]models.Task, 0, n)
+
+	for i := 0; i < n; i++ {
+		createdAt := g.between(project.CreatedAt, end)
+		status := g.status(createdAt, end)
+
+		task := models.Task{
+			Title:     g.title(),
+			Status:    status,
+			Priority:  g.pick(priorities),
+			ProjectID: project.ID,
+			CreatedAt: createdAt,
+			UpdatedAt: g.between(createdAt, end),
+		}
+		task.ID = g.id(createdAt)
+
+		if g.rng.Float64() < 0.4 {
+			task.Description = descriptions[g.rng.Intn(len(descriptions))]
+		}
+
+		for _, idx := range g.rng.Perm(len(labels))[:g.rng.Intn(3)] {
+			task.Labels = append(task.Labels, labels[idx])
+		}
+
+		// Most tasks are assigned, unevenly, so some people are overloaded.
+		if len(assignees) > 0 && g.rng.Float64() < 0.85 {
+			task.AssignedTo = assignees[g.skewed(len(assignees))]
+		}
+
+		// Due dates cluster a few weeks after creation, so open tasks that
+		// have been around for a while are overdue.
+		if g.rng.Float64() < 0.8 {
+			days := 3 + int(g.rng.ExpFloat64()*14)
+			task.DueDate = createdAt.Add(time.Duration(days) * 24 * time.Hour).Truncate(24 * time.Hour)
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	// Board order within each column follows creation order.
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+	})
+	for _, status := range models.TaskStatuses {
+		var column []int
+		for i := range tasks {
+			if tasks[i].Status == status {
+				column = append(column, i)
+			}
+		}
+		for k, rank := range utils.EvenRanks(len(column)) {
+			tasks[column[k]].Rank = rank
+		}
+	}
+
+	return tasks
+}
+
+// status makes older tasks more likely to be done.
+func (g *generator) status(createdAt, end time.Time) string {
+	age := end.Sub(createdAt).Hours() / 24
+	done := math.Min(0.85, 0.15+age/120)
+
+	switch r := g.rng.Float64(); {
+	case r < done:
+		return models.TaskStatusDone
+	case r < done+(1-done)*0.35:
+		return models.TaskStatusInProgress
+	default:
+		return models.TaskStatusTodo
+	}
+}
+
+func (g *generator) title() string {
+	return verbs[g.rng.Intn(len(verbs))] + " " + objects[g.rng.Intn(len(objects))]
+}
+
+// =====================
+// RANDOMNESS
+// =====================
+
+// id builds an ObjectID whose timestamp matches t and whose remaining bytes
+// come from the seeded source.
+func (g *generator) id(t time.Time) primitive.ObjectID {
+	var id primitive.ObjectID
+	binary.BigEndian.PutUint32(id[0:4], uint32(t.Unix()))
+	binary.BigEndian.PutUint64(id[4:12], g.rng.Uint64())
+	return id
+}
+
+func (g *generator) before(t time.Time, max time.Duration) time.Time {
+	return t.Add(-time.Duration(g.rng.Int63n(int64(max)))).Truncate(time.Second)
+}
+
+func (g *generator) between(from, to time.Time) time.Time {
+	if !to.After(from) {
+		return from
+	}
+	return from.Add(time.Duration(g.rng.Int63n(int64(to.Sub(from))))).Truncate(time.Second)
+}
+
+// vary returns a count around avg, between half and one and a half times it.
+func (g *generator) vary(avg int) int {
+	if avg == 0 {
+		return 0
+	}
+	return avg/2 + g.rng.Intn(avg+1)
+}
+
+// skewed picks an index in [0, n) with lower indexes much more likely.
+func (g *generator) skewed(n int) int {
+	i := int(g.rng.ExpFloat64() * float64(n) / 3)
+	if i >= n {
+		i = g.rng.Intn(n)
+	}
+	return i
+}
+
+type weighted struct {
+	value  string
+	weight float64
+}
+
+func (g *generator) pick(choices []weighted) string {
+	r := g.rng.Float64()
+	for _, c := range choices {
+		if r < c.weight {
+			return c.value
+		}
+		r -= c.weight
+	}
+	return choices[len(choices)-1].value
+}
+
+// =====================
+// VOCABULARY
+// =====================
+
+var priorities = []weighted{
+	{"Low", 0.30},
+	{"Medium", 0.50},
+	{"High", 0.20},
+}
+
+var firstNames = []string{
+	"Ada", "Ben", "Chloe", "Dev", "Elena", "Farid", "Grace", "Hugo", "Ines", "Jonas",
+	"Kavya", "Liam", "Mara", "Noah", "Olga", "Priya", "Quentin", "Rosa", "Sami", "Tara",
+	"Umar", "Vera", "Wei", "Ximena", "Yusuf", "Zoe",
+}
+
+var lastNames = []string{
+	"Adams", "Bauer", "Costa", "Dubois", "Eriksen", "Fischer", "Garcia", "Haddad", "Ito", "Jensen",
+	"Kowalski", "Lopez", "Moreau", "Nakamura", "Okafor", "Patel", "Quinn", "Rossi", "Silva", "Tanaka",
+	"Usman", "Varga", "Weber", "Xu", "Yilmaz", "Zhang",
+}
+
+var projectAdjectives = []string{
+	"Apollo", "Atlas", "Beacon", "Cobalt", "Delta", "Ember", "Falcon", "Granite", "Harbor", "Juniper",
+	"Lumen", "Meridian", "Nimbus", "Orion", "Summit", "Vertex",
+}
+
+var projectNouns = []string{
+	"Redesign", "Migration", "Launch", "Onboarding", "Platform", "Mobile App", "Billing", "Analytics",
+	"Integration", "Rollout", "Audit", "Portal",
+}
+
+var verbs = []string{
+	"Implement", "Fix", "Review", "Document", "Refactor", "Test", "Design", "Deploy", "Investigate",
+	"Update", "Migrate", "Optimize",
+}
+
+var objects = []string{
+	"login flow", "invoice export", "search indexing", "dashboard widgets", "email templates",
+	"payment webhook", "user settings page", "API rate limiting", "audit log", "file uploads",
+	"notification service", "onboarding checklist", "report scheduler", "access control rules",
+	"mobile navigation", "error tracking", "cache invalidation", "CSV import",
+}
+
+var descriptions = []string{
+	"Follow the acceptance criteria in the spec and add tests.",
+	"Reported by several customers; reproduce first.",
+	"Coordinate with the design team before starting.",
+	"Blocked until the upstream API change is released.",
+	"Keep the change behind a feature flag until QA signs off.",
+}
+
+var labels = []string{
+	"bug", "feature", "frontend", "backend", "urgent", "tech-debt", "ux", "security", "docs",
+}
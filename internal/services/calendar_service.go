@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var ErrFeedTokenInvalid = errors.New("calendar feed token is invalid or revoked")
+
+// CalendarService serves iCalendar feeds of task due dates. Calendar
+// clients cannot send a bearer token, so feeds are authorized by a secret
+// token in the URL. A user has at most one; it reads with that user's
+// permissions and stops working when replaced, revoked or when the user is
+// no longer active.
+type CalendarService struct {
+	userRepo       repositories.UserRepository
+	taskRepo       repositories.TaskRepository
+	projectRepo    repositories.ProjectRepository
+	projectService *ProjectService
+	baseURL        string
+}
+
+func NewCalendarService(
+	userRepo repositories.UserRepository,
+	taskRepo repositories.TaskRepository,
+	projectRepo repositories.ProjectRepository,
+	projectService *ProjectService,
+	baseURL string,
+) *CalendarService {
+	return &CalendarService{
+		userRepo:       userRepo,
+		taskRepo:       taskRepo,
+		projectRepo:    projectRepo,
+		projectService: projectService,
+		baseURL:        strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// =====================
+// TOKENS
+// =====================
+
+// IssueFeedToken gives the user a new feed token, revoking the previous
+// one. Users can only issue tokens for themselves.
+func (s *CalendarService) IssueFeedToken(
+	ctx context.Context,
+	currentUser *models.User,
+	userID string,
+) (*dto.CalendarToken, error) {
+
+	if userID != currentUser.ID.Hex() {
+		return nil, ErrForbidden
+	}
+
+	token, err := newWorkspaceToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userRepo.SetCalendarTokenHash(ctx, currentUser.ID, hashToken(token)); err != nil {
+		return nil, err
+	}
+
+	return &dto.CalendarToken{
+		Token:          token,
+		UserFeedURL:    s.baseURL + "/calendar/tasks.ics?token=" + token,
+		ProjectFeedURL: s.baseURL + "/calendar/projects/{projectId}.ics?token=" + token,
+	}, nil
+}
+
+// RevokeFeedToken turns the user's feeds off. Admins can revoke anyone's,
+// for instance after a URL leaked.
+func (s *CalendarService) RevokeFeedToken(
+	ctx context.Context,
+	currentUser *models.User,
+	userID string,
+) error {
+
+	objID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.New("invalid user id")
+	}
+
+	if objID != currentUser.ID && !isAdminCapable(currentUser) {
+		return ErrForbidden
+	}
+
+	return s.userRepo.SetCalendarTokenHash(ctx, objID, "")
+}
+
+// =====================
+// FEEDS
+// =====================
+
+// UserFeed lists the tasks assigned to the token's owner that have a due
+// date, in projects they can still see.
+func (s *CalendarService) UserFeed(ctx context.Context, token string) (*dto.CalendarFeed, error) {
+	ctx, user, err := s.feedUser(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.taskRepo.FindByAssignedUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &dto.CalendarFeed{
+		Name:     "Tasks: " + user.Name,
+		Projects: make(map[primitive.ObjectID]string),
+	}
+	hidden := make(map[primitive.ObjectID]bool)
+
+	for _, t := range tasks {
+		if t.DueDate.IsZero() || hidden[t.ProjectID] {
+			continue
+		}
+
+		if _, seen := feed.Projects[t.ProjectID]; !seen {
+			project, err := s.projectRepo.FindByID(ctx, t.ProjectID)
+			if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+				return nil, err
+			}
+			if err != nil || !canOnProject(user, project, actionViewProject) {
+				hidden[t.ProjectID] = true
+				continue
+			}
+			feed.Projects[t.ProjectID] = project.Name
+		}
+
+		feed.Tasks = append(feed.Tasks, t)
+	}
+
+	return feed, nil
+}
+
+// ProjectFeed lists every task of the project that has a due date, if the
+// token's owner can view the project.
+func (s *CalendarService) ProjectFeed(
+	ctx context.Context,
+	token string,
+	projectID string,
+) (*dto.CalendarFeed, error) {
+
+	ctx, user, err := s.feedUser(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.projectService.loadProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canOnProject(user, project, actionViewProject) {
+		return nil, ErrForbidden
+	}
+
+	tasks, err := s.taskRepo.FindByProjectID(ctx, project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &dto.CalendarFeed{
+		Name:     project.Name,
+		Projects: map[primitive.ObjectID]string{project.ID: project.Name},
+	}
+	for _, t := range tasks {
+		if !t.DueDate.IsZero() {
+			feed.Tasks = append(feed.Tasks, t)
+		}
+	}
+
+	return feed, nil
+}
+
+// feedUser scopes ctx to the token's workspace and returns its owner.
+func (s *CalendarService) feedUser(
+	ctx context.Context,
+	token string,
+) (context.Context, *models.User, error) {
+
+	workspaceID, ok := parseWorkspaceToken(token)
+	if !ok {
+		return nil, nil, ErrFeedTokenInvalid
+	}
+	ctx = WithWorkspace(ctx, workspaceID)
+
+	user, err := s.userRepo.FindByCalendarTokenHash(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil, ErrFeedTokenInvalid
+		}
+		return nil, nil, err
+	}
+
+	if !user.IsActive() {
+		return nil, nil, ErrFeedTokenInvalid
+	}
+
+	return ctx, user, nil
+}
@@ -42,6 +42,7 @@ func (s *DashboardService) GetUserFromJWT(
 func (s *DashboardService) GetDashboard(
 	ctx context.Context,
 	currentUser *models.User,
+	includeArchived bool,
 ) (*dto.DashboardResponse, error) {
 
 	response := &dto.DashboardResponse{
@@ -52,7 +53,7 @@ func (s *DashboardService) GetDashboard(
 		return response, nil
 	}
 
-	rawUsers, err := s.dashboardRepo.GetAdminDashboard(ctx, currentUser.ID.Hex())
+	rawUsers, err := s.dashboardRepo.GetAdminDashboard(ctx, currentUser.ID.Hex(), includeArchived)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,380 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// The fakes below keep just enough state for the tests of this package.
+// Each embeds its repository interface, so a method a test does not expect
+// to be called panics on the nil embedded value.
+
+type fakeUnitOfWork struct{}
+
+func (fakeUnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (fakeUnitOfWork) Snapshot(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeTaskRepository struct {
+	repositories.TaskRepository
+
+	stream *fakeTaskStream
+	counts []repositories.ColumnCount
+	tasks  []*models.Task
+	writes []repositories.TaskWrite
+
+	reassignedIn []primitive.ObjectID
+}
+
+func (r *fakeTaskRepository) FindOpenByAssignee(ctx context.Context, userID primitive.ObjectID) ([]models.Task, error) {
+	tasks := []models.Task{}
+	for _, t := range r.tasks {
+		if t.DeletedAt == nil && t.AssignedTo == userID && t.Status != models.TaskStatusDone {
+			tasks = append(tasks, *t)
+		}
+	}
+	return tasks, nil
+}
+
+func (r *fakeTaskRepository) ReassignOpen(ctx context.Context, fromID, toID primitive.ObjectID, projectIDs []primitive.ObjectID) (int64, error) {
+	r.reassignedIn = append(r.reassignedIn, projectIDs...)
+	return 0, nil
+}
+
+func (r *fakeTaskRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Task, error) {
+	for _, t := range r.tasks {
+		if t.ID == id && t.DeletedAt == nil {
+			found := *t
+			return &found, nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func (r *fakeTaskRepository) CountInColumn(ctx context.Context, projectID primitive.ObjectID, status string) (int64, error) {
+	var count int64
+	for _, t := range r.tasks {
+		if t.DeletedAt == nil && t.ProjectID == projectID && t.Status == status {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeTaskRepository) CountInColumnForAssignee(
+	ctx context.Context,
+	projectID primitive.ObjectID,
+	status string,
+	assignee primitive.ObjectID,
+) (int64, error) {
+	var count int64
+	for _, t := range r.tasks {
+		if t.DeletedAt == nil && t.ProjectID == projectID && t.Status == status && t.AssignedTo == assignee {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// BulkWrite matches like the real one, by id, updatedAt and not being in
+// the trash, and records the writes without applying them.
+func (r *fakeTaskRepository) BulkWrite(ctx context.Context, writes []repositories.TaskWrite) (int64, error) {
+	r.writes = append(r.writes, writes...)
+
+	var matched int64
+	for _, w := range writes {
+		for _, t := range r.tasks {
+			if t.ID == w.ID && t.DeletedAt == nil && t.UpdatedAt.Equal(w.UpdatedAt) {
+				matched++
+			}
+		}
+	}
+	return matched, nil
+}
+
+func (r *fakeTaskRepository) CountByStatusAndAssignee(ctx context.Context, projectID primitive.ObjectID) ([]repositories.ColumnCount, error) {
+	return r.counts, nil
+}
+
+func (r *fakeTaskRepository) WatchAll(ctx context.Context) (repositories.TaskChangeStream, error) {
+	return r.stream, nil
+}
+
+// fakeTaskStream delivers what is sent on changes and blocks once it is
+// drained, like an idle change stream.
+type fakeTaskStream struct {
+	changes chan *repositories.TaskChange
+}
+
+func newFakeTaskStream() *fakeTaskStream {
+	return &fakeTaskStream{changes: make(chan *repositories.TaskChange, 16)}
+}
+
+func (s *fakeTaskStream) Next(ctx context.Context) (*repositories.TaskChange, error) {
+	select {
+	case change := <-s.changes:
+		return change, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (s *fakeTaskStream) TryNext(ctx context.Context) (*repositories.TaskChange, error) {
+	select {
+	case change := <-s.changes:
+		return change, nil
+	default:
+		return nil, nil
+	}
+}
+
+func (s *fakeTaskStream) Close(ctx context.Context) error {
+	return nil
+}
+
+type fakeUserRepository struct {
+	repositories.UserRepository
+
+	users   map[primitive.ObjectID]*models.User
+	updates map[primitive.ObjectID]bson.M
+}
+
+func (r *fakeUserRepository) UpdateByID(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	if r.updates == nil {
+		r.updates = make(map[primitive.ObjectID]bson.M)
+	}
+	r.updates[id] = update
+	return nil
+}
+
+func newFakeUserRepository(users ...*models.User) *fakeUserRepository {
+	r := &fakeUserRepository{users: make(map[primitive.ObjectID]*models.User)}
+	for _, u := range users {
+		r.users[u.ID] = u
+	}
+	return r
+}
+
+func (r *fakeUserRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, mongo.ErrNoDocuments
+	}
+	found := *user
+	return &found, nil
+}
+
+type fakeProjectRepository struct {
+	repositories.ProjectRepository
+
+	projects []*models.Project
+	owners   map[primitive.ObjectID]primitive.ObjectID
+	joined   map[primitive.ObjectID][]primitive.ObjectID
+}
+
+func (r *fakeProjectRepository) ChangeOwner(ctx context.Context, projectID, fromID, toID primitive.ObjectID) (bool, error) {
+	if r.owners == nil {
+		r.owners = make(map[primitive.ObjectID]primitive.ObjectID)
+	}
+	r.owners[projectID] = toID
+	return true, nil
+}
+
+func (r *fakeProjectRepository) AddMember(ctx context.Context, projectID primitive.ObjectID, member models.ProjectMember) (bool, error) {
+	if r.joined == nil {
+		r.joined = make(map[primitive.ObjectID][]primitive.ObjectID)
+	}
+	r.joined[projectID] = append(r.joined[projectID], member.UserID)
+	return true, nil
+}
+
+func (r *fakeProjectRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Project, error) {
+	for _, p := range r.projects {
+		if p.ID == id {
+			found := *p
+			return &found, nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func (r *fakeProjectRepository) Lock(ctx context.Context, projectID primitive.ObjectID) error {
+	return nil
+}
+
+func (r *fakeProjectRepository) find(includeArchived bool, match func(*models.Project) bool) []models.Project {
+	var projects []models.Project
+	for _, p := range r.projects {
+		if (includeArchived || p.ArchivedAt == nil) && match(p) {
+			projects = append(projects, *p)
+		}
+	}
+	return projects
+}
+
+func (r *fakeProjectRepository) FindByOwnerID(ctx context.Context, ownerID primitive.ObjectID, includeArchived bool) ([]models.Project, error) {
+	return r.find(includeArchived, func(p *models.Project) bool { return p.OwnerID == ownerID }), nil
+}
+
+func (r *fakeProjectRepository) FindByMemberID(ctx context.Context, userID primitive.ObjectID, includeArchived bool) ([]models.Project, error) {
+	return r.find(includeArchived, func(p *models.Project) bool {
+		return p.OwnerID != userID && directRoleOf(p, userID) != ""
+	}), nil
+}
+
+func (r *fakeTaskRepository) FindBySprintID(ctx context.Context, sprintID primitive.ObjectID) ([]models.Task, error) {
+	tasks := []models.Task{}
+	for _, t := range r.tasks {
+		if t.DeletedAt == nil && t.SprintID == sprintID {
+			tasks = append(tasks, *t)
+		}
+	}
+	return tasks, nil
+}
+
+type fakeSprintRepository struct {
+	repositories.SprintRepository
+
+	sprints []*models.Sprint
+}
+
+func (r *fakeSprintRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Sprint, error) {
+	for _, s := range r.sprints {
+		if s.ID == id {
+			found := *s
+			return &found, nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+// fakeTaskEventRepository expects events in time order.
+type fakeTaskEventRepository struct {
+	repositories.TaskEventRepository
+
+	events []models.TaskEvent
+}
+
+func (r *fakeTaskEventRepository) FindTaskIDsBySprint(ctx context.Context, sprintID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	seen := make(map[primitive.ObjectID]bool)
+	ids := []primitive.ObjectID{}
+	for _, e := range r.events {
+		if e.SprintID == sprintID && !seen[e.TaskID] {
+			seen[e.TaskID] = true
+			ids = append(ids, e.TaskID)
+		}
+	}
+	return ids, nil
+}
+
+func (r *fakeTaskEventRepository) FindByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID, until time.Time) ([]models.TaskEvent, error) {
+	events := []models.TaskEvent{}
+	for _, e := range r.events {
+		if slices.Contains(taskIDs, e.TaskID) && e.At.Before(until) {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (r *fakeUserRepository) FindByUserID(ctx context.Context, userID string) (*models.User, error) {
+	for _, u := range r.users {
+		if u.UserID == userID {
+			found := *u
+			return &found, nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func (r *fakeUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			found := *u
+			return &found, nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func (r *fakeUserRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]models.User, error) {
+	users := []models.User{}
+	for _, id := range ids {
+		if u, ok := r.users[id]; ok {
+			users = append(users, *u)
+		}
+	}
+	return users, nil
+}
+
+func (r *fakeProjectRepository) Create(ctx context.Context, project *models.Project) error {
+	project.ID = primitive.NewObjectID()
+	created := *project
+	r.projects = append(r.projects, &created)
+	return nil
+}
+
+func (r *fakeTaskRepository) CreateMany(ctx context.Context, tasks []models.Task) error {
+	for i := range tasks {
+		tasks[i].ID = primitive.NewObjectID()
+		created := tasks[i]
+		r.tasks = append(r.tasks, &created)
+	}
+	return nil
+}
+
+// fakePresenceRepository keeps entries in the order they were first
+// upserted, which the tests treat as oldest first.
+type fakePresenceRepository struct {
+	repositories.PresenceRepository
+
+	entries []*models.Presence
+}
+
+func (r *fakePresenceRepository) Upsert(ctx context.Context, presence *models.Presence) error {
+	for _, e := range r.entries {
+		if e.ID == presence.ID {
+			e.Mode, e.ExpiresAt = presence.Mode, presence.ExpiresAt
+			return nil
+		}
+	}
+	stored := *presence
+	r.entries = append(r.entries, &stored)
+	return nil
+}
+
+func (r *fakePresenceRepository) Delete(ctx context.Context, connectionID string, resource string) error {
+	r.entries = slices.DeleteFunc(r.entries, func(e *models.Presence) bool {
+		return e.ConnectionID == connectionID && e.Resource == resource
+	})
+	return nil
+}
+
+func (r *fakePresenceRepository) DeleteConnection(ctx context.Context, connectionID string) error {
+	r.entries = slices.DeleteFunc(r.entries, func(e *models.Presence) bool {
+		return e.ConnectionID == connectionID
+	})
+	return nil
+}
+
+func (r *fakePresenceRepository) FindActive(ctx context.Context, resources []string, now time.Time) ([]models.Presence, error) {
+	entries := []models.Presence{}
+	for _, e := range r.entries {
+		if slices.Contains(resources, e.Resource) && e.ExpiresAt.After(now) {
+			entries = append(entries, *e)
+		}
+	}
+	return entries, nil
+}
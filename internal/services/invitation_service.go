@@ -0,0 +1,344 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/mailer"
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvitationInvalid = errors.New("invitation is invalid, expired or already used")
+	ErrAlreadyUser       = errors.New("a user with this email already exists")
+	ErrUserIDTaken       = errors.New("user_id is already taken")
+)
+
+const minPasswordLength = 8
+
+type InvitationService struct {
+	repo        repositories.InvitationRepository
+	userRepo    repositories.UserRepository
+	projectRepo repositories.ProjectRepository
+	userService *UserService
+	uow         repositories.UnitOfWork
+	mailer      mailer.Mailer
+	ttl         time.Duration
+	baseURL     string
+}
+
+func NewInvitationService(
+	repo repositories.InvitationRepository,
+	userRepo repositories.UserRepository,
+	projectRepo repositories.ProjectRepository,
+	userService *UserService,
+	uow repositories.UnitOfWork,
+	m mailer.Mailer,
+	ttl time.Duration,
+	baseURL string,
+) *InvitationService {
+	return &InvitationService{
+		repo:        repo,
+		userRepo:    userRepo,
+		projectRepo: projectRepo,
+		userService: userService,
+		uow:         uow,
+		mailer:      m,
+		ttl:         ttl,
+		baseURL:     strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// =====================
+// INVITE
+// =====================
+// Invite records an invitation and emails its token. The token names its
+// workspace, so accepting it needs no login.
+func (s *InvitationService) Invite(
+	ctx context.Context,
+	currentUser *models.User,
+	invitation *models.Invitation,
+) (*models.Invitation, error) {
+
+	if !isAdminCapable(currentUser) {
+		return nil, ErrForbidden
+	}
+
+	invitation.Email = strings.ToLower(strings.TrimSpace(invitation.Email))
+	if invitation.Role == "" {
+		invitation.Role = models.RoleEmployee
+	}
+
+	errs := &ValidationError{}
+
+	if addr, err := mail.ParseAddress(invitation.Email); err != nil || addr.Address != invitation.Email {
+		errs.add("email", "must be a valid email address")
+	}
+
+	switch invitation.Role {
+	case models.RoleEmployee, models.RoleAdmin:
+	case models.RoleSuperAdmin:
+		if currentUser.Role != models.RoleSuperAdmin {
+			errs.add("role", "only a super_admin can invite a super_admin")
+		}
+	default:
+		errs.add("role", "must be one of employee, admin, super_admin")
+	}
+
+	for i := range invitation.Projects {
+		field := fmt.Sprintf("projects[%d]", i)
+		invited := &invitation.Projects[i]
+
+		if invited.Role == "" {
+			invited.Role = models.ProjectRoleContributor
+		}
+		if !isValidMemberRole(invited.Role) {
+			errs.add(field+".role", "must be one of viewer, contributor, maintainer")
+		}
+
+		project, err := s.projectRepo.FindByID(ctx, invited.ProjectID)
+		if err != nil {
+			if !errors.Is(err, mongo.ErrNoDocuments) {
+				return nil, err
+			}
+			errs.add(field+".projectId", "project not found")
+			continue
+		}
+		if !canOnProject(currentUser, project, actionManageMembers) {
+			errs.add(field+".projectId", "you cannot manage members of this project")
+		}
+		if project.ArchivedAt != nil {
+			errs.add(field+".projectId", "project is archived")
+		}
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userRepo.FindByEmail(ctx, invitation.Email); err == nil {
+		return nil, ErrAlreadyUser
+	} else if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+
+	token, err := newWorkspaceToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation.ID = primitive.NilObjectID
+	invitation.TokenHash = hashToken(token)
+	invitation.InvitedBy = currentUser.ID
+	invitation.CreatedAt = now
+	invitation.ExpiresAt = now.Add(s.ttl)
+	invitation.AcceptedAt = nil
+	invitation.AcceptedBy = nil
+	invitation.RevokedAt = nil
+
+	// A new invitation replaces any earlier one for the same address.
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.repo.RevokePendingForEmail(ctx, invitation.Email, now); err != nil {
+			return err
+		}
+		return s.repo.Create(ctx, invitation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mailer.Send(ctx, s.invitationMail(currentUser, invitation, token)); err != nil {
+		// A token nobody received must not stay usable.
+		if _, revokeErr := s.repo.Revoke(ctx, invitation.ID, time.Now()); revokeErr != nil {
+			return nil, revokeErr
+		}
+		return nil, fmt.Errorf("sending invitation: %w", err)
+	}
+
+	return invitation, nil
+}
+
+func (s *InvitationService) invitationMail(
+	inviter *models.User,
+	invitation *models.Invitation,
+	token string,
+) mailer.Message {
+
+	link := s.baseURL + "/invitations/accept?token=" + url.QueryEscape(token)
+
+	return mailer.Message{
+		To:      invitation.Email,
+		Subject: "You have been invited to join the team",
+		Body: fmt.Sprintf(
+			"%s has invited you to join as %s.\n\n"+
+				"Accept the invitation here:\n%s\n\n"+
+				"The link can be used once and expires on %s.\n",
+			inviter.Name,
+			invitation.Role,
+			link,
+			invitation.ExpiresAt.UTC().Format(time.RFC1123),
+		),
+	}
+}
+
+// =====================
+// LIST / REVOKE
+// =====================
+func (s *InvitationService) GetPendingInvitations(
+	ctx context.Context,
+	currentUser *models.User,
+) ([]models.Invitation, error) {
+
+	if !isAdminCapable(currentUser) {
+		return nil, ErrForbidden
+	}
+	return s.repo.FindPending(ctx, time.Now())
+}
+
+func (s *InvitationService) RevokeInvitation(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+) error {
+
+	if !isAdminCapable(currentUser) {
+		return ErrForbidden
+	}
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid invitation id")
+	}
+
+	if _, err := s.repo.FindByID(ctx, objID); err != nil {
+		return err
+	}
+
+	revoked, err := s.repo.Revoke(ctx, objID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrInvitationInvalid
+	}
+	return nil
+}
+
+// =====================
+// ACCEPT
+// =====================
+// AcceptInvitation redeems a token: in one transaction it marks the
+// invitation used, creates the user with the invited email and role, and
+// adds them to the invited projects. Projects deleted or archived in the
+// meantime are skipped.
+func (s *InvitationService) AcceptInvitation(
+	ctx context.Context,
+	token string,
+	user *models.User,
+	password string,
+) (*models.User, error) {
+
+	workspaceID, ok := parseWorkspaceToken(token)
+	if !ok {
+		return nil, ErrInvitationInvalid
+	}
+	ctx = WithWorkspace(ctx, workspaceID)
+
+	errs := &ValidationError{}
+
+	user.UserID = strings.TrimSpace(user.UserID)
+	user.Name = strings.TrimSpace(user.Name)
+
+	if user.UserID == "" {
+		errs.add("user_id", "is required")
+	}
+	if user.Name == "" {
+		errs.add("name", "is required")
+	}
+	if len(password) < minPasswordLength {
+		errs.add("password", fmt.Sprintf("must be at least %d characters", minPasswordLength))
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	invitation, err := s.repo.FindByTokenHash(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrInvitationInvalid
+		}
+		return nil, err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user.ID = primitive.NilObjectID
+	user.Email = invitation.Email
+	user.Role = invitation.Role
+	user.PasswordHash = string(hash)
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.userService.createUser(ctx, user); err != nil {
+			switch repositories.DuplicateKeyField(err) {
+			case "email":
+				return ErrAlreadyUser
+			case "user_id":
+				return ErrUserIDTaken
+			}
+			return err
+		}
+
+		now := time.Now()
+
+		accepted, err := s.repo.Accept(ctx, invitation.ID, user.ID, now)
+		if err != nil {
+			return err
+		}
+		if !accepted {
+			return ErrInvitationInvalid
+		}
+
+		for _, invited := range invitation.Projects {
+			project, err := s.projectRepo.FindByID(ctx, invited.ProjectID)
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if project.ArchivedAt != nil {
+				continue
+			}
+
+			_, err = s.projectRepo.AddMember(ctx, invited.ProjectID, models.ProjectMember{
+				UserID:  user.ID,
+				Role:    invited.Role,
+				AddedAt: now,
+			})
+			if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,521 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	TaskEventCreated = "task.created"
+	TaskEventUpdated = "task.updated"
+	TaskEventMoved   = "task.moved"
+	TaskEventDeleted = "task.deleted"
+)
+
+var (
+	ErrInvalidEventID         = errors.New("Last-Event-ID is not a valid event id")
+	ErrLiveUpdatesUnavailable = errors.New("live updates are unavailable: the database does not support change streams")
+	ErrStreamClosed           = errors.New("server is shutting down")
+	ErrStreamLagging          = errors.New("client fell behind the live updates, reconnect to resume")
+)
+
+// projectAccessTTL is how long a user stream trusts a project visibility
+// check before repeating it.
+const projectAccessTTL = 30 * time.Second
+
+// liveBuffer is how many changes may wait for a slow subscriber before it
+// is dropped and has to reconnect and resume.
+const liveBuffer = 256
+
+// LiveService pushes task changes to connected clients. Each server instance
+// reads a single MongoDB change stream of all task writes and fans it out
+// to its subscribers, so a client sees writes made through any instance
+// without holding a database connection of its own. Event ids are change
+// stream resume tokens, so a client can reconnect to another instance and
+// pick up where it left off.
+type LiveService struct {
+	taskRepo       repositories.TaskRepository
+	userRepo       repositories.UserRepository
+	projectService *ProjectService
+
+	mu        sync.Mutex
+	running   bool
+	subs      map[*TaskSubscription]struct{}
+	stopHub   context.CancelFunc
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+func NewLiveService(
+	taskRepo repositories.TaskRepository,
+	userRepo repositories.UserRepository,
+	projectService *ProjectService,
+) *LiveService {
+	return &LiveService{
+		taskRepo:       taskRepo,
+		userRepo:       userRepo,
+		projectService: projectService,
+		subs:           make(map[*TaskSubscription]struct{}),
+		closing:        make(chan struct{}),
+	}
+}
+
+// Shutdown ends every open subscription, so that long-lived streams do not
+// hold up a graceful server shutdown.
+func (s *LiveService) Shutdown() {
+	s.closeOnce.Do(func() {
+		close(s.closing)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.stopHub != nil {
+			s.stopHub()
+		}
+	})
+}
+
+// TaskSubscription is an open stream of task events. Events is closed when
+// the stream ends; Err then tells why. Reset is set when the stream could
+// not resume from the requested event and started from now instead, so the
+// client must reload what it shows.
+type TaskSubscription struct {
+	Events <-chan dto.TaskStreamEvent
+	Reset  bool
+
+	service     *LiveService
+	workspaceID primitive.ObjectID
+	projectID   primitive.ObjectID
+	cancel      context.CancelFunc
+
+	// changes is fed by the shared stream and closed when it drops the
+	// subscription.
+	changes chan *repositories.TaskChange
+
+	mu      sync.Mutex
+	err     error
+	user    *models.User
+	visible map[primitive.ObjectID]projectAccess
+}
+
+type projectAccess struct {
+	allowed   bool
+	checkedAt time.Time
+}
+
+// SubscribeProject streams the changes to one project's tasks.
+func (s *LiveService) SubscribeProject(
+	ctx context.Context,
+	currentUser *models.User,
+	projectID string,
+	lastEventID string,
+) (*TaskSubscription, error) {
+
+	project, err := s.projectService.loadProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeProject(currentUser, project, actionViewProject); err != nil {
+		return nil, err
+	}
+
+	return s.subscribe(ctx, currentUser, project.ID, lastEventID)
+}
+
+// SubscribeUser streams the changes to tasks in every project the user can
+// view. Users can only subscribe to their own stream.
+func (s *LiveService) SubscribeUser(
+	ctx context.Context,
+	currentUser *models.User,
+	userID string,
+	lastEventID string,
+) (*TaskSubscription, error) {
+
+	if userID != currentUser.ID.Hex() {
+		return nil, ErrForbidden
+	}
+
+	return s.subscribe(ctx, currentUser, primitive.NilObjectID, lastEventID)
+}
+
+// Check repeats the authorization of the subscription, for streams that
+// stay open for hours. It fails once the user is no longer active or, for
+// a project stream, can no longer view the project.
+func (sub *TaskSubscription) Check(ctx context.Context) error {
+	user, err := sub.service.userRepo.FindByID(ctx, sub.currentUser().ID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrForbidden
+		}
+		return err
+	}
+	if err := CheckUserActive(user); err != nil {
+		return err
+	}
+
+	sub.mu.Lock()
+	sub.user = user
+	sub.visible = make(map[primitive.ObjectID]projectAccess)
+	sub.mu.Unlock()
+
+	if sub.projectID.IsZero() {
+		return nil
+	}
+
+	project, err := sub.service.projectService.findProject(ctx, sub.projectID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrForbidden
+		}
+		return err
+	}
+	return authorizeProject(user, project, actionViewProject)
+}
+
+// Err reports why Events was closed. It is nil when the client went away.
+// Only call it after Events is closed.
+func (sub *TaskSubscription) Err() error {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	return sub.err
+}
+
+func (sub *TaskSubscription) setErr(err error) {
+	sub.mu.Lock()
+	sub.err = err
+	sub.mu.Unlock()
+}
+
+// Close ends the subscription.
+func (sub *TaskSubscription) Close() {
+	sub.cancel()
+}
+
+func (s *LiveService) subscribe(
+	ctx context.Context,
+	currentUser *models.User,
+	projectID primitive.ObjectID,
+	lastEventID string,
+) (*TaskSubscription, error) {
+
+	workspaceID, ok := repositories.TenantFrom(ctx)
+	if !ok {
+		return nil, repositories.ErrNoTenant
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	events := make(chan dto.TaskStreamEvent)
+	sub := &TaskSubscription{
+		Events:      events,
+		service:     s,
+		workspaceID: workspaceID,
+		projectID:   projectID,
+		cancel:      cancel,
+		changes:     make(chan *repositories.TaskChange, liveBuffer),
+		user:        currentUser,
+		visible:     make(map[primitive.ObjectID]projectAccess),
+	}
+
+	// Join the shared stream before catching up, so that nothing written
+	// in between is missed; changes seen twice are skipped by token.
+	if err := s.join(sub); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	var catchUp repositories.TaskChangeStream
+	if lastEventID != "" {
+		stream, reset, err := s.resume(ctx, projectID, lastEventID)
+		if err != nil {
+			s.leave(sub)
+			cancel()
+			return nil, err
+		}
+		catchUp = stream
+		sub.Reset = reset
+	}
+
+	go func() {
+		defer close(events)
+		defer s.leave(sub)
+
+		send := func(change *repositories.TaskChange) bool {
+			for _, event := range sub.eventsFor(ctx, change) {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		}
+
+		// Replay what the client missed from a stream of its own, then
+		// continue with the shared one past the last replayed change.
+		var replayed string
+		if catchUp != nil {
+			for {
+				change, err := catchUp.TryNext(ctx)
+				if err != nil || change == nil {
+					catchUp.Close(context.WithoutCancel(ctx))
+					if err != nil && ctx.Err() == nil {
+						sub.setErr(liveError(err))
+						return
+					}
+					break
+				}
+				replayed = change.Token
+				if !send(change) {
+					catchUp.Close(context.WithoutCancel(ctx))
+					return
+				}
+			}
+		}
+
+		for {
+			select {
+			case change, ok := <-sub.changes:
+				if !ok {
+					return
+				}
+				if change.Token <= replayed {
+					continue
+				}
+				if !send(change) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return sub, nil
+}
+
+// resume opens a stream of the subscriber's workspace right after
+// lastEventID, falling back to no replay when it is too old to resume from.
+func (s *LiveService) resume(
+	ctx context.Context,
+	projectID primitive.ObjectID,
+	lastEventID string,
+) (repositories.TaskChangeStream, bool, error) {
+
+	stream, err := s.taskRepo.Watch(ctx, projectID, lastEventID)
+	if errors.Is(err, repositories.ErrResumeTokenExpired) {
+		return nil, true, nil
+	}
+	if err != nil {
+		return nil, false, liveError(err)
+	}
+	return stream, false, nil
+}
+
+// join adds a subscriber to the shared stream, opening the stream for the
+// first one.
+func (s *LiveService) join(sub *TaskSubscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.closing:
+		return ErrStreamClosed
+	default:
+	}
+
+	if !s.running {
+		ctx, cancel := context.WithCancel(context.Background())
+		stream, err := s.taskRepo.WatchAll(ctx)
+		if err != nil {
+			cancel()
+			return liveError(err)
+		}
+		s.running = true
+		s.stopHub = cancel
+		go s.fanOut(ctx, stream)
+	}
+
+	s.subs[sub] = struct{}{}
+	return nil
+}
+
+// leave removes a subscriber that went away by itself.
+func (s *LiveService) leave(sub *TaskSubscription) {
+	s.mu.Lock()
+	delete(s.subs, sub)
+	s.mu.Unlock()
+}
+
+// fanOut reads the shared stream and hands every change to the subscribers
+// of its workspace. A subscriber that falls liveBuffer changes behind is
+// dropped rather than holding up the others; it can reconnect and resume.
+// When the stream fails every subscriber is dropped, and the next one to
+// arrive opens a new stream.
+func (s *LiveService) fanOut(ctx context.Context, stream repositories.TaskChangeStream) {
+	defer stream.Close(context.Background())
+
+	for {
+		change, err := stream.Next(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				err = ErrStreamClosed
+			} else {
+				log.Println("Live updates stream error:", err)
+				err = liveError(err)
+			}
+
+			s.mu.Lock()
+			for sub := range s.subs {
+				s.drop(sub, err)
+			}
+			s.running = false
+			s.stopHub()
+			s.stopHub = nil
+			s.mu.Unlock()
+			return
+		}
+
+		s.mu.Lock()
+		for sub := range s.subs {
+			if sub.workspaceID != change.Task.WorkspaceID {
+				continue
+			}
+			select {
+			case sub.changes <- change:
+			default:
+				s.drop(sub, ErrStreamLagging)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// drop ends a subscription from the shared stream's side. Callers hold s.mu.
+func (s *LiveService) drop(sub *TaskSubscription, err error) {
+	sub.setErr(err)
+	delete(s.subs, sub)
+	close(sub.changes)
+}
+
+// eventsFor turns a change into the events this subscriber should see. A
+// task moved to another project is reported as deleted from the project it
+// left, without its contents, and as created in the one it entered.
+func (sub *TaskSubscription) eventsFor(ctx context.Context, change *repositories.TaskChange) []dto.TaskStreamEvent {
+	eventType := taskEventType(change)
+	if eventType == "" {
+		return nil
+	}
+
+	task := change.Task
+	var events []dto.TaskStreamEvent
+
+	movedFrom := primitive.NilObjectID
+	if slices.Contains(change.UpdatedFields, "projectId") && task.PreviousProjectID != task.ProjectID {
+		movedFrom = task.PreviousProjectID
+		eventType = TaskEventCreated
+	}
+
+	if !movedFrom.IsZero() && sub.canView(ctx, movedFrom) {
+		events = append(events, dto.TaskStreamEvent{
+			ID:        change.Token,
+			Type:      TaskEventDeleted,
+			ProjectID: movedFrom.Hex(),
+			TaskID:    task.ID.Hex(),
+			At:        change.At,
+		})
+	}
+
+	if sub.canView(ctx, task.ProjectID) {
+		event := dto.TaskStreamEvent{
+			ID:        change.Token,
+			Type:      eventType,
+			ProjectID: task.ProjectID.Hex(),
+			TaskID:    task.ID.Hex(),
+			Task:      &task,
+			At:        change.At,
+		}
+		if eventType == TaskEventUpdated || eventType == TaskEventMoved {
+			// change is shared by every subscriber, so build a new slice
+			// rather than appending to one of its own.
+			event.ChangedFields = slices.Concat(change.UpdatedFields, change.RemovedFields)
+		}
+		events = append(events, event)
+	}
+
+	return events
+}
+
+func (sub *TaskSubscription) currentUser() *models.User {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	return sub.user
+}
+
+// canView tells whether the subscriber sees events of a project. A project
+// stream was authorized when opened and is re-checked by Check; a user
+// stream checks each project, caching the answer for projectAccessTTL.
+func (sub *TaskSubscription) canView(ctx context.Context, projectID primitive.ObjectID) bool {
+	if !sub.projectID.IsZero() {
+		return projectID == sub.projectID
+	}
+
+	sub.mu.Lock()
+	access, ok := sub.visible[projectID]
+	user := sub.user
+	sub.mu.Unlock()
+
+	if ok && time.Since(access.checkedAt) < projectAccessTTL {
+		return access.allowed
+	}
+
+	project, err := sub.service.projectService.findProject(ctx, projectID)
+	allowed := err == nil && canOnProject(user, project, actionViewProject)
+
+	sub.mu.Lock()
+	sub.visible[projectID] = projectAccess{allowed: allowed, checkedAt: time.Now()}
+	sub.mu.Unlock()
+
+	return allowed
+}
+
+// taskEventType classifies a change. Trashing a task is an update of
+// deletedAt and restoring it brings the task back, so it is reported as
+// created. Changes to tasks in the trash are not reported.
+func taskEventType(change *repositories.TaskChange) string {
+	switch {
+	case change.Operation == "insert":
+		return TaskEventCreated
+	case change.Task.DeletedAt != nil:
+		if slices.Contains(change.UpdatedFields, "deletedAt") {
+			return TaskEventDeleted
+		}
+		return ""
+	case slices.Contains(change.RemovedFields, "deletedAt"):
+		return TaskEventCreated
+	case slices.Contains(change.UpdatedFields, "status"), slices.Contains(change.UpdatedFields, "rank"):
+		return TaskEventMoved
+	}
+	return TaskEventUpdated
+}
+
+func liveError(err error) error {
+	switch {
+	case errors.Is(err, repositories.ErrInvalidResumeToken):
+		return ErrInvalidEventID
+	case errors.Is(err, repositories.ErrChangeStreamsUnsupported):
+		return ErrLiveUpdatesUnavailable
+	}
+	return err
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func nextEvent(t *testing.T, sub *TaskSubscription) dto.TaskStreamEvent {
+	t.Helper()
+
+	select {
+	case event, ok := <-sub.Events:
+		if !ok {
+			t.Fatalf("stream closed: %v", sub.Err())
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event within 5s")
+	}
+	return dto.TaskStreamEvent{}
+}
+
+// Every subscriber of a workspace gets the same *TaskChange. Run with -race:
+// building ChangedFields must not write into the change's slices, which
+// have spare capacity when paths such as "labels.0" and "labels.1" collapse.
+func TestLiveSubscribersShareChanges(t *testing.T) {
+	workspace, project := primitive.NewObjectID(), primitive.NewObjectID()
+	stream := newFakeTaskStream()
+	live := NewLiveService(&fakeTaskRepository{stream: stream}, nil, nil)
+	defer live.Shutdown()
+
+	ctx := WithWorkspace(context.Background(), workspace)
+	user := &models.User{ID: primitive.NewObjectID(), Role: models.RoleEmployee}
+
+	var subs []*TaskSubscription
+	for i := 0; i < 2; i++ {
+		sub, err := live.subscribe(ctx, user, project, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer sub.Close()
+		subs = append(subs, sub)
+	}
+
+	updated := make([]string, 0, 3)
+	updated = append(updated, "title", "labels")
+	change := &repositories.TaskChange{
+		Token:         "0001",
+		Operation:     "update",
+		Task:          models.Task{ID: primitive.NewObjectID(), WorkspaceID: workspace, ProjectID: project},
+		UpdatedFields: updated,
+		RemovedFields: []string{"description"},
+	}
+	stream.changes <- change
+
+	want := []string{"title", "labels", "description"}
+	for _, sub := range subs {
+		event := nextEvent(t, sub)
+		if event.Type != TaskEventUpdated {
+			t.Errorf("type = %q, want %q", event.Type, TaskEventUpdated)
+		}
+		if !reflect.DeepEqual(event.ChangedFields, want) {
+			t.Errorf("changedFields = %v, want %v", event.ChangedFields, want)
+		}
+		if &event.ChangedFields[0] == &change.UpdatedFields[0] {
+			t.Error("changedFields shares its array with the change")
+		}
+	}
+}
+
+func TestTaskEventType(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		change repositories.TaskChange
+		want   string
+	}{
+		{"insert", repositories.TaskChange{Operation: "insert"}, TaskEventCreated},
+		{"edit", repositories.TaskChange{Operation: "update", UpdatedFields: []string{"title"}}, TaskEventUpdated},
+		{"status change", repositories.TaskChange{Operation: "update", UpdatedFields: []string{"status", "updatedAt"}}, TaskEventMoved},
+		{"rerank", repositories.TaskChange{Operation: "update", UpdatedFields: []string{"rank"}}, TaskEventMoved},
+		{"trashed", repositories.TaskChange{
+			Operation:     "update",
+			Task:          models.Task{DeletedAt: &now},
+			UpdatedFields: []string{"deletedAt", "deletedBy"},
+		}, TaskEventDeleted},
+		{"edited in the trash", repositories.TaskChange{
+			Operation:     "update",
+			Task:          models.Task{DeletedAt: &now},
+			UpdatedFields: []string{"title"},
+		}, ""},
+		{"restored", repositories.TaskChange{
+			Operation:     "update",
+			UpdatedFields: []string{"status"},
+			RemovedFields: []string{"deletedAt", "deletedBy"},
+		}, TaskEventCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := taskEventType(&tt.change); got != tt.want {
+				t.Errorf("taskEventType = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestEventsForMovedTask checks that a task moving between projects is
+// reported as deleted from the one it left and created in the one it
+// entered, each only to subscribers who can see that project.
+func TestEventsForMovedTask(t *testing.T) {
+	user := &models.User{ID: primitive.NewObjectID(), Role: models.RoleEmployee}
+	member := func() *models.Project {
+		return &models.Project{ID: primitive.NewObjectID(), MemberIDs: []primitive.ObjectID{user.ID}}
+	}
+	from, to := member(), member()
+	hidden := &models.Project{ID: primitive.NewObjectID()}
+
+	projects := &fakeProjectRepository{projects: []*models.Project{from, to, hidden}}
+	live := NewLiveService(nil, nil, NewProjectService(projects, nil, nil, nil, fakeUnitOfWork{}))
+
+	type event struct {
+		eventType string
+		projectID primitive.ObjectID
+		withTask  bool
+	}
+
+	tests := []struct {
+		name     string
+		scope    primitive.ObjectID
+		from, to primitive.ObjectID
+		want     []event
+	}{
+		{
+			name: "between visible projects",
+			from: from.ID, to: to.ID,
+			want: []event{{TaskEventDeleted, from.ID, false}, {TaskEventCreated, to.ID, true}},
+		},
+		{
+			name: "into a hidden project",
+			from: from.ID, to: hidden.ID,
+			want: []event{{TaskEventDeleted, from.ID, false}},
+		},
+		{
+			name: "out of a hidden project",
+			from: hidden.ID, to: to.ID,
+			want: []event{{TaskEventCreated, to.ID, true}},
+		},
+		{
+			name:  "watching the project it left",
+			scope: from.ID,
+			from:  from.ID, to: to.ID,
+			want: []event{{TaskEventDeleted, from.ID, false}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub := &TaskSubscription{
+				service:   live,
+				projectID: tt.scope,
+				user:      user,
+				visible:   make(map[primitive.ObjectID]projectAccess),
+			}
+			change := &repositories.TaskChange{
+				Operation:     "update",
+				Task:          models.Task{ID: primitive.NewObjectID(), ProjectID: tt.to, PreviousProjectID: tt.from},
+				UpdatedFields: []string{"projectId", "previousProjectId"},
+			}
+
+			var got []event
+			for _, e := range sub.eventsFor(context.Background(), change) {
+				projectID, _ := primitive.ObjectIDFromHex(e.ProjectID)
+				got = append(got, event{e.Type, projectID, e.Task != nil})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
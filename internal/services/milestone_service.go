@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MilestoneService struct {
+	repo          repositories.MilestoneRepository
+	projectRepo   repositories.ProjectRepository
+	taskRepo      repositories.TaskRepository
+	uow           repositories.UnitOfWork
+	atRiskWindow  time.Duration
+	atRiskPercent int
+}
+
+func NewMilestoneService(
+	repo repositories.MilestoneRepository,
+	projectRepo repositories.ProjectRepository,
+	taskRepo repositories.TaskRepository,
+	uow repositories.UnitOfWork,
+	atRiskWindow time.Duration,
+	atRiskPercent int,
+) *MilestoneService {
+	return &MilestoneService{
+		repo:          repo,
+		projectRepo:   projectRepo,
+		taskRepo:      taskRepo,
+		uow:           uow,
+		atRiskWindow:  atRiskWindow,
+		atRiskPercent: atRiskPercent,
+	}
+}
+
+// =====================
+// CREATE
+// =====================
+func (s *MilestoneService) CreateMilestone(
+	ctx context.Context,
+	currentUser *models.User,
+	projectID string,
+	milestone *models.Milestone,
+) (*dto.MilestoneResponse, error) {
+
+	project, err := s.loadProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeProject(currentUser, project, actionManagePlanning); err != nil {
+		return nil, err
+	}
+
+	errs := &ValidationError{}
+
+	if milestone.Kind == "" {
+		milestone.Kind = models.MilestoneKindMilestone
+	}
+	if milestone.Kind != models.MilestoneKindMilestone && milestone.Kind != models.MilestoneKindEpic {
+		errs.add("kind", "must be one of milestone, epic")
+	}
+	if milestone.Name == "" {
+		errs.add("name", "is required")
+	}
+	if milestone.TargetDate.IsZero() {
+		errs.add("targetDate", "is required")
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	milestone.ID = primitive.NilObjectID
+	milestone.ProjectID = project.ID
+	milestone.CreatedAt = now
+	milestone.UpdatedAt = now
+
+	if err := s.repo.Create(ctx, milestone); err != nil {
+		return nil, err
+	}
+
+	return s.withProgress(milestone, 0, 0, now), nil
+}
+
+// =====================
+// READ
+// =====================
+
+// GetMilestones lists the project's milestones and epics; kind narrows the
+// list to one of them.
+func (s *MilestoneService) GetMilestones(
+	ctx context.Context,
+	currentUser *models.User,
+	projectID string,
+	kind string,
+) ([]dto.MilestoneResponse, error) {
+
+	project, err := s.loadProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeProject(currentUser, project, actionViewProject); err != nil {
+		return nil, err
+	}
+
+	if kind != "" && kind != models.MilestoneKindMilestone && kind != models.MilestoneKindEpic {
+		errs := &ValidationError{}
+		errs.add("kind", "must be one of milestone, epic")
+		return nil, errs
+	}
+
+	milestones, err := s.repo.FindByProjectID(ctx, project.ID, kind)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := make(map[primitive.ObjectID]repositories.GroupProgress)
+	for _, k := range []string{models.MilestoneKindMilestone, models.MilestoneKindEpic} {
+		if kind != "" && kind != k {
+			continue
+		}
+		counts, err := s.taskRepo.CountProgress(ctx, project.ID, models.MilestoneTaskField(k))
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range counts {
+			progress[c.ID] = c
+		}
+	}
+
+	now := time.Now()
+	result := make([]dto.MilestoneResponse, len(milestones))
+	for i := range milestones {
+		p := progress[milestones[i].ID]
+		result[i] = *s.withProgress(&milestones[i], p.Total, p.Done, now)
+	}
+
+	return result, nil
+}
+
+func (s *MilestoneService) GetMilestone(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+) (*dto.MilestoneDetail, error) {
+
+	milestone, err := s.authorizeMilestone(ctx, currentUser, id, actionViewProject)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.taskRepo.FindByMilestone(ctx, models.MilestoneTaskField(milestone.Kind), milestone.ID)
+	if err != nil {
+		return nil, err
+	}
+	if tasks == nil {
+		tasks = []models.Task{}
+	}
+
+	done := 0
+	for _, t := range tasks {
+		if t.Status == models.TaskStatusDone {
+			done++
+		}
+	}
+
+	return &dto.MilestoneDetail{
+		MilestoneResponse: *s.withProgress(milestone, len(tasks), done, time.Now()),
+		Tasks:             tasks,
+	}, nil
+}
+
+// =====================
+// UPDATE
+// =====================
+func (s *MilestoneService) UpdateMilestone(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+	update bson.M,
+) error {
+
+	milestone, err := s.authorizeMilestone(ctx, currentUser, id, actionManagePlanning)
+	if err != nil {
+		return err
+	}
+
+	delete(update, "_id")
+	delete(update, "projectId")
+	delete(update, "createdAt")
+
+	errs := &ValidationError{}
+
+	if name, ok := update["name"]; ok && name == "" {
+		errs.add("name", "cannot be empty")
+	}
+
+	// Tasks link to milestones and epics through different fields.
+	if kind, ok := update["kind"]; ok && kind != milestone.Kind {
+		errs.add("kind", "cannot be changed")
+	}
+
+	timeField(update, "targetDate", errs)
+
+	if err := errs.errOrNil(); err != nil {
+		return err
+	}
+
+	update["updatedAt"] = time.Now()
+	return s.repo.UpdateByID(ctx, milestone.ID, update)
+}
+
+// =====================
+// DELETE
+// =====================
+
+// DeleteMilestone removes the milestone and unlinks its tasks; the tasks
+// themselves are kept.
+func (s *MilestoneService) DeleteMilestone(ctx context.Context, currentUser *models.User, id string) error {
+	milestone, err := s.authorizeMilestone(ctx, currentUser, id, actionManagePlanning)
+	if err != nil {
+		return err
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteByID(ctx, milestone.ID); err != nil {
+			return err
+		}
+
+		_, err := s.taskRepo.UnlinkMilestone(ctx, models.MilestoneTaskField(milestone.Kind), milestone.ID)
+		return err
+	})
+}
+
+// =====================
+// HELPERS
+// =====================
+
+// withProgress attaches progress and the at-risk flag. A milestone is at
+// risk when it is not complete and either overdue or due within the
+// configured window with less than the configured share of tasks done.
+func (s *MilestoneService) withProgress(
+	milestone *models.Milestone,
+	total int,
+	done int,
+	now time.Time,
+) *dto.MilestoneResponse {
+
+	progress := 0
+	if total > 0 {
+		progress = done * 100 / total
+	}
+
+	complete := total > 0 && done == total
+	overdue := now.After(milestone.TargetDate)
+	dueSoon := now.Add(s.atRiskWindow).After(milestone.TargetDate)
+
+	return &dto.MilestoneResponse{
+		Milestone:  milestone,
+		TotalTasks: total,
+		DoneTasks:  done,
+		Progress:   progress,
+		AtRisk:     !complete && (overdue || (dueSoon && progress < s.atRiskPercent)),
+	}
+}
+
+func (s *MilestoneService) loadProject(ctx context.Context, id string) (*models.Project, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("invalid project id")
+	}
+
+	return s.projectRepo.FindByID(ctx, objID)
+}
+
+// authorizeMilestone loads the milestone and checks the caller's role in its project.
+func (s *MilestoneService) authorizeMilestone(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+	action projectAction,
+) (*models.Milestone, error) {
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("invalid milestone id")
+	}
+
+	milestone, err := s.repo.FindByID(ctx, objID)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.projectRepo.FindByID(ctx, milestone.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeProject(currentUser, project, action); err != nil {
+		return nil, err
+	}
+
+	return milestone, nil
+}
@@ -0,0 +1,500 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	PresenceJoin      = "join"
+	PresenceLeave     = "leave"
+	PresenceHeartbeat = "heartbeat"
+)
+
+var (
+	ErrPresenceTarget = errors.New("give either a project or a task")
+	ErrPresenceMode   = errors.New("mode must be viewing or editing")
+)
+
+// presencePollInterval is how often each server instance reads the presence
+// on the resources its clients have joined. Changes made through the same
+// instance are sent right away.
+const presencePollInterval = 2 * time.Second
+
+// PresenceService tracks who has which board or task open. Presence is
+// stored in MongoDB rather than in memory, so clients connected to
+// different server instances see each other: every instance polls the
+// resources its own clients have joined and sends them the lists that
+// changed.
+type PresenceService struct {
+	repo           repositories.PresenceRepository
+	taskRepo       repositories.TaskRepository
+	userRepo       repositories.UserRepository
+	projectService *ProjectService
+	ttl            time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*PresenceSession
+	closing  bool
+	wake     chan struct{}
+}
+
+func NewPresenceService(
+	repo repositories.PresenceRepository,
+	taskRepo repositories.TaskRepository,
+	userRepo repositories.UserRepository,
+	projectService *ProjectService,
+	ttl time.Duration,
+) *PresenceService {
+	return &PresenceService{
+		repo:           repo,
+		taskRepo:       taskRepo,
+		userRepo:       userRepo,
+		projectService: projectService,
+		ttl:            ttl,
+		sessions:       make(map[string]*PresenceSession),
+		wake:           make(chan struct{}, 1),
+	}
+}
+
+// TTL is how long presence lasts without a heartbeat.
+func (s *PresenceService) TTL() time.Duration {
+	return s.ttl
+}
+
+// HeartbeatInterval is how often clients should send a heartbeat, leaving
+// room for two to go missing before their presence expires.
+func (s *PresenceService) HeartbeatInterval() time.Duration {
+	return s.ttl / 3
+}
+
+// PresenceSession is one client connection. Updates receives the people on
+// each joined resource whenever that list changes, and is closed when the
+// server shuts down. Join, Leave and Heartbeat must be called from one
+// goroutine.
+type PresenceSession struct {
+	Updates <-chan dto.PresenceUpdate
+
+	service     *PresenceService
+	id          string
+	workspaceID primitive.ObjectID
+	updates     chan dto.PresenceUpdate
+
+	user      *models.User
+	checkedAt time.Time
+
+	// Guarded by service.mu. sent holds the last list delivered per
+	// resource, so unchanged lists are not sent again.
+	joined map[string]presenceTarget
+	sent   map[string]string
+}
+
+// presenceTarget is a resolved and authorized join.
+type presenceTarget struct {
+	resource  string
+	projectID primitive.ObjectID
+	taskID    primitive.ObjectID
+	mode      string
+}
+
+// Connect opens a session for the user in the workspace of ctx.
+func (s *PresenceService) Connect(ctx context.Context, currentUser *models.User) (*PresenceSession, error) {
+	workspaceID, ok := repositories.TenantFrom(ctx)
+	if !ok {
+		return nil, repositories.ErrNoTenant
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+
+	updates := make(chan dto.PresenceUpdate, 16)
+	session := &PresenceSession{
+		Updates:     updates,
+		service:     s,
+		id:          hex.EncodeToString(buf),
+		workspaceID: workspaceID,
+		updates:     updates,
+		user:        currentUser,
+		checkedAt:   time.Now(),
+		joined:      make(map[string]presenceTarget),
+		sent:        make(map[string]string),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closing {
+		return nil, ErrStreamClosed
+	}
+	s.sessions[session.id] = session
+
+	return session, nil
+}
+
+// Shutdown closes the Updates of every session, telling their connections
+// to close. Presence left behind expires with its TTL.
+func (s *PresenceService) Shutdown() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closing = true
+	for id, session := range s.sessions {
+		close(session.updates)
+		delete(s.sessions, id)
+	}
+}
+
+// Join announces that the user is viewing or editing a project board or a
+// task. Editing needs the right to update tasks in the project. Joining a
+// resource again changes the mode.
+func (c *PresenceSession) Join(ctx context.Context, msg dto.PresenceMessage) error {
+	target, err := c.service.resolve(ctx, c.user, msg)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = c.service.repo.Upsert(ctx, &models.Presence{
+		ID:           c.id + "/" + target.resource,
+		ConnectionID: c.id,
+		Resource:     target.resource,
+		ProjectID:    target.projectID,
+		TaskID:       target.taskID,
+		UserID:       c.user.ID,
+		Name:         c.user.Name,
+		Mode:         target.mode,
+		Since:        now,
+		ExpiresAt:    now.Add(c.service.ttl),
+	})
+	if err != nil {
+		return err
+	}
+
+	c.service.mu.Lock()
+	c.joined[target.resource] = target
+	delete(c.sent, target.resource)
+	c.service.mu.Unlock()
+
+	c.service.poke()
+	return nil
+}
+
+// Leave withdraws the user from a resource.
+func (c *PresenceSession) Leave(ctx context.Context, msg dto.PresenceMessage) error {
+	resource, err := presenceResource(msg)
+	if err != nil {
+		return err
+	}
+
+	if err := c.service.repo.Delete(ctx, c.id, resource); err != nil {
+		return err
+	}
+
+	c.service.mu.Lock()
+	delete(c.joined, resource)
+	delete(c.sent, resource)
+	c.service.mu.Unlock()
+
+	c.service.poke()
+	return nil
+}
+
+// Heartbeat keeps every joined resource alive for another TTL. At most
+// every projectAccessTTL it also repeats the authorization: it fails once
+// the user is no longer active, and leaves the resources the user lost
+// access to, returning them.
+func (c *PresenceSession) Heartbeat(ctx context.Context) ([]string, error) {
+	var revoked []string
+	if time.Since(c.checkedAt) >= projectAccessTTL {
+		var err error
+		if revoked, err = c.recheck(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.service.repo.Refresh(ctx, c.id, time.Now().Add(c.service.ttl)); err != nil {
+		return nil, err
+	}
+	return revoked, nil
+}
+
+// Close ends the session and removes its presence.
+func (c *PresenceSession) Close() {
+	c.service.mu.Lock()
+	if _, ok := c.service.sessions[c.id]; ok {
+		delete(c.service.sessions, c.id)
+		close(c.updates)
+	}
+	c.service.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(WithWorkspace(context.Background(), c.workspaceID), 5*time.Second)
+	defer cancel()
+
+	if err := c.service.repo.DeleteConnection(ctx, c.id); err != nil {
+		log.Println("Presence cleanup error:", err)
+	}
+	c.service.poke()
+}
+
+func (c *PresenceSession) recheck(ctx context.Context) ([]string, error) {
+	user, err := c.service.userRepo.FindByID(ctx, c.user.ID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrForbidden
+		}
+		return nil, err
+	}
+	if err := CheckUserActive(user); err != nil {
+		return nil, err
+	}
+	c.user = user
+
+	c.service.mu.Lock()
+	targets := make([]presenceTarget, 0, len(c.joined))
+	for _, target := range c.joined {
+		targets = append(targets, target)
+	}
+	c.service.mu.Unlock()
+
+	var revoked []string
+	for _, target := range targets {
+		_, err := c.service.resolve(ctx, user, target.message())
+		switch {
+		case err == nil:
+			continue
+		case errors.Is(err, ErrForbidden),
+			errors.Is(err, ErrProjectArchived),
+			errors.Is(err, mongo.ErrNoDocuments):
+		default:
+			return nil, err
+		}
+
+		if err := c.Leave(ctx, target.message()); err != nil {
+			return nil, err
+		}
+		revoked = append(revoked, target.resource)
+	}
+
+	c.checkedAt = time.Now()
+	return revoked, nil
+}
+
+// resolve looks up what msg points at and checks that the user may be
+// present on it in the requested mode.
+func (s *PresenceService) resolve(
+	ctx context.Context,
+	currentUser *models.User,
+	msg dto.PresenceMessage,
+) (presenceTarget, error) {
+
+	resource, err := presenceResource(msg)
+	if err != nil {
+		return presenceTarget{}, err
+	}
+
+	target := presenceTarget{resource: resource, mode: msg.Mode}
+	switch target.mode {
+	case "":
+		target.mode = models.PresenceViewing
+	case models.PresenceViewing, models.PresenceEditing:
+	default:
+		return presenceTarget{}, ErrPresenceMode
+	}
+
+	var project *models.Project
+	if msg.Task != "" {
+		taskID, _ := primitive.ObjectIDFromHex(msg.Task)
+		task, err := s.taskRepo.FindByID(ctx, taskID)
+		if err != nil {
+			return presenceTarget{}, err
+		}
+		target.taskID = task.ID
+		if project, err = s.projectService.findProject(ctx, task.ProjectID); err != nil {
+			return presenceTarget{}, err
+		}
+	} else if project, err = s.projectService.loadProject(ctx, msg.Project); err != nil {
+		return presenceTarget{}, err
+	}
+	target.projectID = project.ID
+
+	action := actionViewProject
+	if target.mode == models.PresenceEditing {
+		action = actionUpdateTask
+	}
+	if err := authorizeProject(currentUser, project, action); err != nil {
+		return presenceTarget{}, err
+	}
+
+	return target, nil
+}
+
+func (t presenceTarget) message() dto.PresenceMessage {
+	if !t.taskID.IsZero() {
+		return dto.PresenceMessage{Task: t.taskID.Hex(), Mode: t.mode}
+	}
+	return dto.PresenceMessage{Project: t.projectID.Hex(), Mode: t.mode}
+}
+
+// presenceResource names the resource msg points at, "project:<id>" or
+// "task:<id>".
+func presenceResource(msg dto.PresenceMessage) (string, error) {
+	if (msg.Project == "") == (msg.Task == "") {
+		return "", ErrPresenceTarget
+	}
+
+	if msg.Task != "" {
+		taskID, err := primitive.ObjectIDFromHex(msg.Task)
+		if err != nil {
+			return "", errors.New("invalid task id")
+		}
+		return "task:" + taskID.Hex(), nil
+	}
+
+	projectID, err := primitive.ObjectIDFromHex(msg.Project)
+	if err != nil {
+		return "", errors.New("invalid project id")
+	}
+	return "project:" + projectID.Hex(), nil
+}
+
+// =========================
+// BROADCAST
+// =========================
+
+// StartBroadcastLoop sends changed presence lists to the sessions of this
+// instance until ctx is cancelled.
+func (s *PresenceService) StartBroadcastLoop(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(presencePollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-s.wake:
+			}
+			s.broadcast(ctx)
+		}
+	}()
+}
+
+// poke asks the broadcast loop to run now instead of at its next tick.
+func (s *PresenceService) poke() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *PresenceService) broadcast(ctx context.Context) {
+	s.mu.Lock()
+	joined := make(map[primitive.ObjectID]map[string]bool)
+	for _, session := range s.sessions {
+		if joined[session.workspaceID] == nil {
+			joined[session.workspaceID] = make(map[string]bool)
+		}
+		for resource := range session.joined {
+			joined[session.workspaceID][resource] = true
+		}
+	}
+	s.mu.Unlock()
+
+	now := time.Now()
+	for workspaceID, set := range joined {
+		resources := make([]string, 0, len(set))
+		for resource := range set {
+			resources = append(resources, resource)
+		}
+
+		entries, err := s.repo.FindActive(WithWorkspace(ctx, workspaceID), resources, now)
+		if err != nil {
+			log.Println("Presence broadcast error:", err)
+			continue
+		}
+		users := presenceUsers(entries)
+
+		s.mu.Lock()
+		for _, session := range s.sessions {
+			if session.workspaceID == workspaceID {
+				session.deliver(users)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// deliver sends the lists that changed since the last delivery. A session
+// that is not keeping up misses the update and gets it on a later round.
+// Must be called with service.mu held.
+func (c *PresenceSession) deliver(users map[string][]dto.PresenceUser) {
+	for resource, target := range c.joined {
+		list := users[resource]
+		if list == nil {
+			list = []dto.PresenceUser{}
+		}
+
+		encoded, _ := json.Marshal(list)
+		signature := string(encoded)
+		if c.sent[resource] == signature {
+			continue
+		}
+
+		update := dto.PresenceUpdate{
+			Type:      "presence",
+			Resource:  resource,
+			ProjectID: target.projectID.Hex(),
+			Users:     list,
+		}
+		if !target.taskID.IsZero() {
+			update.TaskID = target.taskID.Hex()
+		}
+
+		select {
+		case c.updates <- update:
+			c.sent[resource] = signature
+		default:
+		}
+	}
+}
+
+// presenceUsers groups entries by resource with one row per user. Entries
+// come oldest first, so each user keeps their earliest Since.
+func presenceUsers(entries []models.Presence) map[string][]dto.PresenceUser {
+	users := make(map[string][]dto.PresenceUser)
+	index := make(map[string]int)
+
+	for _, entry := range entries {
+		key := entry.Resource + "/" + entry.UserID.Hex()
+		if i, ok := index[key]; ok {
+			if entry.Mode == models.PresenceEditing {
+				users[entry.Resource][i].Mode = models.PresenceEditing
+			}
+			continue
+		}
+
+		index[key] = len(users[entry.Resource])
+		users[entry.Resource] = append(users[entry.Resource], dto.PresenceUser{
+			UserID: entry.UserID.Hex(),
+			Name:   entry.Name,
+			Mode:   entry.Mode,
+			Since:  entry.Since,
+		})
+	}
+	return users
+}
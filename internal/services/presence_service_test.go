@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPresenceResource(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	tests := []struct {
+		name    string
+		msg     dto.PresenceMessage
+		want    string
+		wantErr bool
+	}{
+		{"project", dto.PresenceMessage{Project: id.Hex()}, "project:" + id.Hex(), false},
+		{"task", dto.PresenceMessage{Task: id.Hex()}, "task:" + id.Hex(), false},
+		{"neither", dto.PresenceMessage{}, "", true},
+		{"both", dto.PresenceMessage{Project: id.Hex(), Task: id.Hex()}, "", true},
+		{"invalid id", dto.PresenceMessage{Task: "nope"}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := presenceResource(tt.msg)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("presenceResource = %q, %v; want %q, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPresenceUsers(t *testing.T) {
+	alice, bob := primitive.NewObjectID(), primitive.NewObjectID()
+	t0 := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+
+	got := presenceUsers([]models.Presence{
+		{Resource: "project:p", UserID: alice, Name: "Alice", Mode: models.PresenceViewing, Since: t0},
+		{Resource: "project:p", UserID: bob, Name: "Bob", Mode: models.PresenceViewing, Since: t0.Add(time.Minute)},
+		{Resource: "project:p", UserID: alice, Name: "Alice", Mode: models.PresenceEditing, Since: t0.Add(2 * time.Minute)},
+		{Resource: "task:t", UserID: bob, Name: "Bob", Mode: models.PresenceEditing, Since: t0},
+	})
+
+	want := map[string][]dto.PresenceUser{
+		"project:p": {
+			{UserID: alice.Hex(), Name: "Alice", Mode: models.PresenceEditing, Since: t0},
+			{UserID: bob.Hex(), Name: "Bob", Mode: models.PresenceViewing, Since: t0.Add(time.Minute)},
+		},
+		"task:t": {
+			{UserID: bob.Hex(), Name: "Bob", Mode: models.PresenceEditing, Since: t0},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("presenceUsers =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+type presenceFixture struct {
+	service   *PresenceService
+	presence  *fakePresenceRepository
+	project   *models.Project
+	task      *models.Task
+	owner     *models.User
+	viewer    *models.User
+	outsider  *models.User
+	workspace context.Context
+}
+
+func newPresenceFixture() *presenceFixture {
+	owner := &models.User{ID: primitive.NewObjectID(), Name: "Owner", Role: models.RoleAdmin}
+	viewer := &models.User{ID: primitive.NewObjectID(), Name: "Viewer", Role: models.RoleEmployee}
+	outsider := &models.User{ID: primitive.NewObjectID(), Name: "Outsider", Role: models.RoleEmployee}
+
+	project := &models.Project{
+		ID:        primitive.NewObjectID(),
+		OwnerID:   owner.ID,
+		MemberIDs: []primitive.ObjectID{viewer.ID},
+		Members:   []models.ProjectMember{{UserID: viewer.ID, Role: models.ProjectRoleViewer}},
+	}
+	task := &models.Task{ID: primitive.NewObjectID(), ProjectID: project.ID}
+
+	projects := &fakeProjectRepository{projects: []*models.Project{project}}
+	tasks := &fakeTaskRepository{tasks: []*models.Task{task}}
+	users := newFakeUserRepository(owner, viewer, outsider)
+	presence := &fakePresenceRepository{}
+
+	projectService := NewProjectService(projects, tasks, users, nil, fakeUnitOfWork{})
+	return &presenceFixture{
+		service:   NewPresenceService(presence, tasks, users, projectService, time.Minute),
+		presence:  presence,
+		project:   project,
+		task:      task,
+		owner:     owner,
+		viewer:    viewer,
+		outsider:  outsider,
+		workspace: WithWorkspace(context.Background(), primitive.NewObjectID()),
+	}
+}
+
+func (f *presenceFixture) connect(t *testing.T, user *models.User) *PresenceSession {
+	t.Helper()
+
+	session, err := f.service.Connect(f.workspace, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(session.Close)
+	return session
+}
+
+func TestPresenceJoin(t *testing.T) {
+	f := newPresenceFixture()
+	project, task := f.project.ID.Hex(), f.task.ID.Hex()
+
+	tests := []struct {
+		name    string
+		user    *models.User
+		msg     dto.PresenceMessage
+		wantErr error
+	}{
+		{"owner edits the board", f.owner, dto.PresenceMessage{Project: project, Mode: models.PresenceEditing}, nil},
+		{"viewer views the board", f.viewer, dto.PresenceMessage{Project: project}, nil},
+		{"viewer views a task", f.viewer, dto.PresenceMessage{Task: task, Mode: models.PresenceViewing}, nil},
+		{"viewer cannot edit a task", f.viewer, dto.PresenceMessage{Task: task, Mode: models.PresenceEditing}, ErrForbidden},
+		{"outsider cannot view", f.outsider, dto.PresenceMessage{Project: project}, ErrForbidden},
+		{"unknown mode", f.owner, dto.PresenceMessage{Project: project, Mode: "lurking"}, ErrPresenceMode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := f.connect(t, tt.user)
+			err := session.Join(f.workspace, tt.msg)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+
+			joined := len(session.joined) > 0
+			if joined != (tt.wantErr == nil) {
+				t.Errorf("joined = %v after err %v", joined, err)
+			}
+		})
+	}
+}
+
+// receive returns the update waiting for the session, if any.
+func receive(session *PresenceSession) (dto.PresenceUpdate, bool) {
+	select {
+	case update := <-session.Updates:
+		return update, true
+	default:
+		return dto.PresenceUpdate{}, false
+	}
+}
+
+func presenceNames(update dto.PresenceUpdate) []string {
+	names := []string{}
+	for _, u := range update.Users {
+		names = append(names, u.Name+" "+u.Mode)
+	}
+	return names
+}
+
+func TestPresenceBroadcastSendsChangedLists(t *testing.T) {
+	f := newPresenceFixture()
+	board := dto.PresenceMessage{Project: f.project.ID.Hex()}
+
+	owner := f.connect(t, f.owner)
+	viewer := f.connect(t, f.viewer)
+
+	if err := owner.Join(f.workspace, dto.PresenceMessage{Project: board.Project, Mode: models.PresenceEditing}); err != nil {
+		t.Fatal(err)
+	}
+	if err := viewer.Join(f.workspace, board); err != nil {
+		t.Fatal(err)
+	}
+
+	f.service.broadcast(context.Background())
+
+	want := []string{"Owner editing", "Viewer viewing"}
+	for _, session := range []*PresenceSession{owner, viewer} {
+		update, ok := receive(session)
+		if !ok {
+			t.Fatalf("%s got no update", session.user.Name)
+		}
+		if update.Resource != "project:"+board.Project || update.ProjectID != board.Project {
+			t.Errorf("%s got an update for %s", session.user.Name, update.Resource)
+		}
+		if got := presenceNames(update); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s sees %v, want %v", session.user.Name, got, want)
+		}
+	}
+
+	f.service.broadcast(context.Background())
+	if update, ok := receive(owner); ok {
+		t.Errorf("unchanged list was sent again: %+v", update)
+	}
+
+	if err := viewer.Leave(f.workspace, board); err != nil {
+		t.Fatal(err)
+	}
+	f.service.broadcast(context.Background())
+
+	update, ok := receive(owner)
+	if !ok {
+		t.Fatal("owner was not told the viewer left")
+	}
+	if got := presenceNames(update); !reflect.DeepEqual(got, []string{"Owner editing"}) {
+		t.Errorf("owner sees %v after the viewer left", got)
+	}
+	if update, ok := receive(viewer); ok {
+		t.Errorf("viewer still gets updates after leaving: %+v", update)
+	}
+}
@@ -0,0 +1,539 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	ProjectExportFormat  = "project-export"
+	ProjectExportVersion = 1
+)
+
+// ExportService takes snapshots of projects and recreates projects from
+// them, possibly on another instance.
+type ExportService struct {
+	projectService *ProjectService
+	projectRepo    repositories.ProjectRepository
+	taskRepo       repositories.TaskRepository
+	sprintRepo     repositories.SprintRepository
+	milestoneRepo  repositories.MilestoneRepository
+	events         repositories.TaskEventRepository
+	userRepo       repositories.UserRepository
+	teamRepo       repositories.TeamRepository
+	uow            repositories.UnitOfWork
+}
+
+func NewExportService(
+	projectService *ProjectService,
+	projectRepo repositories.ProjectRepository,
+	taskRepo repositories.TaskRepository,
+	sprintRepo repositories.SprintRepository,
+	milestoneRepo repositories.MilestoneRepository,
+	events repositories.TaskEventRepository,
+	userRepo repositories.UserRepository,
+	teamRepo repositories.TeamRepository,
+	uow repositories.UnitOfWork,
+) *ExportService {
+	return &ExportService{
+		projectService: projectService,
+		projectRepo:    projectRepo,
+		taskRepo:       taskRepo,
+		sprintRepo:     sprintRepo,
+		milestoneRepo:  milestoneRepo,
+		events:         events,
+		userRepo:       userRepo,
+		teamRepo:       teamRepo,
+		uow:            uow,
+	}
+}
+
+// =====================
+// EXPORT
+// =====================
+
+// ExportWriter receives an export as it is read: the tasks column by
+// column in board order, each column announced with its size, then the
+// history events grouped by task. Close ends the export.
+type ExportWriter interface {
+	Column(status string, count int) error
+	Task(task *models.Task) error
+	Event(event *models.TaskEvent) error
+	Close() error
+}
+
+// ExportProject exports the project with its members, teams, milestones,
+// sprints and live tasks in board order. With includeHistory the task
+// events come along too. Everything is read from one snapshot so the parts
+// agree with each other, and tasks and events are handed to the writer as
+// they come off the cursors instead of being loaded first.
+//
+// open is called with the export minus its tasks and history once the
+// caller is authorized and the header has been read; an error returned
+// before that means nothing was written.
+func (s *ExportService) ExportProject(
+	ctx context.Context,
+	currentUser *models.User,
+	projectID string,
+	includeHistory bool,
+	open func(header *dto.ProjectExport) (ExportWriter, error),
+) error {
+
+	project, err := s.projectService.loadProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeProject(currentUser, project, actionViewProject); err != nil {
+		return err
+	}
+
+	return s.uow.Snapshot(ctx, func(ctx context.Context) error {
+		project, err := s.projectRepo.FindByID(ctx, project.ID)
+		if err != nil {
+			return err
+		}
+
+		header := &dto.ProjectExport{
+			Format:     ProjectExportFormat,
+			Version:    ProjectExportVersion,
+			ExportedAt: time.Now(),
+			Project:    *project,
+		}
+
+		assignees, assignedTeams, err := s.taskRepo.FindAssignees(ctx, project.ID)
+		if err != nil {
+			return err
+		}
+
+		if header.Milestones, err = s.milestoneRepo.FindByProjectID(ctx, project.ID, ""); err != nil {
+			return err
+		}
+		if header.Sprints, err = s.sprintRepo.FindByProjectID(ctx, project.ID); err != nil {
+			return err
+		}
+		if header.Members, err = s.exportMembers(ctx, project, assignees); err != nil {
+			return err
+		}
+		if header.Teams, err = s.exportTeams(ctx, project, assignedTeams); err != nil {
+			return err
+		}
+
+		out, err := open(header)
+		if err != nil {
+			return err
+		}
+
+		var taskIDs []primitive.ObjectID
+
+		for _, status := range models.TaskStatuses {
+			count, err := s.taskRepo.CountInColumn(ctx, project.ID, status)
+			if err != nil {
+				return err
+			}
+			if err := out.Column(status, int(count)); err != nil {
+				return err
+			}
+
+			err = s.taskRepo.EachInColumn(ctx, project.ID, status, func(task *models.Task) error {
+				if includeHistory {
+					taskIDs = append(taskIDs, task.ID)
+				}
+				return out.Task(task)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(taskIDs) > 0 {
+			if err := s.events.EachByTaskIDs(ctx, taskIDs, header.ExportedAt, out.Event); err != nil {
+				return err
+			}
+		}
+
+		return out.Close()
+	})
+}
+
+// exportMembers lists the owner, then the members in project order, then
+// anyone else tasks are assigned to. Users deleted since keep their id
+// with blank details.
+func (s *ExportService) exportMembers(
+	ctx context.Context,
+	project *models.Project,
+	assignees []primitive.ObjectID,
+) ([]dto.ExportMember, error) {
+
+	roles := map[primitive.ObjectID]string{project.OwnerID: models.ProjectRoleOwner}
+	ids := []primitive.ObjectID{project.OwnerID}
+
+	for _, m := range project.Members {
+		if _, ok := roles[m.UserID]; !ok {
+			roles[m.UserID] = m.Role
+			ids = append(ids, m.UserID)
+		}
+	}
+	for _, id := range assignees {
+		if _, ok := roles[id]; !ok {
+			roles[id] = ""
+			ids = append(ids, id)
+		}
+	}
+
+	users, err := s.userRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[primitive.ObjectID]models.User, len(users))
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+
+	members := make([]dto.ExportMember, len(ids))
+	for i, id := range ids {
+		u := byID[id]
+		members[i] = dto.ExportMember{
+			ID:     id,
+			UserID: u.UserID,
+			Name:   u.Name,
+			Email:  u.Email,
+			Role:   roles[id],
+		}
+	}
+
+	return members, nil
+}
+
+func (s *ExportService) exportTeams(
+	ctx context.Context,
+	project *models.Project,
+	assignedTeams []primitive.ObjectID,
+) ([]dto.ExportTeam, error) {
+
+	roles := make(map[primitive.ObjectID]string)
+	ids := []primitive.ObjectID{}
+
+	for _, t := range project.Teams {
+		if _, ok := roles[t.TeamID]; !ok {
+			roles[t.TeamID] = t.Role
+			ids = append(ids, t.TeamID)
+		}
+	}
+	for _, id := range assignedTeams {
+		if _, ok := roles[id]; !ok {
+			roles[id] = ""
+			ids = append(ids, id)
+		}
+	}
+
+	teams := []dto.ExportTeam{}
+	if len(ids) == 0 {
+		return teams, nil
+	}
+
+	found, err := s.teamRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[primitive.ObjectID]string, len(found))
+	for _, t := range found {
+		names[t.ID] = t.Name
+	}
+
+	for _, id := range ids {
+		teams = append(teams, dto.ExportTeam{ID: id, Name: names[id], Role: roles[id]})
+	}
+
+	return teams, nil
+}
+
+// =====================
+// IMPORT
+// =====================
+
+// ImportProject recreates an exported project in the current workspace
+// with new ids. Members are matched by email, then by user_id, and teams
+// by name; whatever has no match here is reported and left out. The
+// exported owner keeps the project if they can own one here, otherwise the
+// caller becomes owner and the exported owner a maintainer. Everything is
+// written in one transaction.
+func (s *ExportService) ImportProject(
+	ctx context.Context,
+	currentUser *models.User,
+	export *dto.ProjectExport,
+	name string,
+) (*dto.ProjectImportResponse, error) {
+
+	if !isAdminCapable(currentUser) {
+		return nil, ErrForbidden
+	}
+
+	errs := &ValidationError{}
+	if export.Format != ProjectExportFormat {
+		errs.add("format", "must be \""+ProjectExportFormat+"\"")
+	}
+	if export.Version != ProjectExportVersion {
+		errs.add("version", "unsupported export version")
+	}
+	if err := errs.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	resp := &dto.ProjectImportResponse{
+		UnmatchedMembers: []string{},
+		UnmatchedTeams:   []string{},
+	}
+
+	users, err := s.matchMembers(ctx, export.Members, resp)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := s.matchTeams(ctx, export.Teams, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	source := &export.Project
+	if name == "" {
+		name = source.Name
+	}
+
+	project := &models.Project{
+		Name:        name,
+		Description: source.Description,
+		OwnerID:     currentUser.ID,
+		WIPLimits:   source.WIPLimits,
+	}
+	if owner, ok := users[source.OwnerID]; ok {
+		if isAdminCapable(owner) {
+			project.OwnerID = owner.ID
+		} else {
+			project.Members = append(project.Members, models.ProjectMember{
+				UserID: owner.ID,
+				Role:   models.ProjectRoleMaintainer,
+			})
+		}
+	}
+	for _, m := range source.Members {
+		if u, ok := users[m.UserID]; ok {
+			project.Members = append(project.Members, models.ProjectMember{UserID: u.ID, Role: m.Role})
+		}
+	}
+
+	now := time.Now()
+	if err := s.projectService.prepareNewProject(ctx, project, now); err != nil {
+		return nil, err
+	}
+
+	for _, t := range source.Teams {
+		if id, ok := teams[t.TeamID]; ok {
+			project.Teams = append(project.Teams, models.ProjectTeam{TeamID: id, Role: t.Role, AddedAt: t.AddedAt})
+		}
+	}
+	if !source.CreatedAt.IsZero() {
+		project.CreatedAt = source.CreatedAt
+	}
+	if source.ArchivedAt != nil {
+		project.ArchivedAt = source.ArchivedAt
+		project.ArchivedBy = &currentUser.ID
+		if u, ok := users[derefID(source.ArchivedBy)]; ok {
+			project.ArchivedBy = &u.ID
+		}
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		project.ID = primitive.NilObjectID
+		if err := s.projectRepo.Create(ctx, project); err != nil {
+			return err
+		}
+
+		milestoneIDs := make(map[primitive.ObjectID]primitive.ObjectID, len(export.Milestones))
+		for _, m := range export.Milestones {
+			oldID := m.ID
+			m.ID = primitive.NilObjectID
+			m.ProjectID = project.ID
+			if err := s.milestoneRepo.Create(ctx, &m); err != nil {
+				return err
+			}
+			milestoneIDs[oldID] = m.ID
+		}
+
+		// Sprints point at the sprint they carried over to, which may come
+		// later in the list, so ids are patched once all exist.
+		sprintIDs := make(map[primitive.ObjectID]primitive.ObjectID, len(export.Sprints))
+		for _, sp := range export.Sprints {
+			oldID := sp.ID
+			sp.ID = primitive.NilObjectID
+			sp.ProjectID = project.ID
+			sp.CarriedOverTo = nil
+			if err := s.sprintRepo.Create(ctx, &sp); err != nil {
+				return err
+			}
+			sprintIDs[oldID] = sp.ID
+		}
+		for _, sp := range export.Sprints {
+			if sp.CarriedOverTo == nil {
+				continue
+			}
+			to, ok := sprintIDs[*sp.CarriedOverTo]
+			if !ok {
+				continue
+			}
+			if err := s.sprintRepo.UpdateByID(ctx, sprintIDs[sp.ID], bson.M{"carriedOverTo": to}); err != nil {
+				return err
+			}
+		}
+
+		tasks := make([]models.Task, len(export.Tasks))
+		for i, t := range export.Tasks {
+			t.ID = primitive.NilObjectID
+			t.ProjectID = project.ID
+			t.SprintID = sprintIDs[t.SprintID]
+			t.MilestoneID = milestoneIDs[t.MilestoneID]
+			t.EpicID = milestoneIDs[t.EpicID]
+			t.AssignedTeamID = teams[t.AssignedTeamID]
+			t.AssignedTo = primitive.NilObjectID
+			if u, ok := users[export.Tasks[i].AssignedTo]; ok && isProjectMemberUser(project, u) {
+				t.AssignedTo = u.ID
+			}
+			t.DeletedAt, t.DeletedBy = nil, nil
+			tasks[i] = t
+		}
+		if err := s.taskRepo.CreateMany(ctx, tasks); err != nil {
+			return err
+		}
+
+		taskIDs := make(map[primitive.ObjectID]primitive.ObjectID, len(tasks))
+		for i := range tasks {
+			taskIDs[export.Tasks[i].ID] = tasks[i].ID
+		}
+
+		events := make([]models.TaskEvent, 0, len(export.History))
+		for _, e := range export.History {
+			taskID, ok := taskIDs[e.TaskID]
+			if !ok {
+				continue
+			}
+			e.ID = primitive.NilObjectID
+			e.TaskID = taskID
+			e.ProjectID = project.ID
+			e.SprintID = sprintIDs[e.SprintID]
+			events = append(events, e)
+		}
+		if err := s.events.CreateMany(ctx, events); err != nil {
+			return err
+		}
+
+		resp.Project = project
+		resp.Milestones = len(milestoneIDs)
+		resp.Sprints = len(sprintIDs)
+		resp.Tasks = len(tasks)
+		resp.History = len(events)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// matchMembers maps exported user ids to users of this workspace.
+func (s *ExportService) matchMembers(
+	ctx context.Context,
+	members []dto.ExportMember,
+	resp *dto.ProjectImportResponse,
+) (map[primitive.ObjectID]*models.User, error) {
+
+	users := make(map[primitive.ObjectID]*models.User, len(members))
+
+	for _, m := range members {
+		var user *models.User
+		var err error
+
+		switch {
+		case m.Email != "":
+			user, err = s.userRepo.FindByEmail(ctx, strings.ToLower(m.Email))
+			if errors.Is(err, mongo.ErrNoDocuments) && m.UserID != "" {
+				user, err = s.userRepo.FindByUserID(ctx, m.UserID)
+			}
+		case m.UserID != "":
+			user, err = s.userRepo.FindByUserID(ctx, m.UserID)
+		default:
+			err = mongo.ErrNoDocuments
+		}
+
+		if errors.Is(err, mongo.ErrNoDocuments) || (err == nil && !user.IsActive()) {
+			resp.UnmatchedMembers = append(resp.UnmatchedMembers, describeMember(m))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		users[m.ID] = user
+	}
+
+	return users, nil
+}
+
+// matchTeams maps exported team ids to teams of this workspace with the
+// same name, ignoring case.
+func (s *ExportService) matchTeams(
+	ctx context.Context,
+	exported []dto.ExportTeam,
+	resp *dto.ProjectImportResponse,
+) (map[primitive.ObjectID]primitive.ObjectID, error) {
+
+	ids := make(map[primitive.ObjectID]primitive.ObjectID, len(exported))
+	if len(exported) == 0 {
+		return ids, nil
+	}
+
+	teams, err := s.teamRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]primitive.ObjectID, len(teams))
+	for _, t := range teams {
+		byName[strings.ToLower(t.Name)] = t.ID
+	}
+
+	for _, t := range exported {
+		id, ok := byName[strings.ToLower(t.Name)]
+		if !ok {
+			resp.UnmatchedTeams = append(resp.UnmatchedTeams, t.Name)
+			continue
+		}
+		ids[t.ID] = id
+	}
+
+	return ids, nil
+}
+
+func describeMember(m dto.ExportMember) string {
+	if m.Email != "" {
+		return m.Email
+	}
+	if m.UserID != "" {
+		return m.UserID
+	}
+	return m.ID.Hex()
+}
+
+func derefID(id *primitive.ObjectID) primitive.ObjectID {
+	if id == nil {
+		return primitive.NilObjectID
+	}
+	return *id
+}
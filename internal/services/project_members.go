@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrAlreadyMember    = errors.New("user is already a member of this project")
+	ErrNotMember        = errors.New("user is not a member of this project")
+	ErrOwnershipChanged = errors.New("project ownership changed concurrently, reload and retry")
+)
+
+// =====================
+// READ
+// =====================
+// GetMembers lists everyone with access to the project. With assignableOnly
+// it leaves out suspended and deactivated users, for assignee pickers.
+func (s *ProjectService) GetMembers(
+	ctx context.Context,
+	currentUser *models.User,
+	projectID string,
+	assignableOnly bool,
+) ([]dto.ProjectMemberResponse, error) {
+
+	project, err := s.loadProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeProject(currentUser, project, actionViewProject); err != nil {
+		return nil, err
+	}
+
+	ids := append([]primitive.ObjectID{project.OwnerID}, project.MemberIDs...)
+
+	// Team members are expanded at read time so they follow the team.
+	viaTeams := make(map[primitive.ObjectID][]models.ProjectTeam)
+	if len(project.Teams) > 0 {
+		teamIDs := make([]primitive.ObjectID, 0, len(project.Teams))
+		byTeam := make(map[primitive.ObjectID]models.ProjectTeam, len(project.Teams))
+		for _, t := range project.Teams {
+			teamIDs = append(teamIDs, t.TeamID)
+			byTeam[t.TeamID] = t
+		}
+
+		teams, err := s.teamRepo.FindByIDs(ctx, teamIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, team := range teams {
+			for _, id := range team.MemberIDs {
+				if _, listed := viaTeams[id]; !listed && !isProjectMember(project, id) {
+					ids = append(ids, id)
+				}
+				viaTeams[id] = append(viaTeams[id], byTeam[team.ID])
+			}
+		}
+	}
+
+	users, err := s.userRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	usersByID := make(map[primitive.ObjectID]models.User, len(users))
+	for _, u := range users {
+		usersByID[u.ID] = u
+	}
+
+	roles := make(map[primitive.ObjectID]models.ProjectMember, len(project.Members))
+	for _, m := range project.Members {
+		roles[m.UserID] = m
+	}
+
+	members := make([]dto.ProjectMemberResponse, 0, len(ids))
+
+	for i, id := range ids {
+		user, ok := usersByID[id]
+		if !ok || (assignableOnly && !user.IsActive()) {
+			continue
+		}
+
+		member := dto.ProjectMemberResponse{
+			ID:     user.ID.Hex(),
+			UserID: user.UserID,
+			Name:   user.Name,
+			Email:  user.Email,
+			Role:   models.ProjectRoleContributor,
+			Status: user.Status,
+		}
+		if member.Status == "" {
+			member.Status = models.UserStatusActive
+		}
+
+		direct := i <= len(project.MemberIDs)
+		if !direct {
+			member.Role = ""
+		}
+
+		if i == 0 {
+			member.Role = models.ProjectRoleOwner
+			member.AddedAt = project.CreatedAt
+		} else if m, ok := roles[id]; ok {
+			member.Role = m.Role
+			member.AddedAt = m.AddedAt
+		}
+
+		for _, t := range viaTeams[id] {
+			member.Teams = append(member.Teams, t.TeamID.Hex())
+			if !direct && (member.AddedAt.IsZero() || t.AddedAt.Before(member.AddedAt)) {
+				member.AddedAt = t.AddedAt
+			}
+			if projectRoleRank[t.Role] > projectRoleRank[member.Role] {
+				member.Role = t.Role
+			}
+		}
+
+		members = append(members, member)
+	}
+
+	return members, nil
+}
+
+// =====================
+// ADD / UPDATE / REMOVE
+// =====================
+func (s *ProjectService) AddMember(
+	ctx context.Context,
+	currentUser *models.User,
+	projectID string,
+	userID string,
+	role string,
+) error {
+
+	project, err := s.loadProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeProject(currentUser, project, actionManageMembers); err != nil {
+		return err
+	}
+
+	if role == "" {
+		role = models.ProjectRoleContributor
+	}
+
+	errs := &ValidationError{}
+
+	if !isValidMemberRole(role) {
+		errs.add("role", "must be one of viewer, contributor, maintainer")
+	}
+
+	memberID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		errs.add("userId", "must be a valid ObjectID")
+	} else if _, err := s.userRepo.FindByID(ctx, memberID); err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return err
+		}
+		errs.add("userId", "user not found")
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return err
+	}
+
+	added, err := s.repo.AddMember(ctx, project.ID, models.ProjectMember{
+		UserID:  memberID,
+		Role:    role,
+		AddedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if !added {
+		return ErrAlreadyMember
+	}
+
+	return nil
+}
+
+func (s *ProjectService) UpdateMemberRole(
+	ctx context.Context,
+	currentUser *models.User,
+	projectID string,
+	userID string,
+	role string,
+) error {
+
+	project, err := s.loadProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeProject(currentUser, project, actionManageMembers); err != nil {
+		return err
+	}
+
+	if !isValidMemberRole(role) {
+		errs := &ValidationError{}
+		errs.add("role", "must be one of viewer, contributor, maintainer")
+		return errs
+	}
+
+	memberID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.New("invalid user id")
+	}
+
+	updated, err := s.repo.UpdateMemberRole(ctx, project.ID, memberID, role)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrNotMember
+	}
+
+	return nil
+}
+
+// RemoveMember also unassigns the member's tasks in the project. Members may
+// always remove themselves.
+func (s *ProjectService) RemoveMember(
+	ctx context.Context,
+	currentUser *models.User,
+	projectID string,
+	userID string,
+) error {
+
+	project, err := s.loadProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	memberID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.New("invalid user id")
+	}
+
+	if memberID != currentUser.ID {
+		if err := authorizeProject(currentUser, project, actionManageMembers); err != nil {
+			return err
+		}
+	} else if err := ensureNotArchived(project); err != nil {
+		return err
+	}
+
+	if memberID == project.OwnerID {
+		return errors.New("the owner cannot be removed; transfer ownership first")
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		removed, err := s.repo.RemoveMember(ctx, project.ID, memberID)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return ErrNotMember
+		}
+
+		_, err = s.taskRepo.UnassignUserInProject(ctx, project.ID, memberID)
+		return err
+	})
+}
+
+// =====================
+// OWNERSHIP
+// =====================
+func (s *ProjectService) TransferOwnership(
+	ctx context.Context,
+	currentUser *models.User,
+	projectID string,
+	newOwnerID string,
+) error {
+
+	project, err := s.loadProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeProject(currentUser, project, actionTransferOwnership); err != nil {
+		return err
+	}
+
+	errs := &ValidationError{}
+
+	toID, err := primitive.ObjectIDFromHex(newOwnerID)
+	if err != nil {
+		errs.add("newOwnerId", "must be a valid ObjectID")
+	} else if toID == project.OwnerID {
+		errs.add("newOwnerId", "user already owns this project")
+	} else if err := s.validateOwner(ctx, "newOwnerId", toID, errs); err != nil {
+		return err
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return err
+	}
+
+	changed, err := s.repo.ChangeOwner(ctx, project.ID, project.OwnerID, toID)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return ErrOwnershipChanged
+	}
+
+	return nil
+}
+
+// normalizeMembers merges memberIds and members from a create request into
+// one list, defaulting roles to contributor, and keeps MemberIDs in sync.
+func normalizeMembers(project *models.Project, now time.Time, errs *ValidationError) {
+	seen := make(map[primitive.ObjectID]bool)
+	members := make([]models.ProjectMember, 0, len(project.Members)+len(project.MemberIDs))
+
+	for i, m := range project.Members {
+		if m.Role == "" {
+			m.Role = models.ProjectRoleContributor
+		}
+		if !isValidMemberRole(m.Role) {
+			errs.add(fmt.Sprintf("members[%d].role", i), "must be one of viewer, contributor, maintainer")
+		}
+		if m.UserID == project.OwnerID || seen[m.UserID] {
+			continue
+		}
+		m.AddedAt = now
+		seen[m.UserID] = true
+		members = append(members, m)
+	}
+
+	for _, id := range project.MemberIDs {
+		if id == project.OwnerID || seen[id] {
+			continue
+		}
+		seen[id] = true
+		members = append(members, models.ProjectMember{
+			UserID:  id,
+			Role:    models.ProjectRoleContributor,
+			AddedAt: now,
+		})
+	}
+
+	project.Members = members
+	project.MemberIDs = make([]primitive.ObjectID, 0, len(members))
+	for _, m := range members {
+		project.MemberIDs = append(project.MemberIDs, m.UserID)
+	}
+}
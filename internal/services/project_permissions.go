@@ -0,0 +1,135 @@
+package services
+
+import (
+	"errors"
+
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrForbidden       = errors.New("you do not have permission to perform this action")
+	ErrProjectArchived = errors.New("project is archived and read-only; unarchive it first")
+)
+
+type projectAction int
+
+const (
+	actionViewProject projectAction = iota
+	actionCreateTask
+	actionUpdateTask
+	actionDeleteTask
+	actionUpdateProject
+	actionManageMembers
+	actionManagePlanning
+	actionSaveTemplate
+	actionArchiveProject
+	actionDeleteProject
+	actionTransferOwnership
+)
+
+// Minimum project role needed for each action. Roles are ordered
+// viewer < contributor < maintainer < owner; super_admin acts as owner.
+var requiredProjectRole = map[projectAction]string{
+	actionViewProject:       models.ProjectRoleViewer,
+	actionCreateTask:        models.ProjectRoleContributor,
+	actionUpdateTask:        models.ProjectRoleContributor,
+	actionDeleteTask:        models.ProjectRoleMaintainer,
+	actionUpdateProject:     models.ProjectRoleMaintainer,
+	actionManageMembers:     models.ProjectRoleMaintainer,
+	actionManagePlanning:    models.ProjectRoleMaintainer,
+	actionSaveTemplate:      models.ProjectRoleMaintainer,
+	actionArchiveProject:    models.ProjectRoleOwner,
+	actionDeleteProject:     models.ProjectRoleOwner,
+	actionTransferOwnership: models.ProjectRoleOwner,
+}
+
+// Actions still allowed on an archived project; everything else is
+// rejected with ErrProjectArchived.
+var archivedProjectActions = map[projectAction]bool{
+	actionViewProject:    true,
+	actionSaveTemplate:   true,
+	actionArchiveProject: true,
+	actionDeleteProject:  true,
+}
+
+var projectRoleRank = map[string]int{
+	models.ProjectRoleViewer:      1,
+	models.ProjectRoleContributor: 2,
+	models.ProjectRoleMaintainer:  3,
+	models.ProjectRoleOwner:       4,
+}
+
+func isValidMemberRole(role string) bool {
+	switch role {
+	case models.ProjectRoleViewer, models.ProjectRoleContributor, models.ProjectRoleMaintainer:
+		return true
+	}
+	return false
+}
+
+// projectRoleOf returns the user's role in the project, or "" if they have none.
+// Members listed only in MemberIDs predate roles and count as contributors.
+// A user who also gets a role through one of their teams gets the higher one.
+func projectRoleOf(project *models.Project, user *models.User) string {
+	if user.Role == models.RoleSuperAdmin || project.OwnerID == user.ID {
+		return models.ProjectRoleOwner
+	}
+
+	role := directRoleOf(project, user.ID)
+
+	for _, t := range project.Teams {
+		if projectRoleRank[t.Role] > projectRoleRank[role] && inTeam(user, t.TeamID) {
+			role = t.Role
+		}
+	}
+
+	return role
+}
+
+func directRoleOf(project *models.Project, userID primitive.ObjectID) string {
+	for _, m := range project.Members {
+		if m.UserID == userID {
+			return m.Role
+		}
+	}
+
+	for _, id := range project.MemberIDs {
+		if id == userID {
+			return models.ProjectRoleContributor
+		}
+	}
+
+	return ""
+}
+
+func inTeam(user *models.User, teamID primitive.ObjectID) bool {
+	for _, id := range user.TeamIDs {
+		if id == teamID {
+			return true
+		}
+	}
+	return false
+}
+
+func canOnProject(user *models.User, project *models.Project, action projectAction) bool {
+	return projectRoleRank[projectRoleOf(project, user)] >= projectRoleRank[requiredProjectRole[action]]
+}
+
+func authorizeProject(user *models.User, project *models.Project, action projectAction) error {
+	if !canOnProject(user, project, action) {
+		return ErrForbidden
+	}
+	if !archivedProjectActions[action] {
+		return ensureNotArchived(project)
+	}
+	return nil
+}
+
+func ensureNotArchived(project *models.Project) error {
+	if project.ArchivedAt != nil {
+		return ErrProjectArchived
+	}
+	return nil
+}
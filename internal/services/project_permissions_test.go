@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type permissionFixture struct {
+	owner, maintainer, viewer, legacy, teamMember, outsider, superAdmin *models.User
+	project                                                             *models.Project
+}
+
+func newPermissionFixture() *permissionFixture {
+	newUser := func(role string) *models.User {
+		return &models.User{ID: primitive.NewObjectID(), Role: role}
+	}
+
+	f := &permissionFixture{
+		owner:      newUser(models.RoleAdmin),
+		maintainer: newUser(models.RoleEmployee),
+		viewer:     newUser(models.RoleEmployee),
+		legacy:     newUser(models.RoleEmployee),
+		teamMember: newUser(models.RoleEmployee),
+		outsider:   newUser(models.RoleAdmin),
+		superAdmin: newUser(models.RoleSuperAdmin),
+	}
+
+	team := primitive.NewObjectID()
+	f.teamMember.TeamIDs = []primitive.ObjectID{team}
+	// A viewer whose team makes them a contributor gets the higher role.
+	f.viewer.TeamIDs = []primitive.ObjectID{team}
+
+	f.project = &models.Project{
+		ID:      primitive.NewObjectID(),
+		OwnerID: f.owner.ID,
+		Members: []models.ProjectMember{
+			{UserID: f.maintainer.ID, Role: models.ProjectRoleMaintainer},
+			{UserID: f.viewer.ID, Role: models.ProjectRoleViewer},
+		},
+		MemberIDs: []primitive.ObjectID{f.maintainer.ID, f.viewer.ID, f.legacy.ID},
+		Teams:     []models.ProjectTeam{{TeamID: team, Role: models.ProjectRoleContributor}},
+	}
+	return f
+}
+
+func TestProjectRoleOf(t *testing.T) {
+	f := newPermissionFixture()
+
+	cases := []struct {
+		name string
+		user *models.User
+		want string
+	}{
+		{"owner", f.owner, models.ProjectRoleOwner},
+		{"super_admin", f.superAdmin, models.ProjectRoleOwner},
+		{"maintainer", f.maintainer, models.ProjectRoleMaintainer},
+		{"viewer raised by a team", f.viewer, models.ProjectRoleContributor},
+		{"member without a role", f.legacy, models.ProjectRoleContributor},
+		{"team member", f.teamMember, models.ProjectRoleContributor},
+		{"admin outside the project", f.outsider, ""},
+	}
+
+	for _, c := range cases {
+		if got := projectRoleOf(f.project, c.user); got != c.want {
+			t.Errorf("%s: role = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestAuthorizeProject(t *testing.T) {
+	f := newPermissionFixture()
+	archived := *f.project
+	now := time.Now()
+	archived.ArchivedAt = &now
+
+	cases := []struct {
+		name    string
+		user    *models.User
+		project *models.Project
+		action  projectAction
+		want    error
+	}{
+		{"contributor views", f.teamMember, f.project, actionViewProject, nil},
+		{"contributor updates a task", f.teamMember, f.project, actionUpdateTask, nil},
+		{"contributor deletes a task", f.teamMember, f.project, actionDeleteTask, ErrForbidden},
+		{"maintainer manages members", f.maintainer, f.project, actionManageMembers, nil},
+		{"maintainer archives", f.maintainer, f.project, actionArchiveProject, ErrForbidden},
+		{"owner archives", f.owner, f.project, actionArchiveProject, nil},
+		{"outsider views", f.outsider, f.project, actionViewProject, ErrForbidden},
+		{"viewer views an archived project", f.legacy, &archived, actionViewProject, nil},
+		{"owner updates an archived project", f.owner, &archived, actionUpdateProject, ErrProjectArchived},
+		{"outsider updates an archived project", f.outsider, &archived, actionUpdateProject, ErrForbidden},
+		{"owner deletes an archived project", f.owner, &archived, actionDeleteProject, nil},
+	}
+
+	for _, c := range cases {
+		if err := authorizeProject(c.user, c.project, c.action); !errors.Is(err, c.want) {
+			t.Errorf("%s: err = %v, want %v", c.name, err, c.want)
+		}
+	}
+}
+
+func TestGetProjectByIDNeedsViewAccess(t *testing.T) {
+	f := newPermissionFixture()
+	s := NewProjectService(&fakeProjectRepository{projects: []*models.Project{f.project}}, &fakeTaskRepository{}, nil, nil, nil)
+
+	if _, err := s.GetProjectByID(context.Background(), f.outsider, f.project.ID.Hex()); !errors.Is(err, ErrForbidden) {
+		t.Errorf("outsider: err = %v, want %v", err, ErrForbidden)
+	}
+
+	detail, err := s.GetProjectByID(context.Background(), f.legacy, f.project.ID.Hex())
+	if err != nil {
+		t.Fatalf("member: %v", err)
+	}
+	if detail.Project.ID != f.project.ID {
+		t.Errorf("got project %s, want %s", detail.Project.ID.Hex(), f.project.ID.Hex())
+	}
+}
+
+func TestGetProjectsByUserHidesProjectsTheCallerCannotView(t *testing.T) {
+	f := newPermissionFixture()
+	other := &models.Project{
+		ID:        primitive.NewObjectID(),
+		OwnerID:   f.outsider.ID,
+		MemberIDs: []primitive.ObjectID{f.maintainer.ID},
+	}
+
+	s := NewProjectService(
+		&fakeProjectRepository{projects: []*models.Project{f.project, other}},
+		nil,
+		newFakeUserRepository(f.maintainer),
+		nil,
+		nil,
+	)
+
+	cases := []struct {
+		name   string
+		caller *models.User
+		want   []primitive.ObjectID
+	}{
+		{"the user themselves", f.maintainer, []primitive.ObjectID{f.project.ID, other.ID}},
+		{"a member of one project", f.legacy, []primitive.ObjectID{f.project.ID}},
+		{"the owner of the other", f.outsider, []primitive.ObjectID{other.ID}},
+		{"super_admin", f.superAdmin, []primitive.ObjectID{f.project.ID, other.ID}},
+	}
+
+	for _, c := range cases {
+		projects, err := s.GetProjectsByUser(context.Background(), c.caller, f.maintainer.ID.Hex(), false)
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+
+		got := make(map[primitive.ObjectID]bool)
+		for _, p := range projects {
+			got[p.ID] = true
+		}
+		if len(got) != len(c.want) {
+			t.Errorf("%s: got %d projects, want %d", c.name, len(got), len(c.want))
+		}
+		for _, id := range c.want {
+			if !got[id] {
+				t.Errorf("%s: project %s missing", c.name, id.Hex())
+			}
+		}
+	}
+}
@@ -3,22 +3,44 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
+	"Concurrent_Task_Management_System/internal/dto"
 	"Concurrent_Task_Management_System/internal/models"
 	"Concurrent_Task_Management_System/internal/repositories"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrAlreadyArchived = errors.New("project is already archived")
+	ErrNotArchived     = errors.New("project is not archived")
 )
 
 type ProjectService struct {
-	repo repositories.ProjectRepository
+	repo     repositories.ProjectRepository
+	taskRepo repositories.TaskRepository
+	userRepo repositories.UserRepository
+	teamRepo repositories.TeamRepository
+	uow      repositories.UnitOfWork
 }
 
-func NewProjectService(repo repositories.ProjectRepository) *ProjectService {
+func NewProjectService(
+	repo repositories.ProjectRepository,
+	taskRepo repositories.TaskRepository,
+	userRepo repositories.UserRepository,
+	teamRepo repositories.TeamRepository,
+	uow repositories.UnitOfWork,
+) *ProjectService {
 	return &ProjectService{
-		repo: repo,
+		repo:     repo,
+		taskRepo: taskRepo,
+		userRepo: userRepo,
+		teamRepo: teamRepo,
+		uow:      uow,
 	}
 }
 
@@ -30,60 +52,97 @@ func (s *ProjectService) CreateProject(
 	project *models.Project,
 ) (*models.Project, error) {
 
+	if err := s.prepareNewProject(ctx, project, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, project); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// prepareNewProject validates a project about to be inserted and fills in
+// its member list and creation time. Templates and clones go through it too.
+func (s *ProjectService) prepareNewProject(ctx context.Context, project *models.Project, now time.Time) error {
+	errs := &ValidationError{}
+
 	if project.Name == "" {
-		return nil, errors.New("name is required")
+		errs.add("name", "is required")
 	}
 
 	if project.OwnerID == primitive.NilObjectID {
-		return nil, errors.New("ownerId is required")
+		errs.add("ownerId", "is required")
+	} else if err := s.validateOwner(ctx, "ownerId", project.OwnerID, errs); err != nil {
+		return err
 	}
 
-	project.CreatedAt = time.Now()
+	normalizeMembers(project, now, errs)
 
-	if err := s.repo.Create(ctx, project); err != nil {
-		return nil, err
+	if err := s.validateMembers(ctx, project.MemberIDs, errs); err != nil {
+		return err
 	}
 
-	return project, nil
+	if err := errs.errOrNil(); err != nil {
+		return err
+	}
+
+	project.ID = primitive.NilObjectID
+	project.Teams = nil
+	project.CreatedAt = now
+	project.ArchivedAt, project.ArchivedBy = nil, nil
+	project.DeletedAt, project.DeletedBy = nil, nil
+	return nil
 }
 
 // =====================
 // READ
 // =====================
-func (s *ProjectService) GetAllProjects(ctx context.Context) ([]models.Project, error) {
-	return s.repo.FindAll(ctx)
+// Archived projects are left out of listings unless includeArchived is set.
+func (s *ProjectService) GetAllProjects(ctx context.Context, includeArchived bool) ([]models.Project, error) {
+	return s.repo.FindAll(ctx, includeArchived)
 }
 
-func (s *ProjectService) GetProjectByID(ctx context.Context, id string) (*models.Project, error) {
-	if id == "" {
-		return nil, errors.New("id is required")
-	}
+func (s *ProjectService) GetProjectByID(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+) (*dto.ProjectDetail, error) {
 
-	objID, err := primitive.ObjectIDFromHex(id)
+	project, err := s.loadProject(ctx, id)
 	if err != nil {
-		return nil, errors.New("invalid project id")
+		return nil, err
 	}
 
-	return s.repo.FindByID(ctx, objID)
+	if err := authorizeProject(currentUser, project, actionViewProject); err != nil {
+		return nil, err
+	}
+
+	return s.projectDetail(ctx, project)
 }
 
 func (s *ProjectService) GetProjectsByOwner(
 	ctx context.Context,
 	ownerID primitive.ObjectID,
 ) ([]models.Project, error) {
-	return s.repo.FindByOwnerID(ctx, ownerID)
+	return s.repo.FindByOwnerID(ctx, ownerID, false)
 }
 
 func (s *ProjectService) GetProjectsByMember(
 	ctx context.Context,
 	userID primitive.ObjectID,
 ) ([]models.Project, error) {
-	return s.repo.FindByMemberID(ctx, userID)
+	return s.repo.FindByMemberID(ctx, userID, false)
 }
 
+// GetProjectsByUser lists the projects a user owns, belongs to or reaches
+// through a team, leaving out those the caller cannot view.
 func (s *ProjectService) GetProjectsByUser(
 	ctx context.Context,
+	currentUser *models.User,
 	userIDStr string,
+	includeArchived bool,
 ) ([]models.Project, error) {
 
 	if userIDStr == "" {
@@ -95,47 +154,329 @@ func (s *ProjectService) GetProjectsByUser(
 		return nil, errors.New("invalid userId")
 	}
 
-	projectsByOwner, err := s.repo.FindByOwnerID(ctx, userID)
+	projectsByOwner, err := s.repo.FindByOwnerID(ctx, userID, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+
+	projectsByMember, err := s.repo.FindByMemberID(ctx, userID, includeArchived)
 	if err != nil {
 		return nil, err
 	}
 
-	projectsByMember, err := s.repo.FindByMemberID(ctx, userID)
+	projects := append(projectsByOwner, projectsByMember...)
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, err
+	}
+	if user == nil || len(user.TeamIDs) == 0 {
+		return visibleProjects(currentUser, projects), nil
+	}
+
+	// Projects reached through a team may also be owned or joined directly.
+	projectsByTeam, err := s.repo.FindByTeamIDs(ctx, user.TeamIDs, includeArchived)
 	if err != nil {
 		return nil, err
 	}
 
-	return append(projectsByOwner, projectsByMember...), nil
+	seen := make(map[primitive.ObjectID]bool, len(projects))
+	for _, p := range projects {
+		seen[p.ID] = true
+	}
+	for _, p := range projectsByTeam {
+		if !seen[p.ID] {
+			projects = append(projects, p)
+		}
+	}
+
+	return visibleProjects(currentUser, projects), nil
+}
+
+// visibleProjects keeps the projects user can view.
+func visibleProjects(user *models.User, projects []models.Project) []models.Project {
+	visible := projects[:0]
+	for i := range projects {
+		if canOnProject(user, &projects[i], actionViewProject) {
+			visible = append(visible, projects[i])
+		}
+	}
+	return visible
 }
 
 // =====================
 // UPDATE
 // =====================
-func (s *ProjectService) UpdateProject(ctx context.Context, id string, update bson.M) error {
-	if id == "" {
-		return errors.New("id is required")
+func (s *ProjectService) UpdateProject(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+	update bson.M,
+) error {
+
+	project, err := s.loadProject(ctx, id)
+	if err != nil {
+		return err
 	}
 
-	objID, err := primitive.ObjectIDFromHex(id)
+	if err := authorizeProject(currentUser, project, actionUpdateProject); err != nil {
+		return err
+	}
+
+	delete(update, "_id")
+	delete(update, "createdAt")
+	stripTrashFields(update)
+
+	errs := &ValidationError{}
+
+	if name, ok := update["name"]; ok && name == "" {
+		errs.add("name", "cannot be empty")
+	}
+
+	// Membership and ownership have their own endpoints so that concurrent
+	// edits are applied atomically instead of replacing the whole list.
+	if _, ok := update["ownerId"]; ok {
+		errs.add("ownerId", "use POST /projects/{id}/transfer-ownership")
+	}
+	if _, ok := update["memberIds"]; ok {
+		errs.add("memberIds", "use /projects/{id}/members")
+	}
+	if _, ok := update["members"]; ok {
+		errs.add("members", "use /projects/{id}/members")
+	}
+	if _, ok := update["teams"]; ok {
+		errs.add("teams", "use /projects/{id}/teams")
+	}
+	if _, ok := update["wipLimits"]; ok {
+		errs.add("wipLimits", "use PUT /projects/{id}/wip-limits")
+	}
+	for _, field := range []string{"archivedAt", "archivedBy"} {
+		if _, ok := update[field]; ok {
+			errs.add(field, "use POST /projects/{id}/archive or /projects/{id}/unarchive")
+		}
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return err
+	}
+
+	return s.repo.UpdateByID(ctx, project.ID, update)
+}
+
+// =====================
+// ARCHIVE
+// =====================
+
+// ArchiveProject makes the project and its tasks read-only and hides it
+// from default listings and the dashboard.
+func (s *ProjectService) ArchiveProject(ctx context.Context, currentUser *models.User, id string) error {
+	project, err := s.loadProject(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeProject(currentUser, project, actionArchiveProject); err != nil {
+		return err
+	}
+
+	archived, err := s.repo.Archive(ctx, project.ID, currentUser.ID)
 	if err != nil {
-		return errors.New("invalid project id")
+		return err
+	}
+	if !archived {
+		return ErrAlreadyArchived
+	}
+	return nil
+}
+
+func (s *ProjectService) UnarchiveProject(ctx context.Context, currentUser *models.User, id string) error {
+	project, err := s.loadProject(ctx, id)
+	if err != nil {
+		return err
 	}
 
-	return s.repo.UpdateByID(ctx, objID, update)
+	if err := authorizeProject(currentUser, project, actionArchiveProject); err != nil {
+		return err
+	}
+
+	unarchived, err := s.repo.Unarchive(ctx, project.ID)
+	if err != nil {
+		return err
+	}
+	if !unarchived {
+		return ErrNotArchived
+	}
+	return nil
 }
 
 // =====================
 // DELETE
 // =====================
-func (s *ProjectService) DeleteProject(ctx context.Context, id string) error {
+func (s *ProjectService) DeleteProject(ctx context.Context, currentUser *models.User, id string) error {
+	project, err := s.loadProject(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeProject(currentUser, project, actionDeleteProject); err != nil {
+		return err
+	}
+
+	// project → tasks: CASCADE
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteByID(ctx, project.ID, currentUser.ID); err != nil {
+			return err
+		}
+
+		_, err := s.taskRepo.DeleteByProjectID(ctx, project.ID, currentUser.ID)
+		return err
+	})
+}
+
+func (s *ProjectService) loadProject(ctx context.Context, id string) (*models.Project, error) {
 	if id == "" {
-		return errors.New("id is required")
+		return nil, errors.New("id is required")
 	}
 
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return errors.New("invalid project id")
+		return nil, errors.New("invalid project id")
+	}
+
+	return s.repo.FindByID(ctx, objID)
+}
+
+// =====================
+// REFERENTIAL INTEGRITY
+// =====================
+
+// validateOwner requires the owner to be an existing admin or super_admin.
+func (s *ProjectService) validateOwner(
+	ctx context.Context,
+	field string,
+	ownerID primitive.ObjectID,
+	errs *ValidationError,
+) error {
+
+	owner, err := s.userRepo.FindByID(ctx, ownerID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			errs.add(field, "user not found")
+			return nil
+		}
+		return err
+	}
+
+	if !isAdminCapable(owner) {
+		errs.add(field, "owner must be an admin or super_admin")
+	}
+
+	return nil
+}
+
+func (s *ProjectService) validateMembers(
+	ctx context.Context,
+	memberIDs []primitive.ObjectID,
+	errs *ValidationError,
+) error {
+
+	if len(memberIDs) == 0 {
+		return nil
+	}
+
+	users, err := s.userRepo.FindByIDs(ctx, memberIDs)
+	if err != nil {
+		return err
+	}
+
+	found := make(map[primitive.ObjectID]bool, len(users))
+	for _, u := range users {
+		found[u.ID] = true
+	}
+
+	for i, id := range memberIDs {
+		if !found[id] {
+			errs.add(fmt.Sprintf("memberIds[%d]", i), "user not found")
+		}
 	}
 
-	return s.repo.DeleteByID(ctx, objID)
+	return nil
+}
+
+func isProjectMember(project *models.Project, userID primitive.ObjectID) bool {
+	if project.OwnerID == userID {
+		return true
+	}
+	for _, id := range project.MemberIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// isProjectMemberUser also counts members of the project's teams.
+func isProjectMemberUser(project *models.Project, user *models.User) bool {
+	if isProjectMember(project, user.ID) {
+		return true
+	}
+	for _, t := range project.Teams {
+		if inTeam(user, t.TeamID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *ProjectService) countOwnedProjects(ctx context.Context, ownerID primitive.ObjectID) (int64, error) {
+	return s.repo.CountByOwnerID(ctx, ownerID)
+}
+
+func (s *ProjectService) transferOwnership(ctx context.Context, fromID, toID primitive.ObjectID) error {
+	_, err := s.repo.TransferOwnership(ctx, fromID, toID)
+	return err
+}
+
+// ownedProjects lists the projects a deactivated user hands over. Archived
+// projects are read-only, so they stay with the user.
+func (s *ProjectService) ownedProjects(ctx context.Context, ownerID primitive.ObjectID) ([]models.Project, error) {
+	return s.repo.FindByOwnerID(ctx, ownerID, false)
+}
+
+// handOver makes toID the owner of project; fromID stays on as maintainer.
+func (s *ProjectService) handOver(ctx context.Context, projectID, fromID, toID primitive.ObjectID) error {
+	changed, err := s.repo.ChangeOwner(ctx, projectID, fromID, toID)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return ErrOwnershipChanged
+	}
+	return nil
+}
+
+// joinAsContributor adds the user unless they already are a member.
+func (s *ProjectService) joinAsContributor(ctx context.Context, projectID, userID primitive.ObjectID) error {
+	_, err := s.repo.AddMember(ctx, projectID, models.ProjectMember{
+		UserID:  userID,
+		Role:    models.ProjectRoleContributor,
+		AddedAt: time.Now(),
+	})
+	return err
+}
+
+func (s *ProjectService) findProject(ctx context.Context, id primitive.ObjectID) (*models.Project, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+// user → project and team memberships: NULLIFY
+func (s *ProjectService) removeMemberEverywhere(ctx context.Context, userID primitive.ObjectID) error {
+	if _, err := s.repo.RemoveMemberFromAll(ctx, userID); err != nil {
+		return err
+	}
+	if _, err := s.teamRepo.RemoveUserFromAll(ctx, userID); err != nil {
+		return err
+	}
+	// A restored user must not regain access through teams they left.
+	return s.userRepo.UpdateByID(ctx, userID, bson.M{"teamIds": bson.A{}})
 }
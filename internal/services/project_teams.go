@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrTeamAlreadyAdded = errors.New("team is already part of this project")
+	ErrTeamNotInProject = errors.New("team is not part of this project")
+)
+
+// Teams are added to a project with one role shared by all of their
+// members. Managing them needs the same project role as managing members.
+
+func (s *ProjectService) AddTeam(
+	ctx context.Context,
+	currentUser *models.User,
+	projectID string,
+	teamID string,
+	role string,
+) error {
+
+	project, err := s.loadProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeProject(currentUser, project, actionManageMembers); err != nil {
+		return err
+	}
+
+	if role == "" {
+		role = models.ProjectRoleContributor
+	}
+
+	errs := &ValidationError{}
+
+	if !isValidMemberRole(role) {
+		errs.add("role", "must be one of viewer, contributor, maintainer")
+	}
+
+	teamObjID, err := primitive.ObjectIDFromHex(teamID)
+	if err != nil {
+		errs.add("teamId", "must be a valid ObjectID")
+	} else if _, err := s.teamRepo.FindByID(ctx, teamObjID); err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return err
+		}
+		errs.add("teamId", "team not found")
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return err
+	}
+
+	added, err := s.repo.AddTeam(ctx, project.ID, models.ProjectTeam{
+		TeamID:  teamObjID,
+		Role:    role,
+		AddedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	if !added {
+		return ErrTeamAlreadyAdded
+	}
+
+	return nil
+}
+
+func (s *ProjectService) UpdateTeamRole(
+	ctx context.Context,
+	currentUser *models.User,
+	projectID string,
+	teamID string,
+	role string,
+) error {
+
+	project, err := s.loadProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeProject(currentUser, project, actionManageMembers); err != nil {
+		return err
+	}
+
+	if !isValidMemberRole(role) {
+		errs := &ValidationError{}
+		errs.add("role", "must be one of viewer, contributor, maintainer")
+		return errs
+	}
+
+	teamObjID, err := primitive.ObjectIDFromHex(teamID)
+	if err != nil {
+		return errors.New("invalid team id")
+	}
+
+	updated, err := s.repo.UpdateTeamRole(ctx, project.ID, teamObjID, role)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrTeamNotInProject
+	}
+
+	return nil
+}
+
+// RemoveTeam also clears the team as assignee of the project's tasks. Tasks
+// assigned to individual team members keep their assignee.
+func (s *ProjectService) RemoveTeam(
+	ctx context.Context,
+	currentUser *models.User,
+	projectID string,
+	teamID string,
+) error {
+
+	project, err := s.loadProject(ctx, projectID)
+	if err != nil {
+		return err
+	}
+
+	if err := authorizeProject(currentUser, project, actionManageMembers); err != nil {
+		return err
+	}
+
+	teamObjID, err := primitive.ObjectIDFromHex(teamID)
+	if err != nil {
+		return errors.New("invalid team id")
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		removed, err := s.repo.RemoveTeam(ctx, project.ID, teamObjID)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return ErrTeamNotInProject
+		}
+
+		_, err = s.taskRepo.UnassignTeamInProject(ctx, project.ID, teamObjID)
+		return err
+	})
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const day = 24 * time.Hour
+
+// =====================
+// BURNDOWN
+// =====================
+
+// GetBurndown returns one point per day of the sprint (UTC days, start and
+// end date included). Each point replays the TaskEvents up to the end of
+// that day: scope is the number of tasks in the sprint, completed the ones
+// among them that are Done. Tasks carry no estimates, so work is counted
+// in tasks. Burnup charts plot scope and completed; burndown plots
+// remaining against ideal.
+func (s *SprintService) GetBurndown(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+) (*dto.BurndownResponse, error) {
+
+	sprint, err := s.authorizeSprint(ctx, currentUser, id, actionViewProject)
+	if err != nil {
+		return nil, err
+	}
+
+	// Days after asOf have no data yet; a closed sprint stops at its close.
+	asOf := time.Now()
+	if sprint.ClosedAt != nil {
+		asOf = *sprint.ClosedAt
+	}
+
+	first := sprint.StartDate.UTC().Truncate(day)
+	last := sprint.EndDate.UTC().Truncate(day)
+
+	timelines, err := s.sprintTimelines(ctx, sprint, last.Add(day))
+	if err != nil {
+		return nil, err
+	}
+
+	days := int(last.Sub(first)/day) + 1
+	points := make([]dto.BurndownPoint, 0, days)
+
+	for d := first; !d.After(last); d = d.Add(day) {
+		point := dto.BurndownPoint{Date: d.Format("2006-01-02")}
+
+		if !d.After(asOf) {
+			cutoff := d.Add(day)
+			if asOf.Before(cutoff) {
+				cutoff = asOf
+			}
+
+			scope, completed := 0, 0
+			for _, events := range timelines {
+				state := stateAt(events, cutoff)
+				if state == nil || state.Deleted || state.SprintID != sprint.ID {
+					continue
+				}
+				scope++
+				if state.Status == models.TaskStatusDone {
+					completed++
+				}
+			}
+			remaining := scope - completed
+
+			point.Scope = &scope
+			point.Completed = &completed
+			point.Remaining = &remaining
+		}
+
+		points = append(points, point)
+	}
+
+	// The ideal line runs from the remaining work at the end of the first
+	// day down to zero on the last.
+	if n := len(points); n > 1 && points[0].Remaining != nil {
+		initial := float64(*points[0].Remaining)
+		for i := range points {
+			ideal := initial * (1 - float64(i)/float64(n-1))
+			points[i].Ideal = math.Round(ideal*100) / 100
+		}
+	}
+
+	return &dto.BurndownResponse{
+		SprintID:  sprint.ID.Hex(),
+		Name:      sprint.Name,
+		State:     sprint.State,
+		StartDate: sprint.StartDate,
+		EndDate:   sprint.EndDate,
+		Unit:      "tasks",
+		Points:    points,
+	}, nil
+}
+
+// sprintTimelines returns, per task that has ever been in the sprint, its
+// events up to until in time order. Tasks in the sprint with no history
+// (written before history was kept) get a single event at their creation
+// time reflecting their current state.
+func (s *SprintService) sprintTimelines(
+	ctx context.Context,
+	sprint *models.Sprint,
+	until time.Time,
+) (map[primitive.ObjectID][]models.TaskEvent, error) {
+
+	ids, err := s.events.FindTaskIDsBySprint(ctx, sprint.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	timelines := make(map[primitive.ObjectID][]models.TaskEvent, len(ids))
+
+	if len(ids) > 0 {
+		events, err := s.events.FindByTaskIDs(ctx, ids, until)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range events {
+			timelines[e.TaskID] = append(timelines[e.TaskID], e)
+		}
+	}
+
+	current, err := s.taskRepo.FindBySprintID(ctx, sprint.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range current {
+		if _, ok := timelines[t.ID]; ok {
+			continue
+		}
+		timelines[t.ID] = []models.TaskEvent{{
+			TaskID:    t.ID,
+			ProjectID: t.ProjectID,
+			SprintID:  t.SprintID,
+			Status:    t.Status,
+			At:        t.CreatedAt,
+		}}
+	}
+
+	return timelines, nil
+}
+
+// stateAt returns the last event strictly before cutoff, or nil if the task
+// did not exist yet.
+func stateAt(events []models.TaskEvent, cutoff time.Time) *models.TaskEvent {
+	var state *models.TaskEvent
+	for i := range events {
+		if !events[i].At.Before(cutoff) {
+			break
+		}
+		state = &events[i]
+	}
+	return state
+}
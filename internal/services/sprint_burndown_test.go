@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestStateAt(t *testing.T) {
+	t0 := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	events := []models.TaskEvent{
+		{Status: models.TaskStatusTodo, At: t0},
+		{Status: models.TaskStatusDone, At: t0.Add(time.Hour)},
+	}
+
+	tests := []struct {
+		name   string
+		cutoff time.Time
+		want   string
+	}{
+		{"before the first event", t0, ""},
+		{"between events", t0.Add(time.Minute), models.TaskStatusTodo},
+		{"at an event", t0.Add(time.Hour), models.TaskStatusTodo},
+		{"after the last event", t0.Add(2 * time.Hour), models.TaskStatusDone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			if state := stateAt(events, tt.cutoff); state != nil {
+				got = state.Status
+			}
+			if got != tt.want {
+				t.Errorf("stateAt = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestGetBurndown replays a four-day sprint closed at noon on its third
+// day:
+//
+//	a: in the sprint from the start, done on day 2
+//	b: added on day 1, taken out of the sprint on day 3
+//	c: no history, created before the sprint
+//	d: added on day 2, deleted on day 3
+//	e: in the sprint from the start, done after the sprint was closed
+func TestGetBurndown(t *testing.T) {
+	user := &models.User{ID: primitive.NewObjectID(), Role: models.RoleSuperAdmin}
+	project := &models.Project{ID: primitive.NewObjectID(), OwnerID: user.ID}
+
+	day1 := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d int, hour int) time.Time {
+		return day1.Add(time.Duration(d-1)*day + time.Duration(hour)*time.Hour)
+	}
+
+	closedAt := at(3, 12)
+	sprint := &models.Sprint{
+		ID:        primitive.NewObjectID(),
+		ProjectID: project.ID,
+		Name:      "Sprint 1",
+		StartDate: at(1, 8),
+		EndDate:   at(4, 18),
+		State:     models.SprintStateClosed,
+		ClosedAt:  &closedAt,
+	}
+
+	a, b, c, d, e := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	event := func(task primitive.ObjectID, at time.Time, sprintID primitive.ObjectID, status string) models.TaskEvent {
+		return models.TaskEvent{TaskID: task, ProjectID: project.ID, SprintID: sprintID, Status: status, At: at}
+	}
+	events := &fakeTaskEventRepository{events: []models.TaskEvent{
+		event(e, at(1, 8), sprint.ID, models.TaskStatusTodo),
+		event(a, at(1, 9), sprint.ID, models.TaskStatusTodo),
+		event(b, at(1, 10), sprint.ID, models.TaskStatusTodo),
+		event(a, at(2, 10), sprint.ID, models.TaskStatusDone),
+		event(d, at(2, 11), sprint.ID, models.TaskStatusTodo),
+		event(b, at(3, 8), primitive.NilObjectID, models.TaskStatusTodo),
+		{TaskID: d, ProjectID: project.ID, SprintID: sprint.ID, Status: models.TaskStatusTodo, Deleted: true, At: at(3, 9)},
+		event(e, at(3, 13), sprint.ID, models.TaskStatusDone),
+	}}
+
+	tasks := &fakeTaskRepository{tasks: []*models.Task{
+		{ID: a, ProjectID: project.ID, SprintID: sprint.ID, Status: models.TaskStatusDone},
+		{ID: c, ProjectID: project.ID, SprintID: sprint.ID, Status: models.TaskStatusTodo, CreatedAt: at(0, 12)},
+		{ID: e, ProjectID: project.ID, SprintID: sprint.ID, Status: models.TaskStatusDone},
+	}}
+
+	service := NewSprintService(
+		&fakeSprintRepository{sprints: []*models.Sprint{sprint}},
+		&fakeProjectRepository{projects: []*models.Project{project}},
+		tasks,
+		events,
+		fakeUnitOfWork{},
+	)
+
+	got, err := service.GetBurndown(context.Background(), user, sprint.ID.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	n := func(v int) *int { return &v }
+	want := []dto.BurndownPoint{
+		{Date: "2026-10-01", Scope: n(4), Completed: n(0), Remaining: n(4), Ideal: 4},
+		{Date: "2026-10-02", Scope: n(5), Completed: n(1), Remaining: n(4), Ideal: 2.67},
+		{Date: "2026-10-03", Scope: n(3), Completed: n(1), Remaining: n(2), Ideal: 1.33},
+		{Date: "2026-10-04", Ideal: 0},
+	}
+	if !reflect.DeepEqual(got.Points, want) {
+		t.Errorf("points =\n%s\nwant\n%s", formatPoints(got.Points), formatPoints(want))
+	}
+	if got.Unit != "tasks" {
+		t.Errorf("unit = %q, want tasks", got.Unit)
+	}
+}
+
+func formatPoints(points []dto.BurndownPoint) string {
+	v := func(p *int) interface{} {
+		if p == nil {
+			return "-"
+		}
+		return *p
+	}
+
+	s := ""
+	for _, p := range points {
+		s += fmt.Sprintf("  %s scope=%v completed=%v remaining=%v ideal=%v\n", p.Date, v(p.Scope), v(p.Completed), v(p.Remaining), p.Ideal)
+	}
+	return s
+}
@@ -0,0 +1,381 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrSprintNotPlanned    = errors.New("only a planned sprint can be started")
+	ErrSprintNotActive     = errors.New("only an active sprint can be closed")
+	ErrSprintAlreadyActive = errors.New("the project already has an active sprint")
+	ErrSprintClosed        = errors.New("a closed sprint cannot be edited")
+)
+
+type SprintService struct {
+	repo        repositories.SprintRepository
+	projectRepo repositories.ProjectRepository
+	taskRepo    repositories.TaskRepository
+	events      repositories.TaskEventRepository
+	uow         repositories.UnitOfWork
+}
+
+func NewSprintService(
+	repo repositories.SprintRepository,
+	projectRepo repositories.ProjectRepository,
+	taskRepo repositories.TaskRepository,
+	events repositories.TaskEventRepository,
+	uow repositories.UnitOfWork,
+) *SprintService {
+	return &SprintService{
+		repo:        repo,
+		projectRepo: projectRepo,
+		taskRepo:    taskRepo,
+		events:      events,
+		uow:         uow,
+	}
+}
+
+// =====================
+// CREATE
+// =====================
+func (s *SprintService) CreateSprint(
+	ctx context.Context,
+	currentUser *models.User,
+	projectID string,
+	sprint *models.Sprint,
+) (*models.Sprint, error) {
+
+	project, err := s.loadProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeProject(currentUser, project, actionManagePlanning); err != nil {
+		return nil, err
+	}
+
+	errs := &ValidationError{}
+
+	if sprint.Name == "" {
+		errs.add("name", "is required")
+	}
+	if sprint.StartDate.IsZero() {
+		errs.add("startDate", "is required")
+	}
+	if sprint.EndDate.IsZero() {
+		errs.add("endDate", "is required")
+	}
+	if !sprint.StartDate.IsZero() && !sprint.EndDate.IsZero() && sprint.EndDate.Before(sprint.StartDate) {
+		errs.add("endDate", "cannot be before startDate")
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	sprint.ID = primitive.NilObjectID
+	sprint.ProjectID = project.ID
+	sprint.State = models.SprintStatePlanned
+	sprint.CreatedAt = time.Now()
+	sprint.StartedAt = nil
+	sprint.ClosedAt = nil
+	sprint.CarriedOver = 0
+	sprint.CarriedOverTo = nil
+
+	if err := s.repo.Create(ctx, sprint); err != nil {
+		return nil, err
+	}
+
+	return sprint, nil
+}
+
+// =====================
+// READ
+// =====================
+func (s *SprintService) GetSprints(
+	ctx context.Context,
+	currentUser *models.User,
+	projectID string,
+) ([]models.Sprint, error) {
+
+	project, err := s.loadProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeProject(currentUser, project, actionViewProject); err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByProjectID(ctx, project.ID)
+}
+
+func (s *SprintService) GetSprint(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+) (*dto.SprintDetail, error) {
+
+	sprint, err := s.authorizeSprint(ctx, currentUser, id, actionViewProject)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.taskRepo.FindBySprintID(ctx, sprint.ID)
+	if err != nil {
+		return nil, err
+	}
+	if tasks == nil {
+		tasks = []models.Task{}
+	}
+
+	return &dto.SprintDetail{Sprint: sprint, Tasks: tasks}, nil
+}
+
+// =====================
+// UPDATE
+// =====================
+func (s *SprintService) UpdateSprint(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+	update bson.M,
+) error {
+
+	sprint, err := s.authorizeSprint(ctx, currentUser, id, actionManagePlanning)
+	if err != nil {
+		return err
+	}
+
+	if sprint.State == models.SprintStateClosed {
+		return ErrSprintClosed
+	}
+
+	delete(update, "_id")
+	delete(update, "projectId")
+	delete(update, "createdAt")
+
+	errs := &ValidationError{}
+
+	if name, ok := update["name"]; ok && name == "" {
+		errs.add("name", "cannot be empty")
+	}
+
+	// State and the close summary only change through start and close.
+	for _, field := range []string{"state", "startedAt", "closedAt", "carriedOver", "carriedOverTo"} {
+		if _, ok := update[field]; ok {
+			errs.add(field, "use POST /sprints/{id}/start or /sprints/{id}/close")
+		}
+	}
+
+	start, end := sprint.StartDate, sprint.EndDate
+	if t, ok := timeField(update, "startDate", errs); ok {
+		start = t
+	}
+	if t, ok := timeField(update, "endDate", errs); ok {
+		end = t
+	}
+	if end.Before(start) {
+		errs.add("endDate", "cannot be before startDate")
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return err
+	}
+
+	return s.repo.UpdateByID(ctx, sprint.ID, update)
+}
+
+// =====================
+// START / CLOSE
+// =====================
+func (s *SprintService) StartSprint(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+) (*models.Sprint, error) {
+
+	sprint, err := s.authorizeSprint(ctx, currentUser, id, actionManagePlanning)
+	if err != nil {
+		return nil, err
+	}
+
+	started, err := s.repo.Start(ctx, sprint.ID, time.Now())
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrSprintAlreadyActive
+		}
+		return nil, err
+	}
+	if !started {
+		return nil, ErrSprintNotPlanned
+	}
+
+	return s.repo.FindByID(ctx, sprint.ID)
+}
+
+// CloseSprint closes an active sprint. Tasks that are not Done are carried
+// over to carryOverTo, another open sprint of the same project, or back to
+// the backlog when it is empty.
+func (s *SprintService) CloseSprint(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+	carryOverTo string,
+) (*models.Sprint, error) {
+
+	sprint, err := s.authorizeSprint(ctx, currentUser, id, actionManagePlanning)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := s.carryOverTarget(ctx, sprint, carryOverTo)
+	if err != nil {
+		return nil, err
+	}
+
+	targetID := primitive.NilObjectID
+	if target != nil {
+		targetID = *target
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		unfinished, err := s.taskRepo.FindUnfinishedInSprint(ctx, sprint.ID)
+		if err != nil {
+			return err
+		}
+
+		closed, err := s.repo.Close(ctx, sprint.ID, time.Now(), len(unfinished), target)
+		if err != nil {
+			return err
+		}
+		if !closed {
+			return ErrSprintNotActive
+		}
+
+		if len(unfinished) == 0 {
+			return nil
+		}
+
+		ids := make([]primitive.ObjectID, len(unfinished))
+		for i, t := range unfinished {
+			ids[i] = t.ID
+		}
+
+		if _, err := s.taskRepo.SetSprint(ctx, ids, targetID); err != nil {
+			return err
+		}
+
+		for i := range unfinished {
+			unfinished[i].SprintID = targetID
+			if err := recordTaskEvent(ctx, s.events, &unfinished[i], false); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.FindByID(ctx, sprint.ID)
+}
+
+func (s *SprintService) carryOverTarget(
+	ctx context.Context,
+	sprint *models.Sprint,
+	carryOverTo string,
+) (*primitive.ObjectID, error) {
+
+	if carryOverTo == "" {
+		return nil, nil
+	}
+
+	errs := &ValidationError{}
+
+	targetID, err := primitive.ObjectIDFromHex(carryOverTo)
+	if err != nil {
+		errs.add("carryOverTo", "must be a valid ObjectID")
+		return nil, errs
+	}
+
+	if targetID == sprint.ID {
+		errs.add("carryOverTo", "cannot be the sprint being closed")
+		return nil, errs
+	}
+
+	target, err := s.repo.FindByID(ctx, targetID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			errs.add("carryOverTo", "sprint not found")
+			return nil, errs
+		}
+		return nil, err
+	}
+
+	switch {
+	case target.ProjectID != sprint.ProjectID:
+		errs.add("carryOverTo", "sprint belongs to another project")
+	case target.State == models.SprintStateClosed:
+		errs.add("carryOverTo", "sprint is closed")
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	return &targetID, nil
+}
+
+// =====================
+// HELPERS
+// =====================
+func (s *SprintService) loadProject(ctx context.Context, id string) (*models.Project, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("invalid project id")
+	}
+
+	return s.projectRepo.FindByID(ctx, objID)
+}
+
+// authorizeSprint loads the sprint and checks the caller's role in its project.
+func (s *SprintService) authorizeSprint(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+	action projectAction,
+) (*models.Sprint, error) {
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("invalid sprint id")
+	}
+
+	sprint, err := s.repo.FindByID(ctx, objID)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.projectRepo.FindByID(ctx, sprint.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeProject(currentUser, project, action); err != nil {
+		return nil, err
+	}
+
+	return sprint, nil
+}
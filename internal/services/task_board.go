@@ -0,0 +1,315 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var ErrStaleNeighbors = errors.New("neighbor tasks are out of order, reload the board and retry")
+
+// =====================
+// BOARD
+// =====================
+func (s *TaskService) GetBoard(
+	ctx context.Context,
+	currentUser *models.User,
+	projectID primitive.ObjectID,
+) (*dto.BoardResponse, error) {
+
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeProject(currentUser, project, actionViewProject); err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.repo.FindBoard(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	utilization, err := wipUtilization(ctx, s.repo, project)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string][]models.Task, len(models.TaskStatuses))
+	for _, task := range tasks {
+		columns[task.Status] = append(columns[task.Status], task)
+	}
+
+	board := &dto.BoardResponse{
+		ProjectID:   project.ID.Hex(),
+		ProjectName: project.Name,
+	}
+
+	for i, status := range models.TaskStatuses {
+		column := dto.BoardColumn{Status: status, WIP: utilization[i], Tasks: columns[status]}
+		if column.Tasks == nil {
+			column.Tasks = []models.Task{}
+		}
+		board.Columns = append(board.Columns, column)
+	}
+
+	return board, nil
+}
+
+// =====================
+// MOVE
+// =====================
+
+// MoveTask places a task in the status column between prevID (the card
+// above) and nextID (the card below). Either neighbor may be omitted; with
+// neither the task goes to the bottom of the column. Only the moved task is
+// written, unless the column first has to be reranked (see
+// ensureColumnRanked).
+func (s *TaskService) MoveTask(
+	ctx context.Context,
+	currentUser *models.User,
+	id primitive.ObjectID,
+	status string,
+	prevID string,
+	nextID string,
+) (*models.Task, error) {
+
+	task, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := s.authorizeTask(ctx, currentUser, task, actionUpdateTask)
+	if err != nil {
+		return nil, err
+	}
+
+	if status == "" {
+		status = task.Status
+	}
+
+	errs := &ValidationError{}
+
+	if !validTaskStatuses[status] {
+		errs.add("status", "must be one of Todo, In Progress, Done")
+	}
+
+	prevObjID := s.parseNeighborID(prevID, "prevTaskId", id, errs)
+	nextObjID := s.parseNeighborID(nextID, "nextTaskId", id, errs)
+
+	if err := errs.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	reranked, err := s.ensureColumnRanked(ctx, task.ProjectID, status)
+	if err != nil {
+		return nil, err
+	}
+	if reranked && status == task.Status {
+		// The task's own rank changed with the rest of its column.
+		if task, err = s.repo.FindByID(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
+	prev, err := s.loadNeighbor(ctx, prevObjID, "prevTaskId", task.ProjectID, status, errs)
+	if err != nil {
+		return nil, err
+	}
+	next, err := s.loadNeighbor(ctx, nextObjID, "nextTaskId", task.ProjectID, status, errs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	// Fill in whichever neighbor the client left out, skipping the task
+	// itself when it is moving within its own column.
+	switch {
+	case prev == nil && next == nil:
+		prev, err = s.repo.FindLastInColumn(ctx, task.ProjectID, status)
+		if err == nil && prev != nil && prev.ID == task.ID {
+			prev, err = s.repo.FindPrevInColumn(ctx, task.ProjectID, status, task.Rank)
+		}
+	case next == nil:
+		next, err = s.repo.FindNextInColumn(ctx, task.ProjectID, status, prev.Rank)
+		if err == nil && next != nil && next.ID == task.ID {
+			next, err = s.repo.FindNextInColumn(ctx, task.ProjectID, status, task.Rank)
+		}
+	case prev == nil:
+		prev, err = s.repo.FindPrevInColumn(ctx, task.ProjectID, status, next.Rank)
+		if err == nil && prev != nil && prev.ID == task.ID {
+			prev, err = s.repo.FindPrevInColumn(ctx, task.ProjectID, status, task.Rank)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	prevRank, nextRank := "", ""
+	if prev != nil {
+		prevRank = prev.Rank
+	}
+	if next != nil {
+		nextRank = next.Rank
+	}
+
+	if nextRank != "" && prevRank >= nextRank {
+		return nil, ErrStaleNeighbors
+	}
+
+	check := wipCheck{
+		ProjectID:    task.ProjectID,
+		Status:       status,
+		Assignee:     task.AssignedTo,
+		EntersColumn: status != task.Status,
+	}
+	track := task.SprintID != primitive.NilObjectID && status != task.Status
+
+	now := time.Now()
+	task.Status = status
+	task.Rank = utils.RankBetween(prevRank, nextRank)
+	task.UpdatedAt = now
+
+	err = s.withinWIPLimits(ctx, project, check, func(ctx context.Context) error {
+		return s.writeTracked(ctx, task.ID, track, func(ctx context.Context) error {
+			return s.repo.UpdateByID(ctx, task.ID, bson.M{
+				"status":    task.Status,
+				"rank":      task.Rank,
+				"updatedAt": now,
+			})
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+func (s *TaskService) parseNeighborID(
+	value string,
+	field string,
+	taskID primitive.ObjectID,
+	errs *ValidationError,
+) primitive.ObjectID {
+
+	if value == "" {
+		return primitive.NilObjectID
+	}
+
+	id, err := primitive.ObjectIDFromHex(value)
+	if err != nil {
+		errs.add(field, "must be a valid ObjectID")
+		return primitive.NilObjectID
+	}
+
+	if id == taskID {
+		errs.add(field, "cannot be the task being moved")
+		return primitive.NilObjectID
+	}
+
+	return id
+}
+
+func (s *TaskService) loadNeighbor(
+	ctx context.Context,
+	id primitive.ObjectID,
+	field string,
+	projectID primitive.ObjectID,
+	status string,
+	errs *ValidationError,
+) (*models.Task, error) {
+
+	if id == primitive.NilObjectID {
+		return nil, nil
+	}
+
+	neighbor, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			errs.add(field, "task not found")
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if neighbor.ProjectID != projectID || neighbor.Status != status {
+		errs.add(field, "task is not in the target column")
+		return nil, nil
+	}
+
+	return neighbor, nil
+}
+
+// ensureColumnRanked reranks a whole column, keeping its current order, if
+// any task in it was created before ranks existed or shares its rank with
+// another. Ties come from tasks appended to the bottom of the same column
+// at the same time; no rank fits between two equal ones, so the column has
+// to be spread out again before anything can be dropped next to them. It
+// reports whether the column was rewritten.
+func (s *TaskService) ensureColumnRanked(ctx context.Context, projectID primitive.ObjectID, status string) (bool, error) {
+	tasks, err := s.repo.FindColumn(ctx, projectID, status)
+	if err != nil {
+		return false, err
+	}
+
+	if !needsRerank(tasks) {
+		return false, nil
+	}
+
+	ranks := utils.EvenRanks(len(tasks))
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		for i, t := range tasks {
+			if err := s.repo.UpdateByID(ctx, t.ID, bson.M{"rank": ranks[i]}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// needsRerank reports whether a column, in board order, has an unranked
+// task or two tasks with the same rank.
+func needsRerank(tasks []models.Task) bool {
+	for i, t := range tasks {
+		if t.Rank == "" {
+			return true
+		}
+		if i > 0 && tasks[i-1].Rank == t.Rank {
+			return true
+		}
+	}
+	return false
+}
+
+// rankAtBottom returns a rank that places a task below every task in the column.
+func (s *TaskService) rankAtBottom(ctx context.Context, projectID primitive.ObjectID, status string) (string, error) {
+	last, err := s.repo.FindLastInColumn(ctx, projectID, status)
+	if err != nil {
+		return "", err
+	}
+
+	if last == nil {
+		return utils.RankBetween("", ""), nil
+	}
+
+	return utils.RankBetween(last.Rank, ""), nil
+}
@@ -0,0 +1,580 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/repositories"
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	BulkModeAtomic     = "atomic"
+	BulkModeBestEffort = "best_effort"
+
+	maxBulkTasks = 500
+)
+
+var (
+	ErrBulkRejected = errors.New("bulk operation rejected; no task was changed")
+	ErrTasksChanged = errors.New("tasks changed while the bulk operation ran, reload and retry")
+)
+
+const (
+	bulkOpUpdate = "update"
+	bulkOpMove   = "move"
+	bulkOpDelete = "delete"
+)
+
+// bulkItem is one operation of a bulk request on its way to the database.
+type bulkItem struct {
+	result *dto.BulkTaskResult
+	id     primitive.ObjectID
+	patch  bson.M
+	task   *models.Task
+	update *taskUpdate // for update and move
+	err    error
+}
+
+func (item *bulkItem) fail(err error) {
+	item.err = err
+	item.result.Status = dto.BulkResultFailed
+	item.result.Error = err.Error()
+}
+
+// =====================
+// BULK
+// =====================
+// BulkTasks applies many task changes at once. Every item is validated and
+// authorized exactly like its single-task endpoint. In atomic mode one
+// failing item rejects the whole request; in best_effort mode failing items
+// are skipped. The writes themselves go out as a single BulkWrite, in a
+// transaction together with the WIP limit checks and sprint history.
+func (s *TaskService) BulkTasks(
+	ctx context.Context,
+	currentUser *models.User,
+	req *dto.BulkTaskRequest,
+) (*dto.BulkTaskResponse, error) {
+
+	if req.Mode == "" {
+		req.Mode = BulkModeAtomic
+	}
+
+	items, err := s.bulkItems(ctx, currentUser, req)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[primitive.ObjectID]bool, len(items))
+	for _, item := range items {
+		if item.err != nil {
+			continue
+		}
+		if seen[item.id] {
+			item.fail(errors.New("task appears more than once in this request"))
+			continue
+		}
+		seen[item.id] = true
+
+		if err := s.prepareBulkItem(ctx, currentUser, item); err != nil {
+			item.fail(err)
+		}
+	}
+
+	resp := &dto.BulkTaskResponse{Mode: req.Mode, Results: make([]dto.BulkTaskResult, 0, len(items))}
+
+	if req.Mode == BulkModeAtomic && countFailed(items) > 0 {
+		return finishBulk(resp, items, false), ErrBulkRejected
+	}
+
+	var wipRejected map[*bulkItem]error
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		// The callback may be retried, so it starts from scratch.
+		wipRejected = make(map[*bulkItem]error)
+		return s.writeBulk(ctx, currentUser, items, req.Mode, wipRejected)
+	})
+
+	for item, err := range wipRejected {
+		item.fail(err)
+	}
+
+	if errors.Is(err, ErrBulkRejected) {
+		return finishBulk(resp, items, false), ErrBulkRejected
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return finishBulk(resp, items, true), nil
+}
+
+// bulkItems turns the request into items, failing the ones that are
+// malformed. A malformed request as a whole is a ValidationError.
+func (s *TaskService) bulkItems(
+	ctx context.Context,
+	currentUser *models.User,
+	req *dto.BulkTaskRequest,
+) ([]*bulkItem, error) {
+
+	errs := &ValidationError{}
+
+	if req.Mode != BulkModeAtomic && req.Mode != BulkModeBestEffort {
+		errs.add("mode", "must be one of atomic, best_effort")
+	}
+
+	switch {
+	case req.Filter != nil && len(req.Operations) > 0:
+		errs.add("operations", "cannot be combined with filter")
+	case req.Filter == nil && len(req.Operations) == 0:
+		errs.add("operations", "operations or filter is required")
+	case len(req.Operations) > maxBulkTasks:
+		errs.add("operations", fmt.Sprintf("at most %d operations are allowed", maxBulkTasks))
+	case req.Filter != nil && len(req.Patch) == 0:
+		errs.add("patch", "is required with filter")
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	if req.Filter != nil {
+		return s.bulkItemsFromFilter(ctx, currentUser, req.Filter, req.Patch)
+	}
+
+	items := make([]*bulkItem, 0, len(req.Operations))
+
+	for i, op := range req.Operations {
+		item := &bulkItem{result: &dto.BulkTaskResult{Index: i, Op: op.Op, ID: op.ID}}
+		items = append(items, item)
+
+		id, err := primitive.ObjectIDFromHex(op.ID)
+		if err != nil {
+			item.fail(errors.New("invalid task id"))
+			continue
+		}
+		item.id = id
+
+		switch op.Op {
+		case bulkOpUpdate:
+			if len(op.Patch) == 0 {
+				item.fail(errors.New("patch is required for update"))
+				continue
+			}
+			item.patch = op.Patch
+		case bulkOpMove:
+			item.patch = bson.M{}
+			if op.Status != "" {
+				item.patch["status"] = op.Status
+			}
+			if op.ProjectID != "" {
+				item.patch["projectId"] = op.ProjectID
+			}
+			if len(item.patch) == 0 {
+				item.fail(errors.New("status or projectId is required for move"))
+			}
+		case bulkOpDelete:
+		default:
+			item.fail(errors.New("op must be one of update, move, delete"))
+		}
+	}
+
+	return items, nil
+}
+
+func (s *TaskService) bulkItemsFromFilter(
+	ctx context.Context,
+	currentUser *models.User,
+	filter *dto.BulkTaskFilter,
+	patch bson.M,
+) ([]*bulkItem, error) {
+
+	errs := &ValidationError{}
+
+	match := bson.M{}
+	ids := []struct{ field, value string }{
+		{"projectId", filter.ProjectID},
+		{"assignedTo", filter.AssignedTo},
+		{"assignedTeamId", filter.AssignedTeamID},
+		{"sprintId", filter.SprintID},
+	}
+	for _, f := range ids {
+		if f.value == "" {
+			continue
+		}
+		id, err := primitive.ObjectIDFromHex(f.value)
+		if err != nil {
+			errs.add("filter."+f.field, "must be a valid ObjectID")
+			continue
+		}
+		match[f.field] = id
+	}
+
+	if filter.ProjectID == "" {
+		errs.add("filter.projectId", "is required")
+	}
+	if filter.Status != "" {
+		if !validTaskStatuses[filter.Status] {
+			errs.add("filter.status", "must be one of Todo, In Progress, Done")
+		}
+		match["status"] = filter.Status
+	}
+	if filter.Priority != "" {
+		match["priority"] = filter.Priority
+	}
+	if filter.Label != "" {
+		match["labels"] = filter.Label
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	projectID := match["projectId"].(primitive.ObjectID)
+	delete(match, "projectId")
+
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeProject(currentUser, project, actionViewProject); err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.repo.FindInProjectMatching(ctx, projectID, match, maxBulkTasks+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) > maxBulkTasks {
+		errs.add("filter", fmt.Sprintf("matches more than %d tasks; narrow it down", maxBulkTasks))
+		return nil, errs
+	}
+
+	items := make([]*bulkItem, 0, len(tasks))
+	for i := range tasks {
+		// prepareUpdate normalizes the patch in place, so each task gets
+		// its own copy.
+		own := make(bson.M, len(patch))
+		for k, v := range patch {
+			own[k] = v
+		}
+
+		items = append(items, &bulkItem{
+			result: &dto.BulkTaskResult{Index: i, Op: bulkOpUpdate, ID: tasks[i].ID.Hex()},
+			id:     tasks[i].ID,
+			patch:  own,
+			task:   &tasks[i],
+		})
+	}
+
+	return items, nil
+}
+
+// prepareBulkItem loads the task and runs the checks of its single-task
+// endpoint, without writing.
+func (s *TaskService) prepareBulkItem(ctx context.Context, currentUser *models.User, item *bulkItem) error {
+	if item.task == nil {
+		task, err := s.repo.FindByID(ctx, item.id)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return errors.New("task not found")
+			}
+			return err
+		}
+		item.task = task
+	}
+
+	if item.result.Op == bulkOpDelete {
+		_, err := s.authorizeTask(ctx, currentUser, item.task, actionDeleteTask)
+		return err
+	}
+
+	update, err := s.prepareUpdate(ctx, currentUser, item.task, item.patch)
+	if err != nil {
+		return err
+	}
+	item.update = update
+	return nil
+}
+
+// writeBulk runs inside the transaction. Items over a WIP limit are
+// collected in rejected; in atomic mode the first one aborts everything.
+func (s *TaskService) writeBulk(
+	ctx context.Context,
+	currentUser *models.User,
+	items []*bulkItem,
+	mode string,
+	rejected map[*bulkItem]error,
+) error {
+
+	wip := newBulkWIP(s)
+	bottoms := make(map[columnKey]string)
+
+	writes := make([]repositories.TaskWrite, 0, len(items))
+	written := make([]*bulkItem, 0, len(items))
+
+	for _, item := range items {
+		if item.err != nil {
+			continue
+		}
+
+		if item.update == nil {
+			wip.leave(item.task)
+			writes = append(writes, repositories.TaskWrite{
+				ID:        item.id,
+				UpdatedAt: item.task.UpdatedAt,
+				Delete:    true,
+				DeletedBy: currentUser.ID,
+			})
+			written = append(written, item)
+			continue
+		}
+
+		if err := wip.admit(ctx, item.update.project, item.task, item.update.check); err != nil {
+			var limitErr *WIPLimitError
+			if !errors.As(err, &limitErr) {
+				return err
+			}
+			rejected[item] = err
+			if mode == BulkModeAtomic {
+				return ErrBulkRejected
+			}
+			continue
+		}
+
+		if item.update.rerank {
+			key := columnKey{item.update.check.ProjectID, item.update.check.Status}
+			rank, ok := bottoms[key]
+			if ok {
+				rank = utils.RankBetween(rank, "")
+			} else {
+				var err error
+				rank, err = s.rankAtBottom(ctx, key.projectID, key.status)
+				if err != nil {
+					return err
+				}
+			}
+			bottoms[key] = rank
+			item.update.set["rank"] = rank
+		}
+
+		writes = append(writes, repositories.TaskWrite{
+			ID:        item.id,
+			UpdatedAt: item.task.UpdatedAt,
+			Set:       item.update.set,
+		})
+		written = append(written, item)
+	}
+
+	// The WIP deltas above start from the tasks as read before the
+	// transaction, so they only hold if no task changed since then.
+	matched, err := s.repo.BulkWrite(ctx, writes)
+	if err != nil {
+		return err
+	}
+	if matched != int64(len(writes)) {
+		return ErrTasksChanged
+	}
+
+	for _, item := range written {
+		switch {
+		case item.update == nil && item.task.SprintID != primitive.NilObjectID:
+			if err := recordTaskEvent(ctx, s.events, item.task, true); err != nil {
+				return err
+			}
+		case item.update != nil && item.update.track:
+			task, err := s.repo.FindByID(ctx, item.id)
+			if err != nil {
+				return err
+			}
+			if err := recordTaskEvent(ctx, s.events, task, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func countFailed(items []*bulkItem) int {
+	failed := 0
+	for _, item := range items {
+		if item.err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+func finishBulk(resp *dto.BulkTaskResponse, items []*bulkItem, applied bool) *dto.BulkTaskResponse {
+	resp.Applied = applied
+	for _, item := range items {
+		switch {
+		case item.err != nil:
+			resp.Failed++
+		case applied:
+			item.result.Status = dto.BulkResultOK
+			resp.Succeeded++
+		default:
+			item.result.Status = dto.BulkResultNotApplied
+		}
+		resp.Results = append(resp.Results, *item.result)
+	}
+	return resp
+}
+
+// =====================
+// WIP LIMITS
+// =====================
+
+type columnKey struct {
+	projectID primitive.ObjectID
+	status    string
+}
+
+type assigneeKey struct {
+	column   columnKey
+	assignee primitive.ObjectID
+}
+
+// bulkWIP applies withinWIPLimits to a batch: a task entering a column
+// counts against the tasks already there plus those moved in earlier in
+// the same batch, minus those moved out. Projects with a limit that applies
+// are locked, as for single writes.
+type bulkWIP struct {
+	s        *TaskService
+	projects map[primitive.ObjectID]*models.Project
+
+	columns     map[columnKey]int64
+	columnDelta map[columnKey]int64
+
+	assignees     map[assigneeKey]int64
+	assigneeDelta map[assigneeKey]int64
+}
+
+func newBulkWIP(s *TaskService) *bulkWIP {
+	return &bulkWIP{
+		s:             s,
+		projects:      make(map[primitive.ObjectID]*models.Project),
+		columns:       make(map[columnKey]int64),
+		columnDelta:   make(map[columnKey]int64),
+		assignees:     make(map[assigneeKey]int64),
+		assigneeDelta: make(map[assigneeKey]int64),
+	}
+}
+
+// admit checks a task landing where c says, in project as loaded before the
+// transaction. from is the task's state before the write, nil for a new task.
+func (w *bulkWIP) admit(ctx context.Context, project *models.Project, from *models.Task, c wipCheck) error {
+	if column, perAssignee := c.applies(wipLimitFor(project, c.Status)); column || perAssignee {
+		locked, err := w.lock(ctx, c.ProjectID)
+		if err != nil {
+			return err
+		}
+
+		limit := wipLimitFor(locked, c.Status)
+		column, perAssignee := c.applies(limit)
+		key := columnKey{c.ProjectID, c.Status}
+
+		if column {
+			count, err := w.columnCount(ctx, key)
+			if err != nil {
+				return err
+			}
+			if count >= int64(limit.Max) {
+				return &WIPLimitError{Status: c.Status, Limit: limit.Max}
+			}
+		}
+
+		if perAssignee {
+			count, err := w.assigneeCount(ctx, assigneeKey{key, c.Assignee})
+			if err != nil {
+				return err
+			}
+			if count >= int64(limit.MaxPerAssignee) {
+				return &WIPLimitError{Status: c.Status, Limit: limit.MaxPerAssignee, PerAssignee: true}
+			}
+		}
+	}
+
+	to := columnKey{c.ProjectID, c.Status}
+
+	if c.EntersColumn {
+		w.columnDelta[to]++
+	}
+	if (c.EntersColumn || c.AssigneeChanged) && c.Assignee != primitive.NilObjectID {
+		w.assigneeDelta[assigneeKey{to, c.Assignee}]++
+	}
+
+	if from != nil {
+		old := columnKey{from.ProjectID, from.Status}
+		if c.EntersColumn {
+			w.columnDelta[old]--
+		}
+		if (c.EntersColumn || c.AssigneeChanged) && from.AssignedTo != primitive.NilObjectID {
+			w.assigneeDelta[assigneeKey{old, from.AssignedTo}]--
+		}
+	}
+
+	return nil
+}
+
+func (w *bulkWIP) leave(task *models.Task) {
+	from := columnKey{task.ProjectID, task.Status}
+	w.columnDelta[from]--
+	if task.AssignedTo != primitive.NilObjectID {
+		w.assigneeDelta[assigneeKey{from, task.AssignedTo}]--
+	}
+}
+
+// lock locks the project once per batch and returns its limits as read
+// under the lock.
+func (w *bulkWIP) lock(ctx context.Context, projectID primitive.ObjectID) (*models.Project, error) {
+	if project, ok := w.projects[projectID]; ok {
+		return project, nil
+	}
+
+	if err := w.s.projectRepo.Lock(ctx, projectID); err != nil {
+		return nil, err
+	}
+	project, err := w.s.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	w.projects[projectID] = project
+	return project, nil
+}
+
+func (w *bulkWIP) columnCount(ctx context.Context, key columnKey) (int64, error) {
+	base, ok := w.columns[key]
+	if !ok {
+		var err error
+		base, err = w.s.repo.CountInColumn(ctx, key.projectID, key.status)
+		if err != nil {
+			return 0, err
+		}
+		w.columns[key] = base
+	}
+	return base + w.columnDelta[key], nil
+}
+
+func (w *bulkWIP) assigneeCount(ctx context.Context, key assigneeKey) (int64, error) {
+	base, ok := w.assignees[key]
+	if !ok {
+		var err error
+		base, err = w.s.repo.CountInColumnForAssignee(ctx, key.column.projectID, key.column.status, key.assignee)
+		if err != nil {
+			return 0, err
+		}
+		w.assignees[key] = base
+	}
+	return base + w.assigneeDelta[key], nil
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type bulkFixture struct {
+	project *models.Project
+	repo    *fakeTaskRepository
+	service *TaskService
+	user    *models.User
+}
+
+// newBulkFixture sets up a project with one task in "In Progress" and
+// three in "Todo", all last updated at the same time.
+func newBulkFixture(limit models.WIPLimit) *bulkFixture {
+	user := &models.User{ID: primitive.NewObjectID(), Role: models.RoleSuperAdmin}
+	project := &models.Project{
+		ID:        primitive.NewObjectID(),
+		OwnerID:   user.ID,
+		WIPLimits: []models.WIPLimit{limit},
+	}
+
+	updatedAt := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	repo := &fakeTaskRepository{}
+	for _, status := range []string{models.TaskStatusInProgress, models.TaskStatusTodo, models.TaskStatusTodo, models.TaskStatusTodo} {
+		repo.tasks = append(repo.tasks, &models.Task{
+			ID:        primitive.NewObjectID(),
+			ProjectID: project.ID,
+			Status:    status,
+			UpdatedAt: updatedAt,
+		})
+	}
+
+	projects := &fakeProjectRepository{projects: []*models.Project{project}}
+	return &bulkFixture{
+		project: project,
+		repo:    repo,
+		service: NewTaskService(repo, projects, nil, nil, nil, nil, fakeUnitOfWork{}),
+		user:    user,
+	}
+}
+
+// move builds the item prepareBulkItem would for moving the task at index
+// i of the fixture to status.
+func (f *bulkFixture) move(i int, status string) *bulkItem {
+	read := *f.repo.tasks[i]
+	return &bulkItem{
+		result: &dto.BulkTaskResult{Index: i, Op: bulkOpMove, ID: read.ID.Hex()},
+		id:     read.ID,
+		task:   &read,
+		update: &taskUpdate{
+			current: &read,
+			project: f.project,
+			set:     bson.M{"status": status},
+			check: wipCheck{
+				ProjectID:    read.ProjectID,
+				Status:       status,
+				Assignee:     read.AssignedTo,
+				EntersColumn: status != read.Status,
+			},
+		},
+	}
+}
+
+func (f *bulkFixture) write(mode string, items ...*bulkItem) (map[*bulkItem]error, error) {
+	rejected := make(map[*bulkItem]error)
+	err := f.service.writeBulk(context.Background(), f.user, items, mode, rejected)
+	return rejected, err
+}
+
+// Tasks moved earlier in a batch count against the limit for the ones
+// after them, and tasks moved out free their slot.
+func TestWriteBulkWIPLimit(t *testing.T) {
+	limit := models.WIPLimit{Status: models.TaskStatusInProgress, Max: 2}
+
+	t.Run("earlier items fill the column", func(t *testing.T) {
+		f := newBulkFixture(limit)
+		items := []*bulkItem{
+			f.move(1, models.TaskStatusInProgress),
+			f.move(2, models.TaskStatusInProgress),
+			f.move(3, models.TaskStatusInProgress),
+		}
+
+		rejected, err := f.write(BulkModeBestEffort, items...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := rejected[items[0]]; ok {
+			t.Error("first move was rejected")
+		}
+		for _, item := range items[1:] {
+			var limitErr *WIPLimitError
+			if !errors.As(rejected[item], &limitErr) {
+				t.Errorf("item %d: err = %v, want a WIPLimitError", item.result.Index, rejected[item])
+			}
+		}
+		if len(f.repo.writes) != 1 {
+			t.Errorf("%d writes, want 1", len(f.repo.writes))
+		}
+	})
+
+	t.Run("moving out frees a slot", func(t *testing.T) {
+		f := newBulkFixture(limit)
+		items := []*bulkItem{
+			f.move(0, models.TaskStatusDone),
+			f.move(1, models.TaskStatusInProgress),
+			f.move(2, models.TaskStatusInProgress),
+			f.move(3, models.TaskStatusInProgress),
+		}
+
+		rejected, err := f.write(BulkModeBestEffort, items...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rejected) != 1 || rejected[items[3]] == nil {
+			t.Errorf("rejected %d items, want only the last one", len(rejected))
+		}
+	})
+
+	t.Run("deleting frees a slot", func(t *testing.T) {
+		f := newBulkFixture(limit)
+		read := *f.repo.tasks[0]
+		items := []*bulkItem{
+			{result: &dto.BulkTaskResult{Op: bulkOpDelete}, id: read.ID, task: &read},
+			f.move(1, models.TaskStatusInProgress),
+			f.move(2, models.TaskStatusInProgress),
+		}
+
+		rejected, err := f.write(BulkModeBestEffort, items...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rejected) != 0 {
+			t.Errorf("rejected %d items, want none", len(rejected))
+		}
+	})
+
+	t.Run("atomic mode stops at the first rejection", func(t *testing.T) {
+		f := newBulkFixture(limit)
+		items := []*bulkItem{
+			f.move(1, models.TaskStatusInProgress),
+			f.move(2, models.TaskStatusInProgress),
+			f.move(3, models.TaskStatusInProgress),
+		}
+
+		rejected, err := f.write(BulkModeAtomic, items...)
+		if !errors.Is(err, ErrBulkRejected) {
+			t.Fatalf("err = %v, want %v", err, ErrBulkRejected)
+		}
+		if len(rejected) != 1 || rejected[items[1]] == nil {
+			t.Errorf("rejected %d items, want the second one", len(rejected))
+		}
+		if len(f.repo.writes) != 0 {
+			t.Errorf("%d writes, want none", len(f.repo.writes))
+		}
+	})
+
+	t.Run("per assignee", func(t *testing.T) {
+		f := newBulkFixture(models.WIPLimit{Status: models.TaskStatusInProgress, MaxPerAssignee: 1})
+		ada, bob := primitive.NewObjectID(), primitive.NewObjectID()
+		f.repo.tasks[1].AssignedTo = ada
+		f.repo.tasks[2].AssignedTo = ada
+		f.repo.tasks[3].AssignedTo = bob
+
+		items := []*bulkItem{
+			f.move(1, models.TaskStatusInProgress),
+			f.move(2, models.TaskStatusInProgress),
+			f.move(3, models.TaskStatusInProgress),
+		}
+
+		rejected, err := f.write(BulkModeBestEffort, items...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var limitErr *WIPLimitError
+		if len(rejected) != 1 || !errors.As(rejected[items[1]], &limitErr) || !limitErr.PerAssignee {
+			t.Errorf("rejected = %v, want a per-assignee rejection of the second item", rejected)
+		}
+	})
+}
+
+// The WIP deltas are worked out from the tasks as read before the
+// transaction, so a task changed since must fail the whole batch.
+func TestWriteBulkRejectsTasksChangedSinceRead(t *testing.T) {
+	f := newBulkFixture(models.WIPLimit{Status: models.TaskStatusInProgress, Max: 2})
+	items := []*bulkItem{
+		f.move(1, models.TaskStatusInProgress),
+		f.move(2, models.TaskStatusDone),
+	}
+
+	// Someone else reassigns task 2 after the bulk request read it.
+	f.repo.tasks[2].AssignedTo = primitive.NewObjectID()
+	f.repo.tasks[2].UpdatedAt = f.repo.tasks[2].UpdatedAt.Add(time.Second)
+
+	_, err := f.write(BulkModeAtomic, items...)
+	if !errors.Is(err, ErrTasksChanged) {
+		t.Fatalf("err = %v, want %v", err, ErrTasksChanged)
+	}
+
+	for i, w := range f.repo.writes {
+		if !w.UpdatedAt.Equal(items[i].task.UpdatedAt) {
+			t.Errorf("write %d filters on updatedAt %v, want %v as read", i, w.UpdatedAt, items[i].task.UpdatedAt)
+		}
+	}
+}
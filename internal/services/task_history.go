@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Only tasks that are, or were, in a sprint get TaskEvents; nothing reads
+// the history of backlog tasks. Callers write the event in the same
+// transaction as the change it describes.
+
+func recordTaskEvent(
+	ctx context.Context,
+	events repositories.TaskEventRepository,
+	task *models.Task,
+	deleted bool,
+) error {
+	return events.Create(ctx, &models.TaskEvent{
+		TaskID:    task.ID,
+		ProjectID: task.ProjectID,
+		SprintID:  task.SprintID,
+		Status:    task.Status,
+		Deleted:   deleted,
+		At:        time.Now(),
+	})
+}
+
+// writeTracked runs write and, when track is set, records the task's new
+// state in the same transaction.
+func (s *TaskService) writeTracked(
+	ctx context.Context,
+	taskID primitive.ObjectID,
+	track bool,
+	write func(ctx context.Context) error,
+) error {
+
+	if !track {
+		return write(ctx)
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := write(ctx); err != nil {
+			return err
+		}
+
+		task, err := s.repo.FindByID(ctx, taskID)
+		if err != nil {
+			return err
+		}
+
+		return recordTaskEvent(ctx, s.events, task, false)
+	})
+}
@@ -0,0 +1,363 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	maxImportRows     = 5000
+	importBatchSize   = 200
+	importPreviewRows = 20
+)
+
+var ErrImportInvalid = errors.New("import has invalid rows; nothing was imported")
+
+// importFields are the task fields a CSV column can be mapped to, with the
+// headers recognized when no mapping is given.
+var importFields = map[string][]string{
+	"title":       {"title", "name", "summary"},
+	"description": {"description", "details"},
+	"status":      {"status", "state"},
+	"priority":    {"priority"},
+	"dueDate":     {"duedate", "due date", "due"},
+	"assignee":    {"assignee", "assigned to", "owner"},
+}
+
+// importDateLayouts are tried in order for the dueDate column.
+var importDateLayouts = []string{time.RFC3339, "2006-01-02", "2006/01/02"}
+
+// =====================
+// IMPORT
+// =====================
+// ImportTasks creates tasks in a project from CSV. mapping names the CSV
+// column of each task field; without one, columns are matched by header.
+// Every row is checked with the rules of CreateTask. A dry run only reports;
+// otherwise nothing is imported unless every row is valid, and tasks are
+// then inserted in batches, each batch in its own transaction.
+func (s *TaskService) ImportTasks(
+	ctx context.Context,
+	currentUser *models.User,
+	projectID primitive.ObjectID,
+	file io.Reader,
+	mapping map[string]string,
+	dryRun bool,
+) (*dto.TaskImportResponse, error) {
+
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeProject(currentUser, project, actionCreateTask); err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		errs := &ValidationError{}
+		if errors.Is(err, io.EOF) {
+			errs.add("file", "is empty")
+		} else {
+			errs.add("file", err.Error())
+		}
+		return nil, errs
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	columns, mapping, err := resolveImportMapping(header, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.TaskImportResponse{
+		DryRun:  dryRun,
+		Mapping: mapping,
+		Errors:  []dto.TaskImportError{},
+	}
+
+	assignees := make(map[string]*models.User)
+	tasks := make([]models.Task, 0)
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			errs := &ValidationError{}
+			errs.add("file", err.Error())
+			return nil, errs
+		}
+
+		// Quoted fields may span lines, so rows are reported by the line
+		// they start on rather than counted.
+		line, _ := reader.FieldPos(0)
+
+		resp.Rows++
+		if resp.Rows > maxImportRows {
+			errs := &ValidationError{}
+			errs.add("file", fmt.Sprintf("at most %d rows can be imported at once", maxImportRows))
+			return nil, errs
+		}
+
+		task, rowErrs, err := s.importRow(ctx, project, columns, record, assignees)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(rowErrs.Fields) > 0 {
+			resp.Invalid++
+			for _, f := range rowErrs.Fields {
+				resp.Errors = append(resp.Errors, dto.TaskImportError{Row: line, Field: f.Field, Message: f.Message})
+			}
+			continue
+		}
+
+		resp.Valid++
+		tasks = append(tasks, *task)
+	}
+
+	if dryRun {
+		if len(tasks) > importPreviewRows {
+			resp.Preview = tasks[:importPreviewRows]
+		} else {
+			resp.Preview = tasks
+		}
+		return resp, nil
+	}
+
+	if resp.Invalid > 0 {
+		return resp, ErrImportInvalid
+	}
+
+	for start := 0; start < len(tasks); start += importBatchSize {
+		end := start + importBatchSize
+		if end > len(tasks) {
+			end = len(tasks)
+		}
+
+		if err := s.importBatch(ctx, project, tasks[start:end]); err != nil {
+			return resp, fmt.Errorf("import stopped after %d of %d tasks: %w", resp.Imported, len(tasks), err)
+		}
+		resp.Imported += end - start
+	}
+
+	return resp, nil
+}
+
+// resolveImportMapping returns the column index of every mapped field and
+// the mapping as applied.
+func resolveImportMapping(header []string, mapping map[string]string) (map[string]int, map[string]string, error) {
+	errs := &ValidationError{}
+
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		index[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+
+	columns := make(map[string]int)
+	applied := make(map[string]string)
+
+	if len(mapping) == 0 {
+		for field, names := range importFields {
+			for _, name := range names {
+				if i, ok := index[name]; ok {
+					columns[field] = i
+					applied[field] = header[i]
+					break
+				}
+			}
+		}
+	} else {
+		for field, column := range mapping {
+			if _, known := importFields[field]; !known {
+				errs.add("mapping."+field, "is not an importable field")
+				continue
+			}
+			i, ok := index[strings.ToLower(strings.TrimSpace(column))]
+			if !ok {
+				errs.add("mapping."+field, fmt.Sprintf("column %q not found", column))
+				continue
+			}
+			columns[field] = i
+			applied[field] = header[i]
+		}
+	}
+
+	if _, ok := columns["title"]; !ok && len(errs.Fields) == 0 {
+		errs.add("mapping.title", "no column is mapped to title")
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return nil, nil, err
+	}
+	return columns, applied, nil
+}
+
+// importRow turns one record into a task and reports its field errors. It
+// applies the checks of CreateTask against project, which the caller has
+// already loaded and authorized. assignees caches user_id/email lookups
+// across rows.
+func (s *TaskService) importRow(
+	ctx context.Context,
+	project *models.Project,
+	columns map[string]int,
+	record []string,
+	assignees map[string]*models.User,
+) (*models.Task, *ValidationError, error) {
+
+	value := func(field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	errs := &ValidationError{}
+
+	task := &models.Task{
+		ProjectID:   project.ID,
+		Title:       value("title"),
+		Description: value("description"),
+		Status:      normalizeImportStatus(value("status")),
+		Priority:    value("priority"),
+	}
+
+	if due := value("dueDate"); due != "" {
+		parsed := false
+		for _, layout := range importDateLayouts {
+			if t, err := time.Parse(layout, due); err == nil {
+				task.DueDate = t
+				parsed = true
+				break
+			}
+		}
+		if !parsed {
+			errs.add("dueDate", "must be a date like 2006-01-02")
+		}
+	}
+
+	validateTaskFields(task, errs)
+
+	if ref := value("assignee"); ref != "" {
+		user, err := s.resolveAssignee(ctx, ref, assignees)
+		if err != nil {
+			return nil, nil, err
+		}
+		if user == nil {
+			errs.add("assignee", fmt.Sprintf("no user with user_id or email %q", ref))
+		} else {
+			task.AssignedTo = user.ID
+			validateAssignee(project, user, "assignee", errs)
+		}
+	}
+
+	if err := s.validateProjectReferences(ctx, project, task, errs); err != nil {
+		return nil, nil, err
+	}
+
+	return task, errs, nil
+}
+
+// resolveAssignee finds a user by email if ref looks like one and by
+// user_id otherwise. It returns nil when there is no such user.
+func (s *TaskService) resolveAssignee(
+	ctx context.Context,
+	ref string,
+	cache map[string]*models.User,
+) (*models.User, error) {
+
+	if user, ok := cache[ref]; ok {
+		return user, nil
+	}
+
+	var user *models.User
+	var err error
+	if strings.Contains(ref, "@") {
+		user, err = s.userRepo.FindByEmail(ctx, strings.ToLower(ref))
+	} else {
+		user, err = s.userRepo.FindByUserID(ctx, ref)
+	}
+
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		user = nil
+	case err != nil:
+		return nil, err
+	}
+
+	cache[ref] = user
+	return user, nil
+}
+
+// normalizeImportStatus accepts statuses in any case, e.g. "in progress".
+func normalizeImportStatus(status string) string {
+	for _, known := range models.TaskStatuses {
+		if strings.EqualFold(status, known) {
+			return known
+		}
+	}
+	return status
+}
+
+// importBatch inserts tasks at the bottom of their columns, within the
+// project's WIP limits, in one transaction.
+func (s *TaskService) importBatch(ctx context.Context, project *models.Project, tasks []models.Task) error {
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		wip := newBulkWIP(s)
+		bottoms := make(map[string]string)
+		now := time.Now()
+
+		for i := range tasks {
+			task := &tasks[i]
+
+			check := wipCheck{
+				ProjectID:    project.ID,
+				Status:       task.Status,
+				Assignee:     task.AssignedTo,
+				EntersColumn: true,
+			}
+			if err := wip.admit(ctx, project, nil, check); err != nil {
+				return err
+			}
+
+			rank, ok := bottoms[task.Status]
+			if ok {
+				rank = utils.RankBetween(rank, "")
+			} else {
+				var err error
+				rank, err = s.rankAtBottom(ctx, project.ID, task.Status)
+				if err != nil {
+					return err
+				}
+			}
+			bottoms[task.Status] = rank
+
+			task.ID = primitive.NilObjectID
+			task.Rank = rank
+			task.CreatedAt = now
+			task.UpdatedAt = now
+		}
+
+		return s.repo.CreateMany(ctx, tasks)
+	})
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestResolveImportMappingByHeader(t *testing.T) {
+	header := []string{"Summary", " Due Date ", "STATUS", "Owner", "Notes"}
+
+	columns, applied, err := resolveImportMapping(header, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantColumns := map[string]int{"title": 0, "dueDate": 1, "status": 2, "assignee": 3}
+	if !reflect.DeepEqual(columns, wantColumns) {
+		t.Errorf("columns = %v, want %v", columns, wantColumns)
+	}
+
+	wantApplied := map[string]string{"title": "Summary", "dueDate": " Due Date ", "status": "STATUS", "assignee": "Owner"}
+	if !reflect.DeepEqual(applied, wantApplied) {
+		t.Errorf("applied = %v, want %v", applied, wantApplied)
+	}
+}
+
+func TestResolveImportMappingExplicit(t *testing.T) {
+	header := []string{"Card", "Title", "Who"}
+
+	columns, applied, err := resolveImportMapping(header, map[string]string{
+		"title":    "card",
+		"assignee": "WHO",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// An explicit mapping replaces header matching, so "Title" is unused.
+	wantColumns := map[string]int{"title": 0, "assignee": 2}
+	if !reflect.DeepEqual(columns, wantColumns) {
+		t.Errorf("columns = %v, want %v", columns, wantColumns)
+	}
+	if applied["title"] != "Card" || applied["assignee"] != "Who" {
+		t.Errorf("applied = %v", applied)
+	}
+}
+
+func TestResolveImportMappingErrors(t *testing.T) {
+	cases := []struct {
+		name    string
+		header  []string
+		mapping map[string]string
+		fields  []string
+	}{
+		{
+			name:   "no title column",
+			header: []string{"Description", "Priority"},
+			fields: []string{"mapping.title"},
+		},
+		{
+			name:    "unknown field",
+			header:  []string{"Title"},
+			mapping: map[string]string{"title": "Title", "rank": "Title"},
+			fields:  []string{"mapping.rank"},
+		},
+		{
+			name:    "missing column",
+			header:  []string{"Title"},
+			mapping: map[string]string{"title": "Title", "dueDate": "Deadline"},
+			fields:  []string{"mapping.dueDate"},
+		},
+		{
+			name:    "mapping without title",
+			header:  []string{"Title", "Details"},
+			mapping: map[string]string{"description": "Details"},
+			fields:  []string{"mapping.title"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, _, err := resolveImportMapping(c.header, c.mapping)
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("err = %v, want a ValidationError", err)
+			}
+
+			fields := make([]string, len(verr.Fields))
+			for i, f := range verr.Fields {
+				fields[i] = f.Field
+			}
+			if !reflect.DeepEqual(fields, c.fields) {
+				t.Errorf("fields = %v, want %v", fields, c.fields)
+			}
+		})
+	}
+}
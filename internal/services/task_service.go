@@ -10,71 +10,210 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 
+var validTaskStatuses = map[string]bool{
+	models.TaskStatusTodo:       true,
+	models.TaskStatusInProgress: true,
+	models.TaskStatusDone:       true,
+}
+
 type TaskService struct {
-	repo repositories.TaskRepository
+	repo        repositories.TaskRepository
+	projectRepo repositories.ProjectRepository
+	userRepo    repositories.UserRepository
+	sprintRepo    repositories.SprintRepository
+	milestoneRepo repositories.MilestoneRepository
+	events        repositories.TaskEventRepository
+	uow           repositories.UnitOfWork
 }
 
-func NewTaskService(repo repositories.TaskRepository) *TaskService {
-	return &TaskService{repo: repo}
+func NewTaskService(
+	repo repositories.TaskRepository,
+	projectRepo repositories.ProjectRepository,
+	userRepo repositories.UserRepository,
+	sprintRepo repositories.SprintRepository,
+	milestoneRepo repositories.MilestoneRepository,
+	events repositories.TaskEventRepository,
+	uow repositories.UnitOfWork,
+) *TaskService {
+	return &TaskService{
+		repo:          repo,
+		projectRepo:   projectRepo,
+		userRepo:      userRepo,
+		sprintRepo:    sprintRepo,
+		milestoneRepo: milestoneRepo,
+		events:        events,
+		uow:           uow,
+	}
 }
 
 // =====================
 // CREATE
 // =====================
-func (s *TaskService) CreateTask(ctx context.Context, task *models.Task) (*models.Task, error) {
+func (s *TaskService) CreateTask(
+	ctx context.Context,
+	currentUser *models.User,
+	task *models.Task,
+) (*models.Task, error) {
 
-	if task.Title == "" {
-		return nil, errors.New("title is required")
+	project, err := s.validateNewTask(ctx, currentUser, task)
+	if err != nil {
+		return nil, err
 	}
 
+	rank, err := s.rankAtBottom(ctx, task.ProjectID, task.Status)
+	if err != nil {
+		return nil, err
+	}
+	task.Rank = rank
+
+	now := time.Now()
+	task.CreatedAt = now
+	task.UpdatedAt = now
+
+	check := wipCheck{
+		ProjectID:    task.ProjectID,
+		Status:       task.Status,
+		Assignee:     task.AssignedTo,
+		EntersColumn: true,
+	}
+	err = s.withinWIPLimits(ctx, project, check, func(ctx context.Context) error {
+		if task.SprintID == primitive.NilObjectID {
+			return s.repo.Create(ctx, task)
+		}
+		return s.uow.Do(ctx, func(ctx context.Context) error {
+			if err := s.repo.Create(ctx, task); err != nil {
+				return err
+			}
+			return recordTaskEvent(ctx, s.events, task, false)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// validateNewTask applies defaults to task and checks it can be created by
+// currentUser. It returns the task's project.
+func (s *TaskService) validateNewTask(
+	ctx context.Context,
+	currentUser *models.User,
+	task *models.Task,
+) (*models.Project, error) {
+
+	errs := &ValidationError{}
+
 	if task.ProjectID == primitive.NilObjectID {
-		return nil, errors.New("projectId is required")
+		errs.add("projectId", "is required")
+	}
+
+	validateTaskFields(task, errs)
+
+	var project *models.Project
+	if task.ProjectID != primitive.NilObjectID {
+		var err error
+		project, err = s.validateReferences(ctx, task.ProjectID, task.AssignedTo, errs)
+		if err != nil {
+			return nil, err
+		}
+		if project != nil {
+			if err := authorizeProject(currentUser, project, actionCreateTask); err != nil {
+				return nil, err
+			}
+			if err := s.validateProjectReferences(ctx, project, task, errs); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return nil, err
 	}
 
-	validStatus := map[string]bool{
-		"Todo":        true,
-		"In Progress": true,
-		"Done":        true,
+	return project, nil
+}
+
+// validateTaskFields applies defaults to a new task and checks the fields
+// that do not refer to other documents.
+func validateTaskFields(task *models.Task, errs *ValidationError) {
+	if task.Title == "" {
+		errs.add("title", "is required")
 	}
 
-	if task.Status != "" && !validStatus[task.Status] {
-		return nil, errors.New("invalid status")
+	if task.Status != "" && !validTaskStatuses[task.Status] {
+		errs.add("status", "must be one of Todo, In Progress, Done")
 	}
 
 	if task.Status == "" {
-		task.Status = "Todo"
+		task.Status = models.TaskStatusTodo
 	}
 
 	if task.Priority == "" {
 		task.Priority = "Medium"
 	}
 
-	if !task.DueDate.IsZero() && task.DueDate.Before(time.Now()) {
-		return nil, errors.New("dueDate cannot be in the past")
-	}
+	validateDueDate(task.DueDate, errs)
 
-	now := time.Now()
-	task.CreatedAt = now
-	task.UpdatedAt = now
+	task.Labels = normalizeLabels(task.Labels, "labels", errs)
+}
 
-	if err := s.repo.Create(ctx, task); err != nil {
-		return nil, err
+// validateDueDate rejects due dates in the past, both when a task is
+// created and when its due date changes. A zero date means none.
+func validateDueDate(due time.Time, errs *ValidationError) {
+	if !due.IsZero() && due.Before(time.Now()) {
+		errs.add("dueDate", "cannot be in the past")
 	}
+}
 
-	return task, nil
+// validateProjectReferences checks the sprint, milestone, epic and team of
+// a new task against the project it is created in.
+func (s *TaskService) validateProjectReferences(
+	ctx context.Context,
+	project *models.Project,
+	task *models.Task,
+	errs *ValidationError,
+) error {
+
+	if err := s.validateSprint(ctx, task.SprintID, project.ID, errs); err != nil {
+		return err
+	}
+	if err := s.validateMilestone(ctx, models.MilestoneKindMilestone, task.MilestoneID, project.ID, errs); err != nil {
+		return err
+	}
+	if err := s.validateMilestone(ctx, models.MilestoneKindEpic, task.EpicID, project.ID, errs); err != nil {
+		return err
+	}
+	validateAssignedTeam(task.AssignedTeamID, project, errs)
+	return nil
 }
 
 // =====================
 // READ
 // =====================
-func (s *TaskService) GetTaskByID(ctx context.Context, id primitive.ObjectID) (*models.Task, error) {
+func (s *TaskService) GetTaskByID(
+	ctx context.Context,
+	currentUser *models.User,
+	id primitive.ObjectID,
+) (*models.Task, error) {
 	if id == primitive.NilObjectID {
 		return nil, errors.New("id is required")
 	}
-	return s.repo.FindByID(ctx, id)
+
+	task, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.authorizeTask(ctx, currentUser, task, actionViewProject); err != nil {
+		return nil, err
+	}
+
+	return task, nil
 }
 
 // Used by Dashboard (ADMIN)
@@ -90,29 +229,100 @@ func (s *TaskService) GetTasksByProjects(
 	return s.repo.FindByProjectIDs(ctx, projectIDs)
 }
 
-func (s *TaskService) GetAllTasks(ctx context.Context) ([]models.Task, error) {
-	return s.repo.FindAll(ctx)
+// GetAllTasks lists the tasks of every project the user can view.
+func (s *TaskService) GetAllTasks(ctx context.Context, currentUser *models.User) ([]models.Task, error) {
+	tasks, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.visibleTasks(ctx, currentUser, tasks)
 }
 
-func (s *TaskService) GetTasksByProject(ctx context.Context, projectID primitive.ObjectID) ([]models.Task, error) {
+func (s *TaskService) GetTasksByProject(
+	ctx context.Context,
+	currentUser *models.User,
+	projectID primitive.ObjectID,
+) ([]models.Task, error) {
 	if projectID == primitive.NilObjectID {
 		return nil, errors.New("projectId is required")
 	}
+
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeProject(currentUser, project, actionViewProject); err != nil {
+		return nil, err
+	}
+
 	return s.repo.FindByProjectID(ctx, projectID)
 }
 
-func (s *TaskService) GetTasksByAssignedUser(ctx context.Context, userID primitive.ObjectID) ([]models.Task, error) {
+// GetTasksByAssignedUser lists a user's tasks, leaving out those in projects
+// the caller cannot view.
+func (s *TaskService) GetTasksByAssignedUser(
+	ctx context.Context,
+	currentUser *models.User,
+	userID primitive.ObjectID,
+) ([]models.Task, error) {
 	if userID == primitive.NilObjectID {
 		return nil, errors.New("userId is required")
 	}
-	return s.repo.FindByAssignedUser(ctx, userID)
+
+	tasks, err := s.repo.FindByAssignedUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.visibleTasks(ctx, currentUser, tasks)
 }
 
-func (s *TaskService) GetTasksByStatus(ctx context.Context, status string) ([]models.Task, error) {
+// GetTasksByStatus lists the tasks with the status in every project the
+// user can view.
+func (s *TaskService) GetTasksByStatus(
+	ctx context.Context,
+	currentUser *models.User,
+	status string,
+) ([]models.Task, error) {
 	if status == "" {
 		return nil, errors.New("status is required")
 	}
-	return s.repo.FindByStatus(ctx, status)
+
+	tasks, err := s.repo.FindByStatus(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+	return s.visibleTasks(ctx, currentUser, tasks)
+}
+
+// visibleTasks keeps the tasks whose project the user can view, looking each
+// project up once.
+func (s *TaskService) visibleTasks(
+	ctx context.Context,
+	currentUser *models.User,
+	tasks []models.Task,
+) ([]models.Task, error) {
+
+	visible := make(map[primitive.ObjectID]bool)
+	kept := make([]models.Task, 0, len(tasks))
+
+	for _, t := range tasks {
+		allowed, seen := visible[t.ProjectID]
+		if !seen {
+			project, err := s.projectRepo.FindByID(ctx, t.ProjectID)
+			if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+				return nil, err
+			}
+			allowed = err == nil && canOnProject(currentUser, project, actionViewProject)
+			visible[t.ProjectID] = allowed
+		}
+
+		if allowed {
+			kept = append(kept, t)
+		}
+	}
+
+	return kept, nil
 }
 
 
@@ -120,26 +330,254 @@ func (s *TaskService) GetTasksByStatus(ctx context.Context, status string) ([]mo
 // =====================
 // UPDATE
 // =====================
-func (s *TaskService) UpdateTask(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+func (s *TaskService) UpdateTask(
+	ctx context.Context,
+	currentUser *models.User,
+	id primitive.ObjectID,
+	update bson.M,
+) error {
 	if id == primitive.NilObjectID {
 		return errors.New("id is required")
 	}
 
+	current, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	prepared, err := s.prepareUpdate(ctx, currentUser, current, update)
+	if err != nil {
+		return err
+	}
+
+	if prepared.rerank {
+		rank, err := s.rankAtBottom(ctx, prepared.check.ProjectID, prepared.check.Status)
+		if err != nil {
+			return err
+		}
+		update["rank"] = rank
+	}
+
+	return s.withinWIPLimits(ctx, prepared.project, prepared.check, func(ctx context.Context) error {
+		return s.writeTracked(ctx, id, prepared.track, func(ctx context.Context) error {
+			return s.repo.UpdateByID(ctx, id, update)
+		})
+	})
+}
+
+// taskUpdate is an update of one task that passed validation and
+// authorization but has not been written yet.
+type taskUpdate struct {
+	current *models.Task
+	project *models.Project // the project the task ends up in
+	set     bson.M
+	check   wipCheck
+	track   bool
+	// rerank is set when the task lands in another column; it then goes to
+	// the bottom of check.Status in check.ProjectID.
+	rerank bool
+}
+
+// prepareUpdate validates update against the current task and normalizes
+// it in place into the $set document to write, apart from the rank.
+func (s *TaskService) prepareUpdate(
+	ctx context.Context,
+	currentUser *models.User,
+	current *models.Task,
+	update bson.M,
+) (*taskUpdate, error) {
+
+	project, err := s.authorizeTask(ctx, currentUser, current, actionUpdateTask)
+	if err != nil {
+		return nil, err
+	}
+
 	delete(update, "_id")
 	delete(update, "createdAt")
+	delete(update, "rank")
+	delete(update, "previousProjectId")
+	stripTrashFields(update)
+
+	errs := &ValidationError{}
+
+	if title, ok := update["title"]; ok && title == "" {
+		errs.add("title", "cannot be empty")
+	}
+
+	if status, ok := update["status"]; ok {
+		statusStr, _ := status.(string)
+		if !validTaskStatuses[statusStr] {
+			errs.add("status", "must be one of Todo, In Progress, Done")
+		}
+	}
+
+	if value, ok := update["dueDate"]; ok && value == nil {
+		// null clears the due date.
+		update["dueDate"] = time.Time{}
+	} else if due, ok := timeField(update, "dueDate", errs); ok && !due.Equal(current.DueDate) {
+		// Resending the current date is fine even once it has passed.
+		validateDueDate(due, errs)
+	}
+
+	if labels, ok := stringListField(update, "labels", errs); ok {
+		update["labels"] = normalizeLabels(labels, "labels", errs)
+	}
+
+	projectID, projectChanged := objectIDField(update, "projectId", errs)
+	assignee, assigneeChanged := objectIDField(update, "assignedTo", errs)
+	sprintID, sprintChanged := objectIDField(update, "sprintId", errs)
+	milestoneID, milestoneChanged := objectIDField(update, "milestoneId", errs)
+	epicID, epicChanged := objectIDField(update, "epicId", errs)
+	teamID, teamChanged := objectIDField(update, "assignedTeamId", errs)
+
+	if projectChanged && projectID == primitive.NilObjectID {
+		errs.add("projectId", "cannot be empty")
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	if projectChanged || assigneeChanged {
+		if !projectChanged {
+			projectID = current.ProjectID
+		}
+		if !assigneeChanged {
+			assignee = current.AssignedTo
+		}
+
+		target, err := s.validateReferences(ctx, projectID, assignee, errs)
+		if err != nil {
+			return nil, err
+		}
+
+		// Moving a task into another project needs the right to create there.
+		if projectChanged && target != nil {
+			if err := authorizeProject(currentUser, target, actionCreateTask); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := errs.errOrNil(); err != nil {
+			return nil, err
+		}
+
+		if projectChanged {
+			project = target
+		}
+	}
+
+	// Sprints, milestones and epics belong to one project, so a task
+	// leaving the project leaves them too unless new ones are given.
+	if projectChanged {
+		if projectID != current.ProjectID {
+			update["previousProjectId"] = current.ProjectID
+		}
+		if !sprintChanged && current.SprintID != primitive.NilObjectID {
+			update["sprintId"] = primitive.NilObjectID
+			sprintID, sprintChanged = primitive.NilObjectID, true
+		}
+		if !milestoneChanged && current.MilestoneID != primitive.NilObjectID {
+			update["milestoneId"] = primitive.NilObjectID
+		}
+		if !epicChanged && current.EpicID != primitive.NilObjectID {
+			update["epicId"] = primitive.NilObjectID
+		}
+	}
+
+	if sprintChanged {
+		if err := s.validateSprint(ctx, sprintID, project.ID, errs); err != nil {
+			return nil, err
+		}
+	}
+	if milestoneChanged {
+		if err := s.validateMilestone(ctx, models.MilestoneKindMilestone, milestoneID, project.ID, errs); err != nil {
+			return nil, err
+		}
+	}
+	if epicChanged {
+		if err := s.validateMilestone(ctx, models.MilestoneKindEpic, epicID, project.ID, errs); err != nil {
+			return nil, err
+		}
+	}
+	if teamChanged || projectChanged {
+		if !teamChanged {
+			teamID = current.AssignedTeamID
+		}
+		validateAssignedTeam(teamID, project, errs)
+	}
+	if err := errs.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	// Ordering within a column is changed through MoveTask. A task that
+	// lands in another column through a plain update goes to its bottom.
+	newStatus, _ := update["status"].(string)
+	if newStatus == "" {
+		newStatus = current.Status
+	}
+	targetProjectID := current.ProjectID
+	if projectChanged {
+		targetProjectID = projectID
+	}
+	entersColumn := newStatus != current.Status || targetProjectID != current.ProjectID
+
+	newAssignee := current.AssignedTo
+	if assigneeChanged {
+		newAssignee = assignee
+	}
+
+	newSprint := current.SprintID
+	if sprintChanged {
+		newSprint = sprintID
+	}
+	inSprint := current.SprintID != primitive.NilObjectID || newSprint != primitive.NilObjectID
 
 	update["updatedAt"] = time.Now()
-	return s.repo.UpdateByID(ctx, id, update)
+
+	return &taskUpdate{
+		current: current,
+		project: project,
+		set:     update,
+		check: wipCheck{
+			ProjectID:       targetProjectID,
+			Status:          newStatus,
+			Assignee:        newAssignee,
+			EntersColumn:    entersColumn,
+			AssigneeChanged: newAssignee != current.AssignedTo,
+		},
+		track:  inSprint && (newStatus != current.Status || newSprint != current.SprintID),
+		rerank: entersColumn,
+	}, nil
 }
 
 // =====================
 // DELETE
 // =====================
-func (s *TaskService) DeleteTask(ctx context.Context, id primitive.ObjectID) error {
+func (s *TaskService) DeleteTask(ctx context.Context, currentUser *models.User, id primitive.ObjectID) error {
 	if id == primitive.NilObjectID {
 		return errors.New("id is required")
 	}
-	return s.repo.DeleteByID(ctx, id)
+
+	task, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.authorizeTask(ctx, currentUser, task, actionDeleteTask); err != nil {
+		return err
+	}
+
+	if task.SprintID == primitive.NilObjectID {
+		return s.repo.DeleteByID(ctx, id, currentUser.ID)
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.DeleteByID(ctx, id, currentUser.ID); err != nil {
+			return err
+		}
+		return recordTaskEvent(ctx, s.events, task, true)
+	})
 }
 // Used by Dashboard (ADMIN)
 func (s *TaskService) GetTasksByOwner(
@@ -153,3 +591,194 @@ func (s *TaskService) GetTasksByOwner(
 
 	return s.repo.FindByAssignedUser(ctx, ownerID)
 }
+
+// openTasksOf lists the open tasks a deactivated user hands over. Tasks in
+// archived projects are read-only, so they are left out.
+func (s *TaskService) openTasksOf(ctx context.Context, userID primitive.ObjectID) ([]models.Task, error) {
+	tasks, err := s.repo.FindOpenByAssignee(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	archived := make(map[primitive.ObjectID]bool)
+	open := tasks[:0]
+
+	for _, task := range tasks {
+		isArchived, ok := archived[task.ProjectID]
+		if !ok {
+			project, err := s.projectRepo.FindByID(ctx, task.ProjectID)
+			if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+				return nil, err
+			}
+			isArchived = project != nil && project.ArchivedAt != nil
+			archived[task.ProjectID] = isArchived
+		}
+		if !isArchived {
+			open = append(open, task)
+		}
+	}
+
+	return open, nil
+}
+
+// reassignOpenTasks hands fromID's open tasks in projectIDs to toID.
+func (s *TaskService) reassignOpenTasks(ctx context.Context, fromID, toID primitive.ObjectID, projectIDs []primitive.ObjectID) error {
+	if len(projectIDs) == 0 {
+		return nil
+	}
+	_, err := s.repo.ReassignOpen(ctx, fromID, toID, projectIDs)
+	return err
+}
+
+// user → assigned tasks: NULLIFY
+func (s *TaskService) unassignUser(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := s.repo.UnassignUser(ctx, userID)
+	return err
+}
+
+// authorizeTask loads the task's project and checks the caller's project role.
+func (s *TaskService) authorizeTask(
+	ctx context.Context,
+	currentUser *models.User,
+	task *models.Task,
+	action projectAction,
+) (*models.Project, error) {
+
+	project, err := s.projectRepo.FindByID(ctx, task.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeProject(currentUser, project, action); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// validateReferences checks that the project exists and that the assignee,
+// if any, exists and belongs to that project. It returns the project when
+// found; lookup failures other than "not found" are returned as errors.
+func (s *TaskService) validateReferences(
+	ctx context.Context,
+	projectID primitive.ObjectID,
+	assignedTo primitive.ObjectID,
+	errs *ValidationError,
+) (*models.Project, error) {
+
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			errs.add("projectId", "project not found")
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if assignedTo == primitive.NilObjectID {
+		return project, nil
+	}
+
+	assignee, err := s.userRepo.FindByID(ctx, assignedTo)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			errs.add("assignedTo", "user not found")
+			return project, nil
+		}
+		return nil, err
+	}
+
+	validateAssignee(project, assignee, "assignedTo", errs)
+	return project, nil
+}
+
+// validateAssignee checks that a user can be assigned tasks of the project.
+func validateAssignee(project *models.Project, assignee *models.User, field string, errs *ValidationError) {
+	if !assignee.IsActive() {
+		errs.add(field, "user is "+assignee.Status)
+	} else if !isProjectMemberUser(project, assignee) {
+		errs.add(field, "user is not a member of the project")
+	}
+}
+
+// validateSprint checks that the sprint, if any, exists, belongs to the
+// task's project and is not closed.
+func (s *TaskService) validateSprint(
+	ctx context.Context,
+	sprintID primitive.ObjectID,
+	projectID primitive.ObjectID,
+	errs *ValidationError,
+) error {
+
+	if sprintID == primitive.NilObjectID {
+		return nil
+	}
+
+	sprint, err := s.sprintRepo.FindByID(ctx, sprintID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			errs.add("sprintId", "sprint not found")
+			return nil
+		}
+		return err
+	}
+
+	switch {
+	case sprint.ProjectID != projectID:
+		errs.add("sprintId", "sprint belongs to another project")
+	case sprint.State == models.SprintStateClosed:
+		errs.add("sprintId", "sprint is closed")
+	}
+
+	return nil
+}
+
+// validateAssignedTeam checks that the team, if any, has been added to the
+// project. Teams are pulled from projects when deleted, so this also proves
+// the team exists.
+func validateAssignedTeam(teamID primitive.ObjectID, project *models.Project, errs *ValidationError) {
+	if teamID == primitive.NilObjectID {
+		return
+	}
+	for _, t := range project.Teams {
+		if t.TeamID == teamID {
+			return
+		}
+	}
+	errs.add("assignedTeamId", "team is not part of the project")
+}
+
+// validateMilestone checks that the milestone or epic, if any, exists,
+// is of the expected kind and belongs to the task's project.
+func (s *TaskService) validateMilestone(
+	ctx context.Context,
+	kind string,
+	milestoneID primitive.ObjectID,
+	projectID primitive.ObjectID,
+	errs *ValidationError,
+) error {
+
+	if milestoneID == primitive.NilObjectID {
+		return nil
+	}
+
+	field := models.MilestoneTaskField(kind)
+
+	milestone, err := s.milestoneRepo.FindByID(ctx, milestoneID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			errs.add(field, kind+" not found")
+			return nil
+		}
+		return err
+	}
+
+	switch {
+	case milestone.Kind != kind:
+		errs.add(field, "wrong kind, expected "+kind)
+	case milestone.ProjectID != projectID:
+		errs.add(field, kind+" belongs to another project")
+	}
+
+	return nil
+}
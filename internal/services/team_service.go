@@ -0,0 +1,407 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrAlreadyTeamMember = errors.New("user is already a member of this team")
+	ErrNotTeamMember     = errors.New("user is not a member of this team")
+	ErrTeamLead          = errors.New("the team lead cannot be removed; choose another lead first")
+)
+
+type TeamService struct {
+	repo        repositories.TeamRepository
+	userRepo    repositories.UserRepository
+	projectRepo repositories.ProjectRepository
+	taskRepo    repositories.TaskRepository
+	uow         repositories.UnitOfWork
+}
+
+func NewTeamService(
+	repo repositories.TeamRepository,
+	userRepo repositories.UserRepository,
+	projectRepo repositories.ProjectRepository,
+	taskRepo repositories.TaskRepository,
+	uow repositories.UnitOfWork,
+) *TeamService {
+	return &TeamService{
+		repo:        repo,
+		userRepo:    userRepo,
+		projectRepo: projectRepo,
+		taskRepo:    taskRepo,
+		uow:         uow,
+	}
+}
+
+// Admins create and delete teams. A team's lead may also manage its members
+// and details.
+
+func canManageTeam(user *models.User, team *models.Team) bool {
+	return isAdminCapable(user) || (team.LeadID != primitive.NilObjectID && team.LeadID == user.ID)
+}
+
+// =====================
+// CREATE
+// =====================
+func (s *TeamService) CreateTeam(
+	ctx context.Context,
+	currentUser *models.User,
+	team *models.Team,
+) (*models.Team, error) {
+
+	if !isAdminCapable(currentUser) {
+		return nil, ErrForbidden
+	}
+
+	team.Name = strings.TrimSpace(team.Name)
+
+	errs := &ValidationError{}
+
+	if team.Name == "" {
+		errs.add("name", "is required")
+	}
+
+	// The lead is always a member.
+	members := team.MemberIDs
+	if team.LeadID != primitive.NilObjectID {
+		members = append([]primitive.ObjectID{team.LeadID}, members...)
+	}
+	team.MemberIDs = uniqueIDs(members)
+
+	if err := s.validateUsers(ctx, "memberIds", team.MemberIDs, errs); err != nil {
+		return nil, err
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	team.ID = primitive.NilObjectID
+	team.CreatedAt = now
+	team.UpdatedAt = now
+
+	err := s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, team); err != nil {
+			return err
+		}
+		for _, id := range team.MemberIDs {
+			if err := s.userRepo.AddTeam(ctx, id, team.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return team, nil
+}
+
+// =====================
+// READ
+// =====================
+func (s *TeamService) GetTeams(ctx context.Context) ([]models.Team, error) {
+	return s.repo.FindAll(ctx)
+}
+
+func (s *TeamService) GetTeam(ctx context.Context, id string) (*models.Team, error) {
+	return s.loadTeam(ctx, id)
+}
+
+// GetTeamQueue lists tasks assigned to the team or to any of its members,
+// leaving out tasks in projects the caller cannot see.
+func (s *TeamService) GetTeamQueue(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+	status string,
+) ([]models.Task, error) {
+
+	team, err := s.loadTeam(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != "" && !validTaskStatuses[status] {
+		errs := &ValidationError{}
+		errs.add("status", "must be one of Todo, In Progress, Done")
+		return nil, errs
+	}
+
+	tasks, err := s.taskRepo.FindTeamQueue(ctx, team.ID, team.MemberIDs, status)
+	if err != nil {
+		return nil, err
+	}
+
+	visible := make(map[primitive.ObjectID]bool)
+	queue := make([]models.Task, 0, len(tasks))
+
+	for _, task := range tasks {
+		allowed, checked := visible[task.ProjectID]
+		if !checked {
+			project, err := s.projectRepo.FindByID(ctx, task.ProjectID)
+			if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+				return nil, err
+			}
+			allowed = project != nil && canOnProject(currentUser, project, actionViewProject)
+			visible[task.ProjectID] = allowed
+		}
+		if allowed {
+			queue = append(queue, task)
+		}
+	}
+
+	return queue, nil
+}
+
+// =====================
+// UPDATE
+// =====================
+// UpdateTeam changes name, description and lead. A new lead who is not yet
+// a member joins the team.
+func (s *TeamService) UpdateTeam(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+	update bson.M,
+) error {
+
+	team, err := s.loadTeam(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !canManageTeam(currentUser, team) {
+		return ErrForbidden
+	}
+
+	errs := &ValidationError{}
+
+	for field := range update {
+		switch field {
+		case "name", "description", "leadId":
+		default:
+			errs.add(field, "cannot be updated here")
+		}
+	}
+
+	if name, ok := update["name"]; ok {
+		str, _ := name.(string)
+		if strings.TrimSpace(str) == "" {
+			errs.add("name", "cannot be empty")
+		}
+		update["name"] = strings.TrimSpace(str)
+	}
+
+	leadID, leadChanged := objectIDField(update, "leadId", errs)
+	if leadChanged && leadID != primitive.NilObjectID {
+		if err := s.validateUsers(ctx, "leadId", []primitive.ObjectID{leadID}, errs); err != nil {
+			return err
+		}
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return err
+	}
+
+	update["updatedAt"] = time.Now()
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if leadChanged && leadID != primitive.NilObjectID {
+			if err := s.addMember(ctx, team.ID, leadID); err != nil && !errors.Is(err, ErrAlreadyTeamMember) {
+				return err
+			}
+		}
+		return s.repo.UpdateByID(ctx, team.ID, update)
+	})
+}
+
+// =====================
+// MEMBERS
+// =====================
+func (s *TeamService) AddMember(
+	ctx context.Context,
+	currentUser *models.User,
+	teamID string,
+	userID string,
+) error {
+
+	team, err := s.loadTeam(ctx, teamID)
+	if err != nil {
+		return err
+	}
+
+	if !canManageTeam(currentUser, team) {
+		return ErrForbidden
+	}
+
+	errs := &ValidationError{}
+
+	memberID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		errs.add("userId", "must be a valid ObjectID")
+	} else if err := s.validateUsers(ctx, "userId", []primitive.ObjectID{memberID}, errs); err != nil {
+		return err
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return err
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		return s.addMember(ctx, team.ID, memberID)
+	})
+}
+
+func (s *TeamService) addMember(ctx context.Context, teamID, userID primitive.ObjectID) error {
+	added, err := s.repo.AddMember(ctx, teamID, userID)
+	if err != nil {
+		return err
+	}
+	if !added {
+		return ErrAlreadyTeamMember
+	}
+	return s.userRepo.AddTeam(ctx, userID, teamID)
+}
+
+// RemoveMember takes the user out of the team and with it out of every
+// project they only reached through the team. Members may always leave.
+func (s *TeamService) RemoveMember(
+	ctx context.Context,
+	currentUser *models.User,
+	teamID string,
+	userID string,
+) error {
+
+	team, err := s.loadTeam(ctx, teamID)
+	if err != nil {
+		return err
+	}
+
+	memberID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.New("invalid user id")
+	}
+
+	if memberID != currentUser.ID && !canManageTeam(currentUser, team) {
+		return ErrForbidden
+	}
+
+	if memberID == team.LeadID {
+		return ErrTeamLead
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		removed, err := s.repo.RemoveMember(ctx, team.ID, memberID)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return ErrNotTeamMember
+		}
+		return s.userRepo.RemoveTeam(ctx, memberID, team.ID)
+	})
+}
+
+// =====================
+// DELETE
+// =====================
+// DeleteTeam removes the team for good, in a single transaction:
+//
+//	team → project access: NULLIFY
+//	team → assigned tasks: NULLIFY
+//	team → members' teamIds: NULLIFY
+func (s *TeamService) DeleteTeam(ctx context.Context, currentUser *models.User, id string) error {
+	if !isAdminCapable(currentUser) {
+		return ErrForbidden
+	}
+
+	team, err := s.loadTeam(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if _, err := s.projectRepo.RemoveTeamFromAll(ctx, team.ID); err != nil {
+			return err
+		}
+		if _, err := s.taskRepo.UnassignTeam(ctx, team.ID); err != nil {
+			return err
+		}
+		if _, err := s.userRepo.RemoveTeamFromAll(ctx, team.ID); err != nil {
+			return err
+		}
+		return s.repo.DeleteByID(ctx, team.ID)
+	})
+}
+
+func (s *TeamService) loadTeam(ctx context.Context, id string) (*models.Team, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("invalid team id")
+	}
+	return s.repo.FindByID(ctx, objID)
+}
+
+// validateUsers records a field error for every id that is not a live user.
+func (s *TeamService) validateUsers(
+	ctx context.Context,
+	field string,
+	ids []primitive.ObjectID,
+	errs *ValidationError,
+) error {
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	users, err := s.userRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	found := make(map[primitive.ObjectID]bool, len(users))
+	for _, u := range users {
+		found[u.ID] = true
+	}
+
+	for i, id := range ids {
+		if found[id] {
+			continue
+		}
+		if len(ids) == 1 {
+			errs.add(field, "user not found")
+		} else {
+			errs.add(fmt.Sprintf("%s[%d]", field, i), "user not found")
+		}
+	}
+
+	return nil
+}
+
+func uniqueIDs(ids []primitive.ObjectID) []primitive.ObjectID {
+	seen := make(map[primitive.ObjectID]bool, len(ids))
+	result := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if id == primitive.NilObjectID || seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
@@ -0,0 +1,382 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/repositories"
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TemplateService saves projects as templates, creates projects from
+// templates and deep-clones projects. Every project it creates is written,
+// with its tasks, in a single transaction.
+type TemplateService struct {
+	repo           repositories.ProjectTemplateRepository
+	projectRepo    repositories.ProjectRepository
+	taskRepo       repositories.TaskRepository
+	milestoneRepo  repositories.MilestoneRepository
+	projectService *ProjectService
+	uow            repositories.UnitOfWork
+}
+
+func NewTemplateService(
+	repo repositories.ProjectTemplateRepository,
+	projectRepo repositories.ProjectRepository,
+	taskRepo repositories.TaskRepository,
+	milestoneRepo repositories.MilestoneRepository,
+	projectService *ProjectService,
+	uow repositories.UnitOfWork,
+) *TemplateService {
+	return &TemplateService{
+		repo:           repo,
+		projectRepo:    projectRepo,
+		taskRepo:       taskRepo,
+		milestoneRepo:  milestoneRepo,
+		projectService: projectService,
+		uow:            uow,
+	}
+}
+
+// =====================
+// SAVE AS TEMPLATE
+// =====================
+
+// SaveTemplate stores the project's live tasks as a template. Due dates are
+// kept relative to startDate, which defaults to the project's creation day.
+func (s *TemplateService) SaveTemplate(
+	ctx context.Context,
+	currentUser *models.User,
+	projectID string,
+	name string,
+	description string,
+	startDate time.Time,
+) (*models.ProjectTemplate, error) {
+
+	project, err := s.projectService.loadProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeProject(currentUser, project, actionSaveTemplate); err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		errs := &ValidationError{}
+		errs.add("name", "is required")
+		return nil, errs
+	}
+
+	if startDate.IsZero() {
+		startDate = project.CreatedAt
+	}
+	start := startDate.UTC().Truncate(day)
+
+	tasks, err := s.taskRepo.FindBoard(ctx, project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &models.ProjectTemplate{
+		Name:            name,
+		Description:     description,
+		SourceProjectID: project.ID,
+		CreatedBy:       currentUser.ID,
+		Tasks:           make([]models.TemplateTask, 0, len(tasks)),
+		WIPLimits:       project.WIPLimits,
+		CreatedAt:       time.Now(),
+	}
+
+	for _, task := range boardOrder(tasks) {
+		t := models.TemplateTask{
+			Title:       task.Title,
+			Description: task.Description,
+			Priority:    task.Priority,
+			Labels:      task.Labels,
+		}
+		if !task.DueDate.IsZero() {
+			offset := int(math.Round(task.DueDate.Sub(start).Hours() / 24))
+			t.DueOffsetDays = &offset
+		}
+		template.Tasks = append(template.Tasks, t)
+	}
+
+	if err := s.repo.Create(ctx, template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+// =====================
+// READ / DELETE
+// =====================
+func (s *TemplateService) GetTemplates(ctx context.Context) ([]models.ProjectTemplate, error) {
+	return s.repo.FindAll(ctx)
+}
+
+func (s *TemplateService) GetTemplate(ctx context.Context, id string) (*models.ProjectTemplate, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("invalid template id")
+	}
+
+	return s.repo.FindByID(ctx, objID)
+}
+
+// Only the template's author or a super_admin can delete it.
+func (s *TemplateService) DeleteTemplate(ctx context.Context, currentUser *models.User, id string) error {
+	template, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if currentUser.Role != models.RoleSuperAdmin && template.CreatedBy != currentUser.ID {
+		return ErrForbidden
+	}
+
+	return s.repo.DeleteByID(ctx, template.ID)
+}
+
+// =====================
+// INSTANTIATE
+// =====================
+
+// InstantiateTemplate creates project from the template. Every task starts
+// in Todo, in template order, with its due date offset from startDate.
+func (s *TemplateService) InstantiateTemplate(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+	project *models.Project,
+	startDate time.Time,
+) (*models.Project, error) {
+
+	if !isAdminCapable(currentUser) {
+		return nil, ErrForbidden
+	}
+
+	template, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if startDate.IsZero() {
+		startDate = time.Now()
+	}
+	start := startDate.UTC().Truncate(day)
+
+	now := time.Now()
+	project.WIPLimits = template.WIPLimits
+
+	if err := s.projectService.prepareNewProject(ctx, project, now); err != nil {
+		return nil, err
+	}
+
+	ranks := utils.EvenRanks(len(template.Tasks))
+	tasks := make([]models.Task, len(template.Tasks))
+	for i, t := range template.Tasks {
+		tasks[i] = models.Task{
+			Title:       t.Title,
+			Description: t.Description,
+			Status:      models.TaskStatusTodo,
+			Priority:    t.Priority,
+			Labels:      t.Labels,
+			Rank:        ranks[i],
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if tasks[i].Priority == "" {
+			tasks[i].Priority = "Medium"
+		}
+		if t.DueOffsetDays != nil {
+			tasks[i].DueDate = start.AddDate(0, 0, *t.DueOffsetDays)
+		}
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.projectRepo.Create(ctx, project); err != nil {
+			return err
+		}
+		for i := range tasks {
+			tasks[i].ProjectID = project.ID
+		}
+		return s.taskRepo.CreateMany(ctx, tasks)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// =====================
+// CLONE
+// =====================
+
+// CloneProject deep-copies a project: settings, milestones and epics, and
+// every live task with its status, order, labels and due date. Sprints are
+// not copied. With includeMembers the members (and the previous owner, as
+// maintainer) come along and keep their assignments; otherwise tasks are
+// left unassigned. The owner defaults to the caller. The source is read inside the same transaction, so the
+// copy is a consistent snapshot.
+func (s *TemplateService) CloneProject(
+	ctx context.Context,
+	currentUser *models.User,
+	projectID string,
+	name string,
+	ownerIDHex string,
+	includeMembers bool,
+) (*models.Project, error) {
+
+	if !isAdminCapable(currentUser) {
+		return nil, ErrForbidden
+	}
+
+	ownerID := currentUser.ID
+	if ownerIDHex != "" {
+		id, err := primitive.ObjectIDFromHex(ownerIDHex)
+		if err != nil {
+			errs := &ValidationError{}
+			errs.add("ownerId", "must be a valid ObjectID")
+			return nil, errs
+		}
+		ownerID = id
+	}
+
+	source, err := s.projectService.loadProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeProject(currentUser, source, actionViewProject); err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		name = source.Name + " (copy)"
+	}
+
+	var clone *models.Project
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		source, err := s.projectRepo.FindByID(ctx, source.ID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		clone = &models.Project{
+			Name:        name,
+			Description: source.Description,
+			OwnerID:     ownerID,
+			WIPLimits:   source.WIPLimits,
+		}
+		if includeMembers {
+			clone.Members = append([]models.ProjectMember{}, source.Members...)
+			clone.MemberIDs = append([]primitive.ObjectID{}, source.MemberIDs...)
+			if source.OwnerID != ownerID {
+				clone.Members = append(clone.Members, models.ProjectMember{
+					UserID: source.OwnerID,
+					Role:   models.ProjectRoleMaintainer,
+				})
+			}
+		}
+
+		if err := s.projectService.prepareNewProject(ctx, clone, now); err != nil {
+			return err
+		}
+		if includeMembers {
+			clone.Teams = append([]models.ProjectTeam{}, source.Teams...)
+		}
+		if err := s.projectRepo.Create(ctx, clone); err != nil {
+			return err
+		}
+
+		milestoneIDs, err := s.cloneMilestones(ctx, source.ID, clone.ID, now)
+		if err != nil {
+			return err
+		}
+
+		tasks, err := s.taskRepo.FindByProjectID(ctx, source.ID)
+		if err != nil {
+			return err
+		}
+
+		for i := range tasks {
+			t := &tasks[i]
+			t.ID = primitive.NilObjectID
+			t.ProjectID = clone.ID
+			t.SprintID = primitive.NilObjectID
+			t.MilestoneID = milestoneIDs[t.MilestoneID]
+			t.EpicID = milestoneIDs[t.EpicID]
+			// With members and teams copied every assignee stays valid.
+			if !includeMembers {
+				if !isProjectMember(clone, t.AssignedTo) {
+					t.AssignedTo = primitive.NilObjectID
+				}
+				t.AssignedTeamID = primitive.NilObjectID
+			}
+			t.CreatedAt = now
+			t.UpdatedAt = now
+		}
+
+		return s.taskRepo.CreateMany(ctx, tasks)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return clone, nil
+}
+
+// cloneMilestones copies the source project's milestones and epics and
+// returns a map from old to new ids. Unknown ids map to NilObjectID.
+func (s *TemplateService) cloneMilestones(
+	ctx context.Context,
+	sourceID primitive.ObjectID,
+	cloneID primitive.ObjectID,
+	now time.Time,
+) (map[primitive.ObjectID]primitive.ObjectID, error) {
+
+	milestones, err := s.milestoneRepo.FindByProjectID(ctx, sourceID, "")
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[primitive.ObjectID]primitive.ObjectID, len(milestones))
+	for i := range milestones {
+		m := &milestones[i]
+		oldID := m.ID
+		m.ID = primitive.NilObjectID
+		m.ProjectID = cloneID
+		m.CreatedAt = now
+		m.UpdatedAt = now
+		if err := s.milestoneRepo.Create(ctx, m); err != nil {
+			return nil, err
+		}
+		ids[oldID] = m.ID
+	}
+
+	return ids, nil
+}
+
+// boardOrder returns tasks column by column, keeping rank order within
+// each column.
+func boardOrder(tasks []models.Task) []models.Task {
+	ordered := make([]models.Task, 0, len(tasks))
+	for _, status := range models.TaskStatuses {
+		for _, t := range tasks {
+			if t.Status == status {
+				ordered = append(ordered, t)
+			}
+		}
+	}
+	return ordered
+}
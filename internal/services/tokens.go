@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"Concurrent_Task_Management_System/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Secret tokens given to clients that cannot log in, such as invitation
+// links and calendar feeds, have the form <workspace id>.<secret>, so a
+// request carrying one can be scoped to its workspace without a lookup
+// across workspaces. Only a hash of the token is stored.
+
+func newWorkspaceToken(ctx context.Context) (string, error) {
+	workspaceID, ok := repositories.TenantFrom(ctx)
+	if !ok {
+		return "", repositories.ErrNoTenant
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return workspaceID.Hex() + "." + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// parseWorkspaceToken returns the workspace a token belongs to.
+func parseWorkspaceToken(token string) (primitive.ObjectID, bool) {
+	workspaceHex, secret, found := strings.Cut(token, ".")
+	workspaceID, err := primitive.ObjectIDFromHex(workspaceHex)
+	if !found || secret == "" || err != nil {
+		return primitive.NilObjectID, false
+	}
+	return workspaceID, true
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrNotInTrash       = errors.New("item not found in trash")
+	ErrRestoreForbidden = errors.New("only the user who deleted this item or a super_admin can restore it")
+)
+
+type TrashService struct {
+	userRepo    repositories.UserRepository
+	projectRepo repositories.ProjectRepository
+	taskRepo    repositories.TaskRepository
+	events      repositories.TaskEventRepository
+	uow         repositories.UnitOfWork
+	retention   time.Duration
+}
+
+func NewTrashService(
+	userRepo repositories.UserRepository,
+	projectRepo repositories.ProjectRepository,
+	taskRepo repositories.TaskRepository,
+	events repositories.TaskEventRepository,
+	uow repositories.UnitOfWork,
+	retention time.Duration,
+) *TrashService {
+	return &TrashService{
+		userRepo:    userRepo,
+		projectRepo: projectRepo,
+		taskRepo:    taskRepo,
+		events:      events,
+		uow:         uow,
+		retention:   retention,
+	}
+}
+
+// =====================
+// READ
+// =====================
+
+// super_admin sees the whole trash, everyone else only what they deleted.
+func (s *TrashService) GetTrash(
+	ctx context.Context,
+	currentUser *models.User,
+) (*dto.TrashResponse, error) {
+
+	response := &dto.TrashResponse{
+		RetentionDays: int(s.retention / (24 * time.Hour)),
+	}
+
+	var err error
+
+	if currentUser.Role == models.RoleSuperAdmin {
+		if response.Users, err = s.userRepo.FindDeleted(ctx); err != nil {
+			return nil, err
+		}
+		if response.Projects, err = s.projectRepo.FindDeleted(ctx); err != nil {
+			return nil, err
+		}
+		if response.Tasks, err = s.taskRepo.FindDeleted(ctx); err != nil {
+			return nil, err
+		}
+		return response, nil
+	}
+
+	if response.Users, err = s.userRepo.FindDeletedBy(ctx, currentUser.ID); err != nil {
+		return nil, err
+	}
+	if response.Projects, err = s.projectRepo.FindDeletedBy(ctx, currentUser.ID); err != nil {
+		return nil, err
+	}
+	if response.Tasks, err = s.taskRepo.FindDeletedBy(ctx, currentUser.ID); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// =====================
+// RESTORE
+// =====================
+func (s *TrashService) RestoreUser(ctx context.Context, currentUser *models.User, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid user id")
+	}
+
+	user, err := s.userRepo.FindDeletedByID(ctx, objID)
+	if err != nil {
+		return trashLookupError(err)
+	}
+
+	if !canRestore(currentUser, user.DeletedBy) {
+		return ErrRestoreForbidden
+	}
+
+	// A trashed user's email and user_id may have been given to someone
+	// else in the meantime.
+	err = s.userRepo.RestoreByID(ctx, objID)
+	switch repositories.DuplicateKeyField(err) {
+	case "email":
+		return ErrAlreadyUser
+	case "user_id":
+		return ErrUserIDTaken
+	}
+	return err
+}
+
+func (s *TrashService) RestoreProject(ctx context.Context, currentUser *models.User, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid project id")
+	}
+
+	project, err := s.projectRepo.FindDeletedByID(ctx, objID)
+	if err != nil {
+		return trashLookupError(err)
+	}
+
+	if !canRestore(currentUser, project.DeletedBy) {
+		return ErrRestoreForbidden
+	}
+
+	// Tasks trashed by the project cascade come back with it.
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.projectRepo.RestoreByID(ctx, objID); err != nil {
+			return err
+		}
+
+		_, err := s.taskRepo.RestoreByProjectID(ctx, objID, *project.DeletedAt)
+		return err
+	})
+}
+
+func (s *TrashService) RestoreTask(ctx context.Context, currentUser *models.User, id string) error {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid task id")
+	}
+
+	task, err := s.taskRepo.FindDeletedByID(ctx, objID)
+	if err != nil {
+		return trashLookupError(err)
+	}
+
+	if !canRestore(currentUser, task.DeletedBy) {
+		return ErrRestoreForbidden
+	}
+
+	// A task cannot come back into a project that is itself in the trash,
+	// nor into an archived (read-only) one.
+	project, err := s.projectRepo.FindByID(ctx, task.ProjectID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return errors.New("restore the task's project first")
+		}
+		return err
+	}
+	if err := ensureNotArchived(project); err != nil {
+		return err
+	}
+
+	if task.SprintID == primitive.NilObjectID {
+		return s.taskRepo.RestoreByID(ctx, objID)
+	}
+
+	// The task counts towards its sprint's burndown again.
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.taskRepo.RestoreByID(ctx, objID); err != nil {
+			return err
+		}
+		return recordTaskEvent(ctx, s.events, task, false)
+	})
+}
+
+// =====================
+// PURGE
+// =====================
+func (s *TrashService) PurgeExpired(ctx context.Context) (int64, error) {
+	// The retention window is the same for every workspace, so one pass
+	// purges them all.
+	ctx = repositories.WithAllTenants(ctx)
+
+	cutoff := time.Now().Add(-s.retention)
+
+	var total int64
+
+	purged, err := s.taskRepo.PurgeDeletedBefore(ctx, cutoff)
+	if err != nil {
+		return total, err
+	}
+	total += purged
+
+	purged, err = s.projectRepo.PurgeDeletedBefore(ctx, cutoff)
+	if err != nil {
+		return total, err
+	}
+	total += purged
+
+	purged, err = s.userRepo.PurgeDeletedBefore(ctx, cutoff)
+	if err != nil {
+		return total, err
+	}
+	total += purged
+
+	return total, nil
+}
+
+// StartPurgeLoop purges expired trash every interval until ctx is cancelled.
+func (s *TrashService) StartPurgeLoop(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				purged, err := s.PurgeExpired(ctx)
+				if err != nil {
+					log.Println("Trash purge error:", err)
+					continue
+				}
+				if purged > 0 {
+					log.Printf("Trash purge removed %d documents", purged)
+				}
+			}
+		}
+	}()
+}
+
+func canRestore(currentUser *models.User, deletedBy *primitive.ObjectID) bool {
+	if currentUser.Role == models.RoleSuperAdmin {
+		return true
+	}
+	return deletedBy != nil && *deletedBy == currentUser.ID
+}
+
+func trashLookupError(err error) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrNotInTrash
+	}
+	return err
+}
+
+// stripTrashFields keeps generic updates from moving documents in or out
+// of the trash; that only happens through DeleteByID and RestoreByID.
+func stripTrashFields(update bson.M) {
+	delete(update, "deletedAt")
+	delete(update, "deletedBy")
+}
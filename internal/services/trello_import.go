@@ -0,0 +1,472 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/utils"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// The parts of a Trello board export (Menu → Print, export and share →
+// Export as JSON) that the importer reads.
+type trelloBoard struct {
+	Name         string             `json:"name"`
+	Desc         string             `json:"desc"`
+	Lists        []trelloList       `json:"lists"`
+	Cards        []trelloCard       `json:"cards"`
+	Members      []trelloMember     `json:"members"`
+	Memberships  []trelloMembership `json:"memberships"`
+	Checklists   []trelloChecklist  `json:"checklists"`
+	Actions      []trelloAction     `json:"actions"`
+	CustomFields []struct {
+		Name string `json:"name"`
+	} `json:"customFields"`
+}
+
+type trelloList struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Closed bool    `json:"closed"`
+	Pos    float64 `json:"pos"`
+}
+
+type trelloCard struct {
+	ID           string        `json:"id"`
+	Name         string        `json:"name"`
+	Desc         string        `json:"desc"`
+	Closed       bool          `json:"closed"`
+	IDList       string        `json:"idList"`
+	Due          *time.Time    `json:"due"`
+	Pos          float64       `json:"pos"`
+	IDMembers    []string      `json:"idMembers"`
+	IDChecklists []string      `json:"idChecklists"`
+	Labels       []trelloLabel `json:"labels"`
+	Badges       struct {
+		Attachments int `json:"attachments"`
+	} `json:"badges"`
+}
+
+type trelloLabel struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+type trelloMember struct {
+	ID       string `json:"id"`
+	FullName string `json:"fullName"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+type trelloMembership struct {
+	IDMember   string `json:"idMember"`
+	MemberType string `json:"memberType"`
+}
+
+type trelloChecklist struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Pos        float64 `json:"pos"`
+	CheckItems []struct {
+		Name  string  `json:"name"`
+		State string  `json:"state"`
+		Pos   float64 `json:"pos"`
+	} `json:"checkItems"`
+}
+
+type trelloAction struct {
+	Type string `json:"type"`
+}
+
+// trelloListStatuses guesses a list's status from words in its name; lists
+// matching none become Todo.
+var trelloListStatuses = []struct {
+	status string
+	words  []string
+}{
+	{models.TaskStatusDone, []string{"done", "complete", "finished", "closed", "shipped", "released"}},
+	{models.TaskStatusInProgress, []string{"doing", "progress", "review", "testing", "wip", "started"}},
+}
+
+// Trello board roles and the project roles they become.
+var trelloMemberRoles = map[string]string{
+	"admin":    models.ProjectRoleMaintainer,
+	"normal":   models.ProjectRoleContributor,
+	"observer": models.ProjectRoleViewer,
+}
+
+// =====================
+// TRELLO IMPORT
+// =====================
+
+// ImportTrelloBoard creates a project owned by the caller from a Trello
+// board export. Lists become statuses, open cards become tasks with their
+// description, checklists (appended to the description), due date and
+// labels, and board members matched to users here join the project and
+// keep their cards. Anything left out is listed in the response. A dry run
+// writes nothing; otherwise the project and its tasks are written in one
+// transaction.
+func (s *ExportService) ImportTrelloBoard(
+	ctx context.Context,
+	currentUser *models.User,
+	file io.Reader,
+	mapping dto.TrelloImportMapping,
+	name string,
+	dryRun bool,
+) (*dto.TrelloImportResponse, error) {
+
+	if !isAdminCapable(currentUser) {
+		return nil, ErrForbidden
+	}
+
+	var board trelloBoard
+	if err := json.NewDecoder(file).Decode(&board); err != nil {
+		errs := &ValidationError{}
+		errs.add("file", "is not a Trello board export: "+err.Error())
+		return nil, errs
+	}
+
+	if name == "" {
+		name = board.Name
+	}
+
+	errs := &ValidationError{}
+	if len(board.Lists) == 0 {
+		errs.add("file", "has no lists; is it a Trello board export?")
+	}
+	if len(board.Cards) > maxImportRows {
+		errs.add("file", fmt.Sprintf("at most %d cards can be imported at once", maxImportRows))
+	}
+	for _, list := range sortedKeys(mapping.Lists) {
+		if !validTaskStatuses[mapping.Lists[list]] {
+			errs.add("mapping.lists."+list, "must be one of Todo, In Progress, Done")
+		}
+	}
+	if err := errs.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	resp := &dto.TrelloImportResponse{
+		DryRun:  dryRun,
+		Lists:   make(map[string]string, len(board.Lists)),
+		Members: make(map[string]string, len(board.Members)),
+		Issues:  []dto.TrelloImportIssue{},
+	}
+	issue := func(kind, name, format string, args ...interface{}) {
+		resp.Issues = append(resp.Issues, dto.TrelloImportIssue{Kind: kind, Name: name, Message: fmt.Sprintf(format, args...)})
+	}
+
+	users, err := s.matchTrelloMembers(ctx, board.Members, mapping.Members, resp, issue)
+	if err != nil {
+		return nil, err
+	}
+
+	project := &models.Project{
+		Name:        name,
+		Description: board.Desc,
+		OwnerID:     currentUser.ID,
+	}
+	for _, m := range board.Memberships {
+		user, ok := users[m.IDMember]
+		if !ok {
+			continue
+		}
+		role, ok := trelloMemberRoles[m.MemberType]
+		if !ok {
+			role = models.ProjectRoleContributor
+		}
+		project.Members = append(project.Members, models.ProjectMember{UserID: user.ID, Role: role})
+	}
+	// Card members who are not board members still need access.
+	for _, c := range board.Cards {
+		for _, id := range c.IDMembers {
+			if user, ok := users[id]; ok {
+				project.Members = append(project.Members, models.ProjectMember{UserID: user.ID, Role: models.ProjectRoleContributor})
+			}
+		}
+	}
+
+	now := time.Now()
+	if err := s.projectService.prepareNewProject(ctx, project, now); err != nil {
+		return nil, err
+	}
+
+	// Lists
+	lists := make(map[string]trelloList, len(board.Lists))
+	statuses := make(map[string]string, len(board.Lists))
+	for _, l := range board.Lists {
+		lists[l.ID] = l
+		if l.Closed {
+			continue
+		}
+		status, ok := mapping.Lists[l.Name]
+		if !ok {
+			status, ok = mapping.Lists[l.ID]
+		}
+		if !ok {
+			status = guessListStatus(l.Name)
+		}
+		statuses[l.ID] = status
+		resp.Lists[l.Name] = status
+	}
+
+	checklists := make(map[string]trelloChecklist, len(board.Checklists))
+	for _, c := range board.Checklists {
+		checklists[c.ID] = c
+	}
+
+	// Cards
+	cards := make([]trelloCard, 0, len(board.Cards))
+	archivedCards, archivedLists := 0, make(map[string]int)
+	attachments := 0
+
+	for _, c := range board.Cards {
+		list, listFound := lists[c.IDList]
+		switch {
+		case c.Closed:
+			archivedCards++
+			continue
+		case !listFound:
+			issue("card", c.Name, "its list is not in the export; skipped")
+			continue
+		case list.Closed:
+			archivedLists[list.Name]++
+			continue
+		case strings.TrimSpace(c.Name) == "":
+			issue("card", c.ID, "has no title; skipped")
+			continue
+		}
+		attachments += c.Badges.Attachments
+		cards = append(cards, c)
+	}
+
+	sort.SliceStable(cards, func(i, j int) bool {
+		li, lj := lists[cards[i].IDList], lists[cards[j].IDList]
+		if li.Pos != lj.Pos {
+			return li.Pos < lj.Pos
+		}
+		return cards[i].Pos < cards[j].Pos
+	})
+
+	tasks := make([]models.Task, 0, len(cards))
+	for _, c := range cards {
+		task := models.Task{
+			Title:       strings.TrimSpace(c.Name),
+			Description: trelloDescription(c, checklists),
+			Status:      statuses[c.IDList],
+			Priority:    "Medium",
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if c.Due != nil {
+			task.DueDate = *c.Due
+		}
+
+		labels := make([]string, 0, len(c.Labels))
+		for _, l := range c.Labels {
+			if l.Name != "" {
+				labels = append(labels, l.Name)
+			} else if l.Color != "" {
+				labels = append(labels, l.Color)
+			}
+		}
+		labelErrs := &ValidationError{}
+		task.Labels = normalizeLabels(labels, "labels", labelErrs)
+		if len(task.Labels) > maxLabels {
+			task.Labels = task.Labels[:maxLabels]
+		}
+		if len(labelErrs.Fields) > 0 {
+			issue("label", c.Name, "some labels were too long or too many and were dropped")
+		}
+
+		var assignees []*models.User
+		for _, id := range c.IDMembers {
+			if user, ok := users[id]; ok {
+				assignees = append(assignees, user)
+			}
+		}
+		if len(assignees) > 0 {
+			task.AssignedTo = assignees[0].ID
+		}
+		if len(assignees) > 1 {
+			issue("card", c.Name, "has %d members; assigned to %s only", len(assignees), assignees[0].Name)
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	// Ranks follow list order, then card order, within each status.
+	for _, status := range models.TaskStatuses {
+		var column []int
+		for i := range tasks {
+			if tasks[i].Status == status {
+				column = append(column, i)
+			}
+		}
+		for i, rank := range utils.EvenRanks(len(column)) {
+			tasks[column[i]].Rank = rank
+		}
+	}
+
+	// Things with no place to go here
+	if archivedCards > 0 {
+		issue("card", "", "%d archived cards were skipped", archivedCards)
+	}
+	for _, list := range sortedKeys(archivedLists) {
+		issue("list", list, "list is archived; its %d cards were skipped", archivedLists[list])
+	}
+	if attachments > 0 {
+		issue("attachment", "", "%d attachments were not imported", attachments)
+	}
+	comments := 0
+	for _, a := range board.Actions {
+		if a.Type == "commentCard" {
+			comments++
+		}
+	}
+	if comments > 0 {
+		issue("comment", "", "%d comments were not imported", comments)
+	}
+	for _, f := range board.CustomFields {
+		issue("customField", f.Name, "custom fields are not supported")
+	}
+
+	resp.Tasks = len(tasks)
+
+	if dryRun {
+		resp.Preview = tasks[:min(len(tasks), importPreviewRows)]
+		return resp, nil
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		project.ID = primitive.NilObjectID
+		if err := s.projectRepo.Create(ctx, project); err != nil {
+			return err
+		}
+		for i := range tasks {
+			tasks[i].ID = primitive.NilObjectID
+			tasks[i].ProjectID = project.ID
+		}
+		return s.taskRepo.CreateMany(ctx, tasks)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Project = project
+	return resp, nil
+}
+
+// matchTrelloMembers maps Trello member ids to active users, through the
+// mapping if it names the member's username, otherwise by email.
+func (s *ExportService) matchTrelloMembers(
+	ctx context.Context,
+	members []trelloMember,
+	mapping map[string]string,
+	resp *dto.TrelloImportResponse,
+	issue func(kind, name, format string, args ...interface{}),
+) (map[string]*models.User, error) {
+
+	users := make(map[string]*models.User, len(members))
+
+	for _, m := range members {
+		ref, mapped := mapping[m.Username]
+		if !mapped {
+			ref = m.Email
+		}
+		if ref == "" {
+			issue("member", m.Username, "has no email in the export; map the username to a user to import their cards")
+			continue
+		}
+
+		var user *models.User
+		var err error
+		if strings.Contains(ref, "@") {
+			user, err = s.userRepo.FindByEmail(ctx, strings.ToLower(ref))
+		} else {
+			user, err = s.userRepo.FindByUserID(ctx, ref)
+		}
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			issue("member", m.Username, "no user with user_id or email %q", ref)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !user.IsActive() {
+			issue("member", m.Username, "user %s is %s", user.UserID, user.Status)
+			continue
+		}
+
+		users[m.ID] = user
+		resp.Members[m.Username] = user.UserID
+	}
+
+	return users, nil
+}
+
+func guessListStatus(name string) string {
+	name = strings.ToLower(name)
+	for _, candidate := range trelloListStatuses {
+		for _, word := range candidate.words {
+			if strings.Contains(name, word) {
+				return candidate.status
+			}
+		}
+	}
+	return models.TaskStatusTodo
+}
+
+// trelloDescription appends the card's checklists to its description as
+// Markdown task lists.
+func trelloDescription(card trelloCard, checklists map[string]trelloChecklist) string {
+	var b strings.Builder
+	b.WriteString(strings.TrimSpace(card.Desc))
+
+	lists := make([]trelloChecklist, 0, len(card.IDChecklists))
+	for _, id := range card.IDChecklists {
+		if c, ok := checklists[id]; ok {
+			lists = append(lists, c)
+		}
+	}
+	sort.SliceStable(lists, func(i, j int) bool { return lists[i].Pos < lists[j].Pos })
+
+	for _, c := range lists {
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		b.WriteString("### " + c.Name)
+
+		items := c.CheckItems
+		sort.SliceStable(items, func(i, j int) bool { return items[i].Pos < items[j].Pos })
+		for _, item := range items {
+			mark := " "
+			if item.State == "complete" {
+				mark = "x"
+			}
+			b.WriteString("\n- [" + mark + "] " + item.Name)
+		}
+	}
+
+	return b.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
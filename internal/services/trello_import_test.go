@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestGuessListStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"To Do", models.TaskStatusTodo},
+		{"Backlog", models.TaskStatusTodo},
+		{"Doing", models.TaskStatusInProgress},
+		{"In Review", models.TaskStatusInProgress},
+		{"WIP", models.TaskStatusInProgress},
+		{"Done", models.TaskStatusDone},
+		{"Shipped this week", models.TaskStatusDone},
+		{"Review done", models.TaskStatusDone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := guessListStatus(tt.name); got != tt.want {
+				t.Errorf("guessListStatus(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrelloDescription(t *testing.T) {
+	var lists []trelloChecklist
+	err := json.Unmarshal([]byte(`[
+		{"id": "later", "name": "Release", "pos": 2},
+		{"id": "first", "name": "Build", "pos": 1, "checkItems": [
+			{"name": "test", "state": "incomplete", "pos": 2},
+			{"name": "compile", "state": "complete", "pos": 1}
+		]}
+	]`), &lists)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checklists := make(map[string]trelloChecklist, len(lists))
+	for _, c := range lists {
+		checklists[c.ID] = c
+	}
+
+	tests := []struct {
+		name string
+		card trelloCard
+		want string
+	}{
+		{
+			name: "description only",
+			card: trelloCard{Desc: "  Ship it  "},
+			want: "Ship it",
+		},
+		{
+			name: "checklists in board order",
+			card: trelloCard{Desc: "Ship it", IDChecklists: []string{"later", "first", "gone"}},
+			want: "Ship it\n\n### Build\n- [x] compile\n- [ ] test\n\n### Release",
+		},
+		{
+			name: "checklists without description",
+			card: trelloCard{IDChecklists: []string{"later"}},
+			want: "### Release",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trelloDescription(tt.card, checklists); got != tt.want {
+				t.Errorf("trelloDescription =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+const trelloTestBoard = `{
+	"name": "Launch",
+	"desc": "Everything for the launch",
+	"lists": [
+		{"id": "l-todo", "name": "To Do", "pos": 1},
+		{"id": "l-doing", "name": "Doing", "pos": 2},
+		{"id": "l-qa", "name": "QA", "pos": 3},
+		{"id": "l-old", "name": "Old ideas", "pos": 4, "closed": true}
+	],
+	"members": [
+		{"id": "m-alice", "username": "alice", "email": "alice@example.com"},
+		{"id": "m-bob", "username": "bob"},
+		{"id": "m-carol", "username": "carol", "email": "carol@example.com"},
+		{"id": "m-dave", "username": "dave", "email": "dave@example.com"}
+	],
+	"memberships": [
+		{"idMember": "m-alice", "memberType": "admin"},
+		{"idMember": "m-bob", "memberType": "observer"}
+	],
+	"cards": [
+		{"id": "c-second", "name": "Second", "idList": "l-todo", "pos": 2, "idMembers": ["m-alice", "m-bob"],
+			"labels": [{"name": "bug"}, {"color": "green"}], "badges": {"attachments": 2}},
+		{"id": "c-first", "name": "First", "idList": "l-todo", "pos": 1, "due": "2026-11-02T17:00:00Z"},
+		{"id": "c-working", "name": "Working", "idList": "l-doing", "pos": 1, "idMembers": ["m-bob"]},
+		{"id": "c-tested", "name": "Tested", "idList": "l-qa", "pos": 1},
+		{"id": "c-archived", "name": "Archived", "idList": "l-todo", "closed": true},
+		{"id": "c-idea", "name": "Idea", "idList": "l-old"},
+		{"id": "c-lost", "name": "Lost", "idList": "l-gone"},
+		{"id": "c-blank", "name": "  ", "idList": "l-todo"}
+	],
+	"actions": [{"type": "commentCard"}, {"type": "updateCard"}, {"type": "commentCard"}],
+	"customFields": [{"name": "Estimate"}]
+}`
+
+type trelloFixture struct {
+	service  *ExportService
+	projects *fakeProjectRepository
+	tasks    *fakeTaskRepository
+	caller   *models.User
+	alice    *models.User
+	bob      *models.User
+}
+
+func newTrelloFixture() *trelloFixture {
+	f := &trelloFixture{
+		projects: &fakeProjectRepository{},
+		tasks:    &fakeTaskRepository{},
+		caller:   &models.User{ID: primitive.NewObjectID(), UserID: "admin", Role: models.RoleAdmin},
+		alice:    &models.User{ID: primitive.NewObjectID(), UserID: "alice", Name: "Alice", Email: "alice@example.com"},
+		bob:      &models.User{ID: primitive.NewObjectID(), UserID: "bob_b", Name: "Bob", Email: "bob@example.com"},
+	}
+	dave := &models.User{ID: primitive.NewObjectID(), UserID: "dave", Email: "dave@example.com", Status: models.UserStatusSuspended}
+	users := newFakeUserRepository(f.caller, f.alice, f.bob, dave)
+
+	projectService := NewProjectService(f.projects, f.tasks, users, nil, fakeUnitOfWork{})
+	f.service = NewExportService(projectService, f.projects, f.tasks, nil, nil, nil, users, nil, fakeUnitOfWork{})
+	return f
+}
+
+func (f *trelloFixture) importBoard(t *testing.T, dryRun bool) *dto.TrelloImportResponse {
+	t.Helper()
+
+	mapping := dto.TrelloImportMapping{
+		Lists:   map[string]string{"QA": models.TaskStatusDone},
+		Members: map[string]string{"bob": "bob_b"},
+	}
+	resp, err := f.service.ImportTrelloBoard(context.Background(), f.caller, strings.NewReader(trelloTestBoard), mapping, "", dryRun)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestImportTrelloBoard(t *testing.T) {
+	f := newTrelloFixture()
+	resp := f.importBoard(t, false)
+
+	wantLists := map[string]string{
+		"To Do": models.TaskStatusTodo,
+		"Doing": models.TaskStatusInProgress,
+		"QA":    models.TaskStatusDone,
+	}
+	if !reflect.DeepEqual(resp.Lists, wantLists) {
+		t.Errorf("lists = %v, want %v", resp.Lists, wantLists)
+	}
+	wantMembers := map[string]string{"alice": "alice", "bob": "bob_b"}
+	if !reflect.DeepEqual(resp.Members, wantMembers) {
+		t.Errorf("members = %v, want %v", resp.Members, wantMembers)
+	}
+
+	var issues []string
+	for _, issue := range resp.Issues {
+		issues = append(issues, issue.Kind+":"+issue.Name)
+	}
+	wantIssues := []string{
+		"member:carol",
+		"member:dave",
+		"card:Lost",
+		"card:c-blank",
+		"card:Second",
+		"card:",
+		"list:Old ideas",
+		"attachment:",
+		"comment:",
+		"customField:Estimate",
+	}
+	if !reflect.DeepEqual(issues, wantIssues) {
+		t.Errorf("issues = %v, want %v", issues, wantIssues)
+	}
+
+	project := resp.Project
+	if project == nil || project.Name != "Launch" || project.OwnerID != f.caller.ID {
+		t.Fatalf("project = %+v, want Launch owned by the caller", project)
+	}
+	roles := make(map[primitive.ObjectID]string)
+	for _, m := range project.Members {
+		roles[m.UserID] = m.Role
+	}
+	wantRoles := map[primitive.ObjectID]string{
+		f.alice.ID: models.ProjectRoleMaintainer,
+		f.bob.ID:   models.ProjectRoleViewer,
+	}
+	if !reflect.DeepEqual(roles, wantRoles) {
+		t.Errorf("member roles = %v, want %v", roles, wantRoles)
+	}
+
+	if resp.Tasks != 4 || len(f.tasks.tasks) != 4 {
+		t.Fatalf("imported %d tasks, wrote %d; want 4", resp.Tasks, len(f.tasks.tasks))
+	}
+	type imported struct {
+		title, status string
+		assignee      primitive.ObjectID
+		labels        []string
+	}
+	var got []imported
+	for _, task := range f.tasks.tasks {
+		if task.ProjectID != project.ID {
+			t.Errorf("task %q is in project %v, want %v", task.Title, task.ProjectID, project.ID)
+		}
+		got = append(got, imported{task.Title, task.Status, task.AssignedTo, task.Labels})
+	}
+	want := []imported{
+		{"First", models.TaskStatusTodo, primitive.NilObjectID, []string{}},
+		{"Second", models.TaskStatusTodo, f.alice.ID, []string{"bug", "green"}},
+		{"Working", models.TaskStatusInProgress, f.bob.ID, []string{}},
+		{"Tested", models.TaskStatusDone, primitive.NilObjectID, []string{}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tasks =\n%+v\nwant\n%+v", got, want)
+	}
+
+	first, second := f.tasks.tasks[0], f.tasks.tasks[1]
+	if first.Rank >= second.Rank {
+		t.Errorf("ranks %q, %q do not follow card order", first.Rank, second.Rank)
+	}
+	if first.DueDate.IsZero() {
+		t.Error("due date was not imported")
+	}
+}
+
+func TestImportTrelloBoardDryRunWritesNothing(t *testing.T) {
+	f := newTrelloFixture()
+	resp := f.importBoard(t, true)
+
+	if len(f.projects.projects) != 0 || len(f.tasks.tasks) != 0 {
+		t.Errorf("dry run wrote %d projects and %d tasks", len(f.projects.projects), len(f.tasks.tasks))
+	}
+	if resp.Tasks != 4 || len(resp.Preview) != 4 {
+		t.Errorf("tasks = %d, preview = %d; want 4 and 4", resp.Tasks, len(resp.Preview))
+	}
+}
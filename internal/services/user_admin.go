@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrSuperAdminExists    = errors.New("workspace already has an active super_admin")
+	ErrCannotChangeOwnRole = errors.New("you cannot change your own role")
+	ErrLastSuperAdmin      = errors.New("cannot demote the last active super_admin")
+	ErrRoleUnchanged       = errors.New("user already has this role")
+	ErrWrongPassword       = errors.New("current password is incorrect")
+)
+
+var assignableRoles = map[string]bool{
+	models.RoleSuperAdmin: true,
+	models.RoleAdmin:      true,
+	models.RoleEmployee:   true,
+}
+
+// HashPassword checks the password policy and returns a bcrypt hash.
+func HashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength {
+		errs := &ValidationError{}
+		errs.add("password", fmt.Sprintf("must be at least %d characters", minPasswordLength))
+		return "", errs
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// BootstrapSuperAdmin creates the first super_admin of the workspace in ctx.
+// It refuses once the workspace has an active one; from then on roles are
+// handed out with ChangeRole.
+func (s *UserService) BootstrapSuperAdmin(
+	ctx context.Context,
+	user *models.User,
+	password string,
+) (*models.User, error) {
+
+	hash, err := HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	user.ID = primitive.NilObjectID
+	user.Role = models.RoleSuperAdmin
+	user.PasswordHash = hash
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		admins, err := s.activeSuperAdmins(ctx)
+		if err != nil {
+			return err
+		}
+		if admins > 0 {
+			return ErrSuperAdminExists
+		}
+
+		user.ID = primitive.NilObjectID
+		_, err = s.createUser(ctx, user)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ChangeRole moves a user between employee, admin and super_admin. Admins
+// manage employees and admins; only a super_admin grants or takes away
+// super_admin, and the workspace always keeps at least one. Project owners
+// must stay admin-capable, so their projects are transferred first.
+func (s *UserService) ChangeRole(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+	role string,
+) (*models.User, error) {
+
+	if !isAdminCapable(currentUser) {
+		return nil, ErrForbidden
+	}
+
+	if !assignableRoles[role] {
+		errs := &ValidationError{}
+		errs.add("role", "must be one of employee, admin, super_admin")
+		return nil, errs
+	}
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("invalid user id")
+	}
+	if objID == currentUser.ID {
+		return nil, ErrCannotChangeOwnRole
+	}
+
+	var user *models.User
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		user, err = s.repo.FindByID(ctx, objID)
+		if err != nil {
+			return err
+		}
+
+		if user.Role == role {
+			return ErrRoleUnchanged
+		}
+		if (user.Role == models.RoleSuperAdmin || role == models.RoleSuperAdmin) &&
+			currentUser.Role != models.RoleSuperAdmin {
+			return ErrForbidden
+		}
+
+		if user.Role == models.RoleSuperAdmin && user.IsActive() {
+			admins, err := s.activeSuperAdmins(ctx)
+			if err != nil {
+				return err
+			}
+			if admins <= 1 {
+				return ErrLastSuperAdmin
+			}
+		}
+
+		if role == models.RoleEmployee {
+			owned, err := s.projectService.countOwnedProjects(ctx, user.ID)
+			if err != nil {
+				return err
+			}
+			if owned > 0 {
+				return fmt.Errorf("%w (%d owned)", ErrUserOwnsProjects, owned)
+			}
+		}
+
+		if err := s.repo.UpdateByID(ctx, user.ID, bson.M{"role": role}); err != nil {
+			return err
+		}
+		user.Role = role
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// SetPassword replaces a user's password. Users change their own by giving
+// the current one; admins set anyone else's without it, which is also how
+// accounts created before passwords existed get one. Only a super_admin
+// sets a super_admin's password.
+func (s *UserService) SetPassword(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+	currentPassword string,
+	newPassword string,
+) error {
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return errors.New("invalid user id")
+	}
+
+	user, err := s.repo.FindByID(ctx, objID)
+	if err != nil {
+		return err
+	}
+
+	if user.ID == currentUser.ID {
+		if !s.CheckPassword(user, currentPassword) {
+			return ErrWrongPassword
+		}
+	} else {
+		if !isAdminCapable(currentUser) {
+			return ErrForbidden
+		}
+		if user.Role == models.RoleSuperAdmin && currentUser.Role != models.RoleSuperAdmin {
+			return ErrForbidden
+		}
+	}
+
+	hash, err := HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.UpdateByID(ctx, user.ID, bson.M{"passwordHash": hash})
+}
+
+func (s *UserService) activeSuperAdmins(ctx context.Context) (int, error) {
+	users, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, u := range users {
+		if u.Role == models.RoleSuperAdmin && u.IsActive() {
+			count++
+		}
+	}
+	return count, nil
+}
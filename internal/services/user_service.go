@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"Concurrent_Task_Management_System/internal/models"
@@ -10,25 +11,75 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrUserOwnsProjects = errors.New("user owns projects; pass transferTo to hand them over before deleting")
+	ErrCannotDeleteSelf = errors.New("you cannot delete your own account")
 )
 
 type UserService struct {
 	repo           repositories.UserRepository
 	projectService *ProjectService
+	taskService    *TaskService
+	uow            repositories.UnitOfWork
 }
 
 func NewUserService(
 	repo repositories.UserRepository,
 	projectService *ProjectService,
+	taskService *TaskService,
+	uow repositories.UnitOfWork,
 ) *UserService {
 	return &UserService{
 		repo: repo,
 		projectService: projectService,
+		taskService:    taskService,
+		uow:            uow,
 	}
 }
 
+// CreateUser adds a user on behalf of currentUser, with the same rules as
+// ChangeRole: admins create employees and admins, and only a super_admin
+// creates another super_admin. Every user gets a password.
+func (s *UserService) CreateUser(
+	ctx context.Context,
+	currentUser *models.User,
+	user *models.User,
+	password string,
+) (*models.User, error) {
+
+	if !isAdminCapable(currentUser) {
+		return nil, ErrForbidden
+	}
+
+	if user.Role == "" {
+		user.Role = models.RoleEmployee
+	}
+	if !assignableRoles[user.Role] {
+		errs := &ValidationError{}
+		errs.add("role", "must be one of employee, admin, super_admin")
+		return nil, errs
+	}
+	if user.Role == models.RoleSuperAdmin && currentUser.Role != models.RoleSuperAdmin {
+		return nil, ErrForbidden
+	}
+
+	hash, err := HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
 
-func (s *UserService) CreateUser(ctx context.Context, user *models.User) (*models.User, error) {
+	user.ID = primitive.NilObjectID
+	user.PasswordHash = hash
+
+	return s.createUser(ctx, user)
+}
+
+// createUser validates and stores a user whose role and password the caller
+// has already settled.
+func (s *UserService) createUser(ctx context.Context, user *models.User) (*models.User, error) {
 
 	// ❌ DO NOT validate user.ID (MongoDB generates it)
 
@@ -45,9 +96,13 @@ func (s *UserService) CreateUser(ctx context.Context, user *models.User) (*model
 	}
 
 	if user.Role == "" {
-		user.Role = "user"
+		user.Role = models.RoleEmployee
 	}
 
+	// Team membership is managed through /teams.
+	user.TeamIDs = nil
+	user.Status = models.UserStatusActive
+	user.StatusChangedAt = nil
 	user.CreatedAt = time.Now()
 
 	err := s.repo.Create(ctx, user)
@@ -75,7 +130,16 @@ func (s *UserService) GetAllUsers(ctx context.Context) ([]models.User, error) {
 	return s.repo.FindAll(ctx)
 }
 
-func (s *UserService) UpdateUser(ctx context.Context, id string, update bson.M) error {
+// UpdateUser changes a user's profile. Users edit their own; admins edit
+// anyone's, except that only a super_admin edits another super_admin. Roles
+// change through ChangeRole.
+func (s *UserService) UpdateUser(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+	update bson.M,
+) error {
+
 	if id == "" {
 		return errors.New("id is required")
 	}
@@ -85,10 +149,52 @@ func (s *UserService) UpdateUser(ctx context.Context, id string, update bson.M)
 		return errors.New("invalid user id")
 	}
 
+	if objID != currentUser.ID {
+		if !isAdminCapable(currentUser) {
+			return ErrForbidden
+		}
+		user, err := s.repo.FindByID(ctx, objID)
+		if err != nil {
+			return err
+		}
+		if user.Role == models.RoleSuperAdmin && currentUser.Role != models.RoleSuperAdmin {
+			return ErrForbidden
+		}
+	}
+
+	delete(update, "_id")
+	delete(update, "role")
+	delete(update, "createdAt")
+	delete(update, "teamIds")
+	delete(update, "passwordHash")
+	delete(update, "calendarTokenHash")
+	delete(update, "status")
+	delete(update, "statusChangedAt")
+	stripTrashFields(update)
+
 	return s.repo.UpdateByID(ctx, objID, update)
 }
 
-func (s *UserService) DeleteUser(ctx context.Context, id string) error {
+// DeleteUser moves a user to the trash and detaches them from everything
+// that references them, in a single transaction:
+//
+//	user → owned projects: RESTRICT, unless transferTo names a new owner
+//	user → project memberships: NULLIFY
+//	user → team memberships: NULLIFY (teams they led are left without a lead)
+//	user → assigned tasks: NULLIFY
+//
+// Only admins delete users, never themselves, and only a super_admin
+// deletes another super_admin.
+func (s *UserService) DeleteUser(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+	transferTo string,
+) error {
+	if !isAdminCapable(currentUser) {
+		return ErrForbidden
+	}
+
 	if id == "" {
 		return errors.New("id is required")
 	}
@@ -97,9 +203,66 @@ func (s *UserService) DeleteUser(ctx context.Context, id string) error {
 	if err != nil {
 		return errors.New("invalid user id")
 	}
+	if objID == currentUser.ID {
+		return ErrCannotDeleteSelf
+	}
+
+	newOwnerID := primitive.NilObjectID
+	if transferTo != "" {
+		newOwnerID, err = primitive.ObjectIDFromHex(transferTo)
+		if err != nil {
+			return errors.New("invalid transferTo user id")
+		}
+		if newOwnerID == objID {
+			return errors.New("cannot transfer ownership to the user being deleted")
+		}
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+
+		user, err := s.repo.FindByID(ctx, objID)
+		if err != nil {
+			return err
+		}
+		if user.Role == models.RoleSuperAdmin && currentUser.Role != models.RoleSuperAdmin {
+			return ErrForbidden
+		}
+
+		owned, err := s.projectService.countOwnedProjects(ctx, objID)
+		if err != nil {
+			return err
+		}
+
+		if owned > 0 {
+			if newOwnerID == primitive.NilObjectID {
+				return fmt.Errorf("%w (%d owned)", ErrUserOwnsProjects, owned)
+			}
+
+			newOwner, err := s.repo.FindByID(ctx, newOwnerID)
+			if err != nil {
+				return errors.New("transferTo user not found")
+			}
+			if !isAdminCapable(newOwner) {
+				return errors.New("transferTo user must be an admin or super_admin")
+			}
+
+			if err := s.projectService.transferOwnership(ctx, objID, newOwnerID); err != nil {
+				return err
+			}
+		}
+
+		if err := s.projectService.removeMemberEverywhere(ctx, objID); err != nil {
+			return err
+		}
 
-	return s.repo.DeleteByID(ctx, objID)
+		if err := s.taskService.unassignUser(ctx, objID); err != nil {
+			return err
+		}
+
+		return s.repo.DeleteByID(ctx, objID, currentUser.ID)
+	})
 }
+
 func (s *UserService) GetUsersUnderAdmin(
 	ctx context.Context,
 	adminID primitive.ObjectID,
@@ -149,6 +312,17 @@ func (s *UserService) GetUserByUserID(
 ) (*models.User, error) {
 	return s.repo.FindByUserID(ctx, userID)
 }
+
+// CheckPassword reports whether password matches the user's credentials.
+// Users without a password hash, created before passwords existed, cannot
+// log in until an admin sets one with SetPassword.
+func (s *UserService) CheckPassword(user *models.User, password string) bool {
+	if user.PasswordHash == "" {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) == nil
+}
+
 func (s *UserService) GetUserByObjectID(
 	ctx context.Context,
 	id primitive.ObjectID,
@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrUserSuspended         = errors.New("user account is suspended")
+	ErrUserDeactivated       = errors.New("user account is deactivated")
+	ErrSuccessorRequired     = errors.New("user has open tasks or owned projects; pass successorId to hand them over")
+	ErrStatusUnchanged       = errors.New("user already has this status")
+	ErrCannotChangeOwnStatus = errors.New("you cannot change your own status")
+)
+
+// CheckUserActive is called on login and on every authenticated request.
+func CheckUserActive(user *models.User) error {
+	switch user.Status {
+	case models.UserStatusSuspended:
+		return ErrUserSuspended
+	case models.UserStatusDeactivated:
+		return ErrUserDeactivated
+	}
+	return nil
+}
+
+func (s *UserService) GetUsersByStatus(ctx context.Context, status string) ([]models.User, error) {
+	switch status {
+	case models.UserStatusActive, models.UserStatusSuspended, models.UserStatusDeactivated:
+	default:
+		errs := &ValidationError{}
+		errs.add("status", "must be one of active, suspended, deactivated")
+		return nil, errs
+	}
+	return s.repo.FindByStatus(ctx, status)
+}
+
+// =====================
+// SUSPEND / REACTIVATE
+// =====================
+// Suspending keeps all of a user's work in place; it only locks them out.
+func (s *UserService) SuspendUser(ctx context.Context, currentUser *models.User, id string) error {
+	user, err := s.loadStatusTarget(ctx, currentUser, id)
+	if err != nil {
+		return err
+	}
+	if user.Status == models.UserStatusSuspended {
+		return ErrStatusUnchanged
+	}
+	return s.setStatus(ctx, user.ID, models.UserStatusSuspended)
+}
+
+func (s *UserService) ReactivateUser(ctx context.Context, currentUser *models.User, id string) error {
+	user, err := s.loadStatusTarget(ctx, currentUser, id)
+	if err != nil {
+		return err
+	}
+	if user.IsActive() {
+		return ErrStatusUnchanged
+	}
+	return s.setStatus(ctx, user.ID, models.UserStatusActive)
+}
+
+// =====================
+// DEACTIVATE
+// =====================
+// PlanDeactivation shows what DeactivateUser would hand over, without
+// changing anything. successorID is optional here.
+func (s *UserService) PlanDeactivation(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+	successorID string,
+) (*dto.DeactivationPlan, error) {
+
+	user, err := s.loadStatusTarget(ctx, currentUser, id)
+	if err != nil {
+		return nil, err
+	}
+
+	successor, err := s.loadSuccessor(ctx, user, successorID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildDeactivationPlan(ctx, user, successor)
+}
+
+// DeactivateUser locks the user out and, in a single transaction, hands
+// their work to successorID:
+//
+//	user → owned projects: successor becomes owner, user stays as maintainer
+//	user → open tasks: reassigned to successor
+//	successor → projects of those tasks: joins as contributor if needed
+//
+// Done tasks keep their assignee for the record. Memberships are kept so
+// that a reactivated user finds everything as before. Archived projects
+// are read-only and left alone, along with the tasks in them.
+func (s *UserService) DeactivateUser(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+	successorID string,
+) (*dto.DeactivationPlan, error) {
+
+	user, err := s.loadStatusTarget(ctx, currentUser, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status == models.UserStatusDeactivated {
+		return nil, ErrStatusUnchanged
+	}
+
+	successor, err := s.loadSuccessor(ctx, user, successorID)
+	if err != nil {
+		return nil, err
+	}
+
+	var plan *dto.DeactivationPlan
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		plan, err = s.buildDeactivationPlan(ctx, user, successor)
+		if err != nil {
+			return err
+		}
+
+		if len(plan.OpenTasks) > 0 || len(plan.OwnedProjects) > 0 {
+			if successor == nil {
+				return ErrSuccessorRequired
+			}
+
+			for _, project := range plan.OwnedProjects {
+				if err := s.projectService.handOver(ctx, project.ID, user.ID, successor.ID); err != nil {
+					return err
+				}
+			}
+
+			for _, hex := range plan.ProjectsToJoin {
+				projectID, _ := primitive.ObjectIDFromHex(hex)
+				if err := s.projectService.joinAsContributor(ctx, projectID, successor.ID); err != nil {
+					return err
+				}
+			}
+
+			if err := s.taskService.reassignOpenTasks(ctx, user.ID, successor.ID, taskProjectIDs(plan.OpenTasks)); err != nil {
+				return err
+			}
+		}
+
+		return s.setStatus(ctx, user.ID, models.UserStatusDeactivated)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+func (s *UserService) buildDeactivationPlan(
+	ctx context.Context,
+	user *models.User,
+	successor *models.User,
+) (*dto.DeactivationPlan, error) {
+
+	owned, err := s.projectService.ownedProjects(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.taskService.openTasksOf(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &dto.DeactivationPlan{
+		UserID:         user.ID.Hex(),
+		OpenTasks:      tasks,
+		OwnedProjects:  owned,
+		ProjectsToJoin: []string{},
+	}
+	if plan.OwnedProjects == nil {
+		plan.OwnedProjects = []models.Project{}
+	}
+
+	if successor == nil {
+		return plan, nil
+	}
+	plan.SuccessorID = successor.ID.Hex()
+
+	if len(owned) > 0 && !isAdminCapable(successor) {
+		errs := &ValidationError{}
+		errs.add("successorId", "must be an admin or super_admin to take over owned projects")
+		return nil, errs
+	}
+
+	ownedIDs := make(map[primitive.ObjectID]bool, len(owned))
+	for _, p := range owned {
+		ownedIDs[p.ID] = true
+	}
+
+	checked := make(map[primitive.ObjectID]bool)
+	for _, task := range tasks {
+		if checked[task.ProjectID] || ownedIDs[task.ProjectID] {
+			continue
+		}
+		checked[task.ProjectID] = true
+
+		project, err := s.projectService.findProject(ctx, task.ProjectID)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				continue
+			}
+			return nil, err
+		}
+		if !isProjectMemberUser(project, successor) {
+			plan.ProjectsToJoin = append(plan.ProjectsToJoin, project.ID.Hex())
+		}
+	}
+
+	return plan, nil
+}
+
+// taskProjectIDs lists the distinct projects of tasks.
+func taskProjectIDs(tasks []models.Task) []primitive.ObjectID {
+	seen := make(map[primitive.ObjectID]bool)
+	ids := []primitive.ObjectID{}
+	for _, task := range tasks {
+		if !seen[task.ProjectID] {
+			seen[task.ProjectID] = true
+			ids = append(ids, task.ProjectID)
+		}
+	}
+	return ids
+}
+
+// loadStatusTarget loads the user whose status is about to change. Admins
+// change statuses, but never their own and only a super_admin can change a
+// super_admin's.
+func (s *UserService) loadStatusTarget(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+) (*models.User, error) {
+
+	if !isAdminCapable(currentUser) {
+		return nil, ErrForbidden
+	}
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("invalid user id")
+	}
+	if objID == currentUser.ID {
+		return nil, ErrCannotChangeOwnStatus
+	}
+
+	user, err := s.repo.FindByID(ctx, objID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role == models.RoleSuperAdmin && currentUser.Role != models.RoleSuperAdmin {
+		return nil, ErrForbidden
+	}
+
+	return user, nil
+}
+
+func (s *UserService) loadSuccessor(
+	ctx context.Context,
+	user *models.User,
+	successorID string,
+) (*models.User, error) {
+
+	if successorID == "" {
+		return nil, nil
+	}
+
+	errs := &ValidationError{}
+
+	objID, err := primitive.ObjectIDFromHex(successorID)
+	if err != nil {
+		errs.add("successorId", "must be a valid ObjectID")
+		return nil, errs
+	}
+	if objID == user.ID {
+		errs.add("successorId", "cannot be the user being deactivated")
+		return nil, errs
+	}
+
+	successor, err := s.repo.FindByID(ctx, objID)
+	if err != nil {
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, err
+		}
+		errs.add("successorId", "user not found")
+		return nil, errs
+	}
+	if !successor.IsActive() {
+		errs.add("successorId", "user is "+successor.Status)
+		return nil, errs
+	}
+
+	return successor, nil
+}
+
+func (s *UserService) setStatus(ctx context.Context, id primitive.ObjectID, status string) error {
+	return s.repo.UpdateByID(ctx, id, bson.M{
+		"status":          status,
+		"statusChangedAt": time.Now(),
+	})
+}
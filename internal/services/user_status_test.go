@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type deactivationFixture struct {
+	admin, user, successor *models.User
+
+	owned, ownedArchived, other, otherArchived *models.Project
+
+	users    *fakeUserRepository
+	projects *fakeProjectRepository
+	tasks    *fakeTaskRepository
+	service  *UserService
+}
+
+// newDeactivationFixture gives user an owned project and a project they
+// work in, each once live and once archived, with an open task in every
+// one of them.
+func newDeactivationFixture() *deactivationFixture {
+	f := &deactivationFixture{
+		admin:     &models.User{ID: primitive.NewObjectID(), Role: models.RoleSuperAdmin},
+		user:      &models.User{ID: primitive.NewObjectID(), Role: models.RoleAdmin},
+		successor: &models.User{ID: primitive.NewObjectID(), Role: models.RoleAdmin},
+	}
+
+	archivedAt := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	f.owned = &models.Project{ID: primitive.NewObjectID(), OwnerID: f.user.ID}
+	f.ownedArchived = &models.Project{ID: primitive.NewObjectID(), OwnerID: f.user.ID, ArchivedAt: &archivedAt}
+	f.other = &models.Project{ID: primitive.NewObjectID(), OwnerID: f.admin.ID, MemberIDs: []primitive.ObjectID{f.user.ID}}
+	f.otherArchived = &models.Project{ID: primitive.NewObjectID(), OwnerID: f.admin.ID, MemberIDs: []primitive.ObjectID{f.user.ID}, ArchivedAt: &archivedAt}
+
+	f.users = newFakeUserRepository(f.admin, f.user, f.successor)
+	f.projects = &fakeProjectRepository{projects: []*models.Project{f.owned, f.ownedArchived, f.other, f.otherArchived}}
+	f.tasks = &fakeTaskRepository{}
+	for _, p := range f.projects.projects {
+		f.tasks.tasks = append(f.tasks.tasks, &models.Task{
+			ID:         primitive.NewObjectID(),
+			ProjectID:  p.ID,
+			Status:     models.TaskStatusInProgress,
+			AssignedTo: f.user.ID,
+		})
+	}
+
+	projectService := NewProjectService(f.projects, f.tasks, f.users, nil, fakeUnitOfWork{})
+	taskService := NewTaskService(f.tasks, f.projects, f.users, nil, nil, nil, fakeUnitOfWork{})
+	f.service = NewUserService(f.users, projectService, taskService, fakeUnitOfWork{})
+	return f
+}
+
+// Archived projects are read-only, so deactivating a user leaves them and
+// their tasks alone.
+func TestDeactivateUserSkipsArchivedProjects(t *testing.T) {
+	f := newDeactivationFixture()
+
+	plan, err := f.service.DeactivateUser(context.Background(), f.admin, f.user.ID.Hex(), f.successor.ID.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plan.OwnedProjects) != 1 || plan.OwnedProjects[0].ID != f.owned.ID {
+		t.Errorf("plan owns %d projects, want only the live one", len(plan.OwnedProjects))
+	}
+	for _, task := range plan.OpenTasks {
+		if task.ProjectID == f.ownedArchived.ID || task.ProjectID == f.otherArchived.ID {
+			t.Errorf("plan hands over a task of an archived project")
+		}
+	}
+	if len(plan.OpenTasks) != 2 {
+		t.Errorf("plan hands over %d tasks, want 2", len(plan.OpenTasks))
+	}
+
+	if len(f.projects.owners) != 1 || f.projects.owners[f.owned.ID] != f.successor.ID {
+		t.Errorf("owners changed = %v, want only the live owned project", f.projects.owners)
+	}
+	if len(f.projects.joined) != 1 || len(f.projects.joined[f.other.ID]) != 1 {
+		t.Errorf("joined = %v, want the successor in the live project only", f.projects.joined)
+	}
+
+	got := idStrings(f.tasks.reassignedIn)
+	want := idStrings([]primitive.ObjectID{f.owned.ID, f.other.ID})
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("tasks reassigned in %v, want %v", got, want)
+	}
+
+	if f.users.updates[f.user.ID]["status"] != models.UserStatusDeactivated {
+		t.Errorf("user update = %v, want deactivated", f.users.updates[f.user.ID])
+	}
+}
+
+func TestDeactivateUserWithOnlyArchivedWorkNeedsNoSuccessor(t *testing.T) {
+	f := newDeactivationFixture()
+	f.owned.ArchivedAt = f.ownedArchived.ArchivedAt
+	f.other.ArchivedAt = f.otherArchived.ArchivedAt
+
+	plan, err := f.service.DeactivateUser(context.Background(), f.admin, f.user.ID.Hex(), "")
+	if errors.Is(err, ErrSuccessorRequired) {
+		t.Fatal("a successor was required for archived work only")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.OpenTasks) != 0 || len(plan.OwnedProjects) != 0 {
+		t.Errorf("plan = %+v, want nothing to hand over", plan)
+	}
+	if len(f.tasks.reassignedIn) != 0 {
+		t.Errorf("tasks reassigned in %v", f.tasks.reassignedIn)
+	}
+}
+
+func idStrings(ids []primitive.ObjectID) []string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = id.Hex()
+	}
+	sort.Strings(s)
+	return s
+}
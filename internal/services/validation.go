@@ -0,0 +1,160 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FieldError describes one invalid field of a request body.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError collects every FieldError found while validating a single
+// create or update request, so clients can fix them all in one round trip.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		parts = append(parts, f.Field+": "+f.Message)
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) add(field, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+}
+
+// errOrNil returns e only if it holds at least one field error.
+func (e *ValidationError) errOrNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+func isAdminCapable(user *models.User) bool {
+	return user.Role == models.RoleAdmin || user.Role == models.RoleSuperAdmin
+}
+
+// Generic updates arrive as decoded JSON, so ObjectIDs and dates are still
+// strings. The helpers below convert a field in place and record a field
+// error when it cannot be converted.
+
+func objectIDField(update bson.M, field string, errs *ValidationError) (primitive.ObjectID, bool) {
+	value, ok := update[field]
+	if !ok {
+		return primitive.NilObjectID, false
+	}
+
+	switch v := value.(type) {
+	case primitive.ObjectID:
+		return v, true
+	case string:
+		if v == "" {
+			update[field] = primitive.NilObjectID
+			return primitive.NilObjectID, true
+		}
+		id, err := primitive.ObjectIDFromHex(v)
+		if err != nil {
+			errs.add(field, "must be a valid ObjectID")
+			return primitive.NilObjectID, false
+		}
+		update[field] = id
+		return id, true
+	default:
+		errs.add(field, "must be a valid ObjectID")
+		return primitive.NilObjectID, false
+	}
+}
+
+func timeField(update bson.M, field string, errs *ValidationError) (time.Time, bool) {
+	value, ok := update[field]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		errs.add(field, "must be an RFC 3339 timestamp")
+		return time.Time{}, false
+	}
+
+	parsed, err := time.Parse(time.RFC3339, str)
+	if err != nil {
+		errs.add(field, "must be an RFC 3339 timestamp")
+		return time.Time{}, false
+	}
+
+	update[field] = parsed
+	return parsed, true
+}
+
+// stringListField converts a JSON array of strings in place.
+func stringListField(update bson.M, field string, errs *ValidationError) ([]string, bool) {
+	value, ok := update[field]
+	if !ok {
+		return nil, false
+	}
+
+	items, ok := value.([]interface{})
+	if !ok {
+		errs.add(field, "must be a list of strings")
+		return nil, false
+	}
+
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		str, ok := item.(string)
+		if !ok {
+			errs.add(field, "must be a list of strings")
+			return nil, false
+		}
+		list = append(list, str)
+	}
+
+	update[field] = list
+	return list, true
+}
+
+const (
+	maxLabels      = 20
+	maxLabelLength = 50
+)
+
+// normalizeLabels trims and de-duplicates labels, keeping their order.
+func normalizeLabels(labels []string, field string, errs *ValidationError) []string {
+	seen := make(map[string]bool, len(labels))
+	result := make([]string, 0, len(labels))
+
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		switch {
+		case label == "":
+			errs.add(field, "labels cannot be empty")
+			continue
+		case len(label) > maxLabelLength:
+			errs.add(field, "labels are limited to 50 characters")
+			continue
+		case seen[label]:
+			continue
+		}
+		seen[label] = true
+		result = append(result, label)
+	}
+
+	if len(result) > maxLabels {
+		errs.add(field, "at most 20 labels are allowed")
+	}
+
+	return result
+}
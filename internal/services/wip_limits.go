@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// WIPLimitError is returned when a write would put more tasks in a column,
+// or in one assignee's share of a column, than the project allows.
+type WIPLimitError struct {
+	Status      string
+	Limit       int
+	PerAssignee bool
+}
+
+func (e *WIPLimitError) Error() string {
+	if e.PerAssignee {
+		return fmt.Sprintf("WIP limit reached: at most %d tasks per assignee in %q", e.Limit, e.Status)
+	}
+	return fmt.Sprintf("WIP limit reached: at most %d tasks in %q", e.Limit, e.Status)
+}
+
+// wipCheck describes where a task ends up after a write. EntersColumn is
+// set when the task was not in this column before (create, status or
+// project change); AssigneeChanged when it stays but changes hands.
+type wipCheck struct {
+	ProjectID       primitive.ObjectID
+	Status          string
+	Assignee        primitive.ObjectID
+	EntersColumn    bool
+	AssigneeChanged bool
+}
+
+func wipLimitFor(project *models.Project, status string) *models.WIPLimit {
+	for i := range project.WIPLimits {
+		if project.WIPLimits[i].Status == status {
+			return &project.WIPLimits[i]
+		}
+	}
+	return nil
+}
+
+// applies reports whether limit constrains the write described by c.
+func (c wipCheck) applies(limit *models.WIPLimit) (column, perAssignee bool) {
+	if limit == nil {
+		return false, false
+	}
+	column = c.EntersColumn && limit.Max > 0
+	perAssignee = (c.EntersColumn || c.AssigneeChanged) &&
+		limit.MaxPerAssignee > 0 &&
+		c.Assignee != primitive.NilObjectID
+	return column, perAssignee
+}
+
+// withinWIPLimits runs write, first making sure it does not exceed the
+// target column's WIP limits. The check and the write share a transaction
+// that starts by locking the project document, so concurrent writes to the
+// same project are serialized and cannot both take the last free slot.
+func (s *TaskService) withinWIPLimits(
+	ctx context.Context,
+	project *models.Project,
+	c wipCheck,
+	write func(ctx context.Context) error,
+) error {
+
+	if column, perAssignee := c.applies(wipLimitFor(project, c.Status)); !column && !perAssignee {
+		return write(ctx)
+	}
+
+	return s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.projectRepo.Lock(ctx, c.ProjectID); err != nil {
+			return err
+		}
+
+		// Re-read the limits under the lock; they may have just changed.
+		locked, err := s.projectRepo.FindByID(ctx, c.ProjectID)
+		if err != nil {
+			return err
+		}
+
+		limit := wipLimitFor(locked, c.Status)
+		column, perAssignee := c.applies(limit)
+
+		if column {
+			count, err := s.repo.CountInColumn(ctx, c.ProjectID, c.Status)
+			if err != nil {
+				return err
+			}
+			if count >= int64(limit.Max) {
+				return &WIPLimitError{Status: c.Status, Limit: limit.Max}
+			}
+		}
+
+		if perAssignee {
+			count, err := s.repo.CountInColumnForAssignee(ctx, c.ProjectID, c.Status, c.Assignee)
+			if err != nil {
+				return err
+			}
+			if count >= int64(limit.MaxPerAssignee) {
+				return &WIPLimitError{Status: c.Status, Limit: limit.MaxPerAssignee, PerAssignee: true}
+			}
+		}
+
+		return write(ctx)
+	})
+}
+
+// wipUtilization reports every status column of the project, in board
+// order, with its task counts and configured limits.
+func wipUtilization(
+	ctx context.Context,
+	taskRepo repositories.TaskRepository,
+	project *models.Project,
+) ([]dto.WIPUtilization, error) {
+
+	counts, err := taskRepo.CountByStatusAndAssignee(ctx, project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	byStatus := make(map[string]*dto.WIPUtilization, len(models.TaskStatuses))
+	result := make([]dto.WIPUtilization, len(models.TaskStatuses))
+
+	for i, status := range models.TaskStatuses {
+		result[i].Status = status
+		if limit := wipLimitFor(project, status); limit != nil {
+			result[i].Max = limit.Max
+			result[i].MaxPerAssignee = limit.MaxPerAssignee
+		}
+		byStatus[status] = &result[i]
+	}
+
+	for _, c := range counts {
+		u, ok := byStatus[c.Status]
+		if !ok {
+			continue
+		}
+		u.Count += c.Count
+		if c.AssignedTo == primitive.NilObjectID {
+			continue
+		}
+		if u.PerAssignee == nil {
+			u.PerAssignee = make(map[string]int)
+		}
+		u.PerAssignee[c.AssignedTo.Hex()] += c.Count
+	}
+
+	return result, nil
+}
+
+// =====================
+// CONFIGURE
+// =====================
+
+// SetWIPLimits replaces the project's WIP limits. Lowering a limit below
+// the current count is allowed; it only blocks tasks from entering the
+// column until it drains.
+func (s *ProjectService) SetWIPLimits(
+	ctx context.Context,
+	currentUser *models.User,
+	id string,
+	limits []models.WIPLimit,
+) (*dto.ProjectDetail, error) {
+
+	project, err := s.loadProject(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := authorizeProject(currentUser, project, actionUpdateProject); err != nil {
+		return nil, err
+	}
+
+	errs := &ValidationError{}
+	seen := make(map[string]bool, len(limits))
+	kept := make([]models.WIPLimit, 0, len(limits))
+
+	for i, limit := range limits {
+		field := fmt.Sprintf("wipLimits[%d]", i)
+
+		if !validTaskStatuses[limit.Status] {
+			errs.add(field+".status", "must be one of Todo, In Progress, Done")
+		} else if seen[limit.Status] {
+			errs.add(field+".status", "is listed more than once")
+		}
+		seen[limit.Status] = true
+
+		if limit.Max < 0 {
+			errs.add(field+".max", "cannot be negative")
+		}
+		if limit.MaxPerAssignee < 0 {
+			errs.add(field+".maxPerAssignee", "cannot be negative")
+		}
+
+		if limit.Max > 0 || limit.MaxPerAssignee > 0 {
+			kept = append(kept, limit)
+		}
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return nil, err
+	}
+
+	// Lock like task writes do, so a limit change and a task entering the
+	// column are not interleaved.
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.Lock(ctx, project.ID); err != nil {
+			return err
+		}
+		return s.repo.SetWIPLimits(ctx, project.ID, kept)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	project.WIPLimits = kept
+	return s.projectDetail(ctx, project)
+}
+
+func (s *ProjectService) projectDetail(ctx context.Context, project *models.Project) (*dto.ProjectDetail, error) {
+	utilization, err := wipUtilization(ctx, s.taskRepo, project)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ProjectDetail{Project: project, WIPUtilization: utilization}, nil
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestWIPCheckApplies(t *testing.T) {
+	assignee := primitive.NewObjectID()
+	both := &models.WIPLimit{Status: models.TaskStatusInProgress, Max: 3, MaxPerAssignee: 1}
+
+	tests := []struct {
+		name            string
+		check           wipCheck
+		limit           *models.WIPLimit
+		wantColumn      bool
+		wantPerAssignee bool
+	}{
+		{"no limit", wipCheck{EntersColumn: true, Assignee: assignee}, nil, false, false},
+		{"enters column", wipCheck{EntersColumn: true, Assignee: assignee}, both, true, true},
+		{"enters column unassigned", wipCheck{EntersColumn: true}, both, true, false},
+		{"changes hands", wipCheck{AssigneeChanged: true, Assignee: assignee}, both, false, true},
+		{"stays put", wipCheck{Assignee: assignee}, both, false, false},
+		{"column limit only", wipCheck{EntersColumn: true, Assignee: assignee}, &models.WIPLimit{Max: 3}, true, false},
+		{"assignee limit only", wipCheck{EntersColumn: true, Assignee: assignee}, &models.WIPLimit{MaxPerAssignee: 1}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			column, perAssignee := tt.check.applies(tt.limit)
+			if column != tt.wantColumn || perAssignee != tt.wantPerAssignee {
+				t.Errorf("applies = %v, %v; want %v, %v", column, perAssignee, tt.wantColumn, tt.wantPerAssignee)
+			}
+		})
+	}
+}
+
+func TestWithinWIPLimits(t *testing.T) {
+	alice := primitive.NewObjectID()
+	bob := primitive.NewObjectID()
+
+	tests := []struct {
+		name    string
+		limit   models.WIPLimit
+		check   wipCheck
+		wantErr *WIPLimitError
+	}{
+		{
+			name:  "room in column",
+			limit: models.WIPLimit{Status: models.TaskStatusInProgress, Max: 3},
+			check: wipCheck{Status: models.TaskStatusInProgress, EntersColumn: true},
+		},
+		{
+			name:    "column full",
+			limit:   models.WIPLimit{Status: models.TaskStatusInProgress, Max: 2},
+			check:   wipCheck{Status: models.TaskStatusInProgress, EntersColumn: true},
+			wantErr: &WIPLimitError{Status: models.TaskStatusInProgress, Limit: 2},
+		},
+		{
+			name:  "already in column",
+			limit: models.WIPLimit{Status: models.TaskStatusInProgress, Max: 2},
+			check: wipCheck{Status: models.TaskStatusInProgress},
+		},
+		{
+			name:    "assignee full",
+			limit:   models.WIPLimit{Status: models.TaskStatusInProgress, MaxPerAssignee: 1},
+			check:   wipCheck{Status: models.TaskStatusInProgress, Assignee: alice, AssigneeChanged: true},
+			wantErr: &WIPLimitError{Status: models.TaskStatusInProgress, Limit: 1, PerAssignee: true},
+		},
+		{
+			name:  "other assignee has room",
+			limit: models.WIPLimit{Status: models.TaskStatusInProgress, MaxPerAssignee: 1},
+			check: wipCheck{Status: models.TaskStatusInProgress, Assignee: bob, AssigneeChanged: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := &models.Project{ID: primitive.NewObjectID(), WIPLimits: []models.WIPLimit{tt.limit}}
+			repo := &fakeTaskRepository{tasks: []*models.Task{
+				{ID: primitive.NewObjectID(), ProjectID: project.ID, Status: models.TaskStatusInProgress, AssignedTo: alice},
+				{ID: primitive.NewObjectID(), ProjectID: project.ID, Status: models.TaskStatusInProgress},
+				{ID: primitive.NewObjectID(), ProjectID: project.ID, Status: models.TaskStatusTodo, AssignedTo: bob},
+			}}
+			projects := &fakeProjectRepository{projects: []*models.Project{project}}
+			service := NewTaskService(repo, projects, nil, nil, nil, nil, fakeUnitOfWork{})
+
+			tt.check.ProjectID = project.ID
+			written := false
+			err := service.withinWIPLimits(context.Background(), project, tt.check, func(ctx context.Context) error {
+				written = true
+				return nil
+			})
+
+			if tt.wantErr == nil {
+				if err != nil || !written {
+					t.Fatalf("err = %v, written = %v; want the write to go through", err, written)
+				}
+				return
+			}
+			var wipErr *WIPLimitError
+			if !errors.As(err, &wipErr) || *wipErr != *tt.wantErr {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if written {
+				t.Error("write ran despite the limit")
+			}
+		})
+	}
+}
+
+func TestWithinWIPLimitsRereadsLimitsUnderLock(t *testing.T) {
+	stored := &models.Project{
+		ID:        primitive.NewObjectID(),
+		WIPLimits: []models.WIPLimit{{Status: models.TaskStatusInProgress, Max: 5}},
+	}
+	stale := *stored
+	stale.WIPLimits = []models.WIPLimit{{Status: models.TaskStatusInProgress, Max: 1}}
+
+	repo := &fakeTaskRepository{tasks: []*models.Task{
+		{ID: primitive.NewObjectID(), ProjectID: stored.ID, Status: models.TaskStatusInProgress},
+	}}
+	projects := &fakeProjectRepository{projects: []*models.Project{stored}}
+	service := NewTaskService(repo, projects, nil, nil, nil, nil, fakeUnitOfWork{})
+
+	check := wipCheck{ProjectID: stored.ID, Status: models.TaskStatusInProgress, EntersColumn: true}
+	err := service.withinWIPLimits(context.Background(), &stale, check, func(ctx context.Context) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("err = %v, want the raised limit to be used", err)
+	}
+}
+
+func TestWIPUtilization(t *testing.T) {
+	alice := primitive.NewObjectID()
+	project := &models.Project{
+		ID:        primitive.NewObjectID(),
+		WIPLimits: []models.WIPLimit{{Status: models.TaskStatusInProgress, Max: 4, MaxPerAssignee: 2}},
+	}
+	repo := &fakeTaskRepository{counts: []repositories.ColumnCount{
+		{Status: models.TaskStatusInProgress, AssignedTo: alice, Count: 2},
+		{Status: models.TaskStatusInProgress, Count: 1},
+		{Status: models.TaskStatusDone, AssignedTo: alice, Count: 7},
+		{Status: "Archived", Count: 3},
+	}}
+
+	got, err := wipUtilization(context.Background(), repo, project)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []dto.WIPUtilization{
+		{Status: models.TaskStatusTodo},
+		{Status: models.TaskStatusInProgress, Count: 3, Max: 4, MaxPerAssignee: 2, PerAssignee: map[string]int{alice.Hex(): 2}},
+		{Status: models.TaskStatusDone, Count: 7, PerAssignee: map[string]int{alice.Hex(): 7}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wipUtilization =\n%+v\nwant\n%+v", got, want)
+	}
+}
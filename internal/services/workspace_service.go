@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"Concurrent_Task_Management_System/internal/dto"
+	"Concurrent_Task_Management_System/internal/models"
+	"Concurrent_Task_Management_System/internal/repositories"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrWorkspaceNotFound  = errors.New("workspace not found")
+	ErrWorkspaceSlugTaken = errors.New("workspace slug is already taken")
+	ErrSignupDisabled     = errors.New("workspace sign-up is disabled; ask an operator to create the workspace")
+)
+
+var workspaceSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,38}[a-z0-9]$`)
+
+type WorkspaceService struct {
+	repo        repositories.WorkspaceRepository
+	userService *UserService
+	uow         repositories.UnitOfWork
+	signupOpen  bool
+}
+
+func NewWorkspaceService(
+	repo repositories.WorkspaceRepository,
+	userService *UserService,
+	uow repositories.UnitOfWork,
+	signupOpen bool,
+) *WorkspaceService {
+	return &WorkspaceService{
+		repo:        repo,
+		userService: userService,
+		uow:         uow,
+		signupOpen:  signupOpen,
+	}
+}
+
+// WithWorkspace scopes every repository call made with the returned context
+// to one workspace.
+func WithWorkspace(ctx context.Context, workspaceID primitive.ObjectID) context.Context {
+	return repositories.WithTenant(ctx, workspaceID)
+}
+
+// EnsureDefaultWorkspace creates the default workspace on first start and
+// moves every document that predates workspaces into it.
+func (s *WorkspaceService) EnsureDefaultWorkspace(ctx context.Context) (*models.Workspace, int64, error) {
+	workspace, err := s.repo.FindBySlug(ctx, models.DefaultWorkspaceSlug)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		workspace = &models.Workspace{
+			Name:      "Default",
+			Slug:      models.DefaultWorkspaceSlug,
+			CreatedAt: time.Now(),
+		}
+		err = s.repo.Create(ctx, workspace)
+		if mongo.IsDuplicateKeyError(err) {
+			workspace, err = s.repo.FindBySlug(ctx, models.DefaultWorkspaceSlug)
+		}
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	adopted, err := s.repo.AdoptOrphans(ctx, workspace.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return workspace, adopted, nil
+}
+
+// ResolveWorkspace looks a workspace up by slug for login. An empty slug
+// means the default workspace.
+func (s *WorkspaceService) ResolveWorkspace(ctx context.Context, slug string) (*models.Workspace, error) {
+	if slug == "" {
+		slug = models.DefaultWorkspaceSlug
+	}
+
+	workspace, err := s.repo.FindBySlug(ctx, strings.ToLower(slug))
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrWorkspaceNotFound
+	}
+	return workspace, err
+}
+
+// SignUp is the public, unauthenticated way to create a workspace. It is
+// only available when sign-up is open.
+func (s *WorkspaceService) SignUp(
+	ctx context.Context,
+	workspace *models.Workspace,
+	admin *models.User,
+	password string,
+) (*models.Workspace, *models.User, error) {
+
+	if !s.signupOpen {
+		return nil, nil, ErrSignupDisabled
+	}
+	return s.CreateWorkspace(ctx, workspace, admin, password)
+}
+
+// CreateWorkspace creates a new workspace together with its first user, who
+// becomes the workspace's super_admin and logs in with password.
+func (s *WorkspaceService) CreateWorkspace(
+	ctx context.Context,
+	workspace *models.Workspace,
+	admin *models.User,
+	password string,
+) (*models.Workspace, *models.User, error) {
+
+	workspace.Name = strings.TrimSpace(workspace.Name)
+	workspace.Slug = strings.ToLower(strings.TrimSpace(workspace.Slug))
+
+	errs := &ValidationError{}
+
+	if workspace.Name == "" {
+		errs.add("name", "is required")
+	}
+	if !workspaceSlugPattern.MatchString(workspace.Slug) {
+		errs.add("slug", "must be 3-40 lowercase letters, digits or dashes")
+	}
+	if admin == nil {
+		errs.add("admin", "is required")
+	}
+	if len(password) < minPasswordLength {
+		errs.add("password", fmt.Sprintf("must be at least %d characters", minPasswordLength))
+	}
+
+	if err := errs.errOrNil(); err != nil {
+		return nil, nil, err
+	}
+
+	hash, err := HashPassword(password)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	workspace.ID = primitive.NilObjectID
+	workspace.CreatedAt = time.Now()
+	admin.Role = models.RoleSuperAdmin
+	admin.PasswordHash = hash
+
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, workspace); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return ErrWorkspaceSlugTaken
+			}
+			return err
+		}
+
+		_, err := s.userService.createUser(WithWorkspace(ctx, workspace.ID), admin)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return workspace, admin, nil
+}
+
+// GetCurrentWorkspace returns the workspace the caller belongs to.
+func (s *WorkspaceService) GetCurrentWorkspace(
+	ctx context.Context,
+	currentUser *models.User,
+) (*models.Workspace, error) {
+
+	workspace, err := s.repo.FindByID(ctx, currentUser.WorkspaceID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrWorkspaceNotFound
+	}
+	return workspace, err
+}
+
+// GetAllWorkspaces lists every workspace, oldest first.
+func (s *WorkspaceService) GetAllWorkspaces(ctx context.Context) ([]models.Workspace, error) {
+	return s.repo.FindAll(ctx)
+}
+
+// GetStats counts the users, projects and tasks of one workspace.
+func (s *WorkspaceService) GetStats(ctx context.Context, workspace *models.Workspace) (*dto.WorkspaceStats, error) {
+	stats := &dto.WorkspaceStats{Workspace: *workspace}
+
+	var err error
+	if stats.UsersByRole, err = s.repo.CountBy(ctx, workspace.ID, "users", "role"); err != nil {
+		return nil, err
+	}
+	if stats.UsersByStatus, err = s.repo.CountBy(ctx, workspace.ID, "users", "status"); err != nil {
+		return nil, err
+	}
+	if stats.TasksByStatus, err = s.repo.CountBy(ctx, workspace.ID, "tasks", "status"); err != nil {
+		return nil, err
+	}
+	if stats.Documents, err = s.repo.CountDocuments(ctx, workspace.ID); err != nil {
+		return nil, err
+	}
+
+	// Users created before statuses existed count as active.
+	if n, ok := stats.UsersByStatus[""]; ok {
+		stats.UsersByStatus[models.UserStatusActive] += n
+		delete(stats.UsersByStatus, "")
+	}
+
+	projects, err := s.userService.projectService.GetAllProjects(WithWorkspace(ctx, workspace.ID), true)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range projects {
+		if p.ArchivedAt != nil {
+			stats.ArchivedProjects++
+		} else {
+			stats.ActiveProjects++
+		}
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,80 @@
+package utils
+
+import "strings"
+
+// Task ranks are base-36 strings compared lexicographically. A new rank can
+// always be generated between two existing ones, so moving a card on the
+// board only ever rewrites the moved task.
+const rankAlphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// RankBetween returns a rank strictly between prev and next. An empty prev
+// means "before everything" and an empty next means "after everything".
+// Ranks produced here never end in '0', which keeps that guarantee intact
+// for all future calls.
+func RankBetween(prev, next string) string {
+	base := len(rankAlphabet)
+	var out []byte
+
+	for i := 0; ; i++ {
+		lo := 0
+		if i < len(prev) {
+			lo = strings.IndexByte(rankAlphabet, prev[i])
+		}
+
+		hi := base
+		if next != "" && i < len(next) {
+			hi = strings.IndexByte(rankAlphabet, next[i])
+		}
+
+		switch {
+		case lo == hi:
+			// Still inside the common prefix.
+			out = append(out, rankAlphabet[lo])
+		case hi-lo > 1:
+			mid := (lo + hi) / 2
+			if hi == base && i < len(prev) {
+				// Appending to the bottom of a column is the common case;
+				// stepping by one instead of halving keeps ranks short.
+				mid = lo + 1
+			}
+			out = append(out, rankAlphabet[mid])
+			return string(out)
+		default:
+			// Adjacent digits: keep prev's digit and keep going with no
+			// upper bound, since anything longer already sorts below next.
+			out = append(out, rankAlphabet[lo])
+			next = ""
+		}
+	}
+}
+
+// EvenRanks returns n ascending ranks spread evenly over the rank space. It
+// is used to (re)rank a whole column at once, e.g. tasks created before
+// ranks existed.
+func EvenRanks(n int) []string {
+	base := len(rankAlphabet)
+
+	width, space := 1, base
+	for space <= n+1 {
+		width++
+		space *= base
+	}
+
+	ranks := make([]string, n)
+	step := space / (n + 1)
+
+	for i := range ranks {
+		value := (i + 1) * step
+
+		digits := make([]byte, width)
+		for d := width - 1; d >= 0; d-- {
+			digits[d] = rankAlphabet[value%base]
+			value /= base
+		}
+
+		// A trailing 'i' keeps ranks from ending in '0', see RankBetween.
+		ranks[i] = string(digits) + "i"
+	}
+
+	return ranks
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func checkRank(t *testing.T, prev, next, rank string) {
+	t.Helper()
+
+	if rank <= prev || (next != "" && rank >= next) {
+		t.Fatalf("RankBetween(%q, %q) = %q, not strictly between", prev, next, rank)
+	}
+	if strings.HasSuffix(rank, "0") {
+		t.Fatalf("RankBetween(%q, %q) = %q, ends in '0'", prev, next, rank)
+	}
+}
+
+func TestRankBetween(t *testing.T) {
+	cases := []struct {
+		prev, next string
+	}{
+		{"", ""},
+		{"", "i"},
+		{"i", ""},
+		{"a", "b"},
+		{"a", "c"},
+		{"z", ""},
+		{"", "01"},
+		{"a", "a1"},
+		{"ai", "b"},
+		{"zzz", ""},
+		{"0i", "0j"},
+	}
+
+	for _, c := range cases {
+		checkRank(t, c.prev, c.next, RankBetween(c.prev, c.next))
+	}
+}
+
+func TestRankBetweenAppendStaysShort(t *testing.T) {
+	rank := RankBetween("", "")
+	for i := 0; i < 100; i++ {
+		next := RankBetween(rank, "")
+		checkRank(t, rank, "", next)
+		rank = next
+	}
+
+	// Stepping by one digit adds a character only every 17 appends or so,
+	// where halving would add one every few.
+	if len(rank) > 1+100/17 {
+		t.Fatalf("100 appends produced a rank of length %d: %q", len(rank), rank)
+	}
+}
+
+// Random drops anywhere in a column must keep producing ranks that fit.
+func TestRankBetweenRandomInserts(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	ranks := []string{}
+
+	for i := 0; i < 2000; i++ {
+		pos := rng.Intn(len(ranks) + 1)
+
+		prev, next := "", ""
+		if pos > 0 {
+			prev = ranks[pos-1]
+		}
+		if pos < len(ranks) {
+			next = ranks[pos]
+		}
+
+		rank := RankBetween(prev, next)
+		checkRank(t, prev, next, rank)
+
+		ranks = append(ranks, "")
+		copy(ranks[pos+1:], ranks[pos:])
+		ranks[pos] = rank
+	}
+
+	if !sort.StringsAreSorted(ranks) {
+		t.Fatal("ranks are out of order")
+	}
+}
+
+func TestEvenRanks(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 34, 35, 36, 1000} {
+		ranks := EvenRanks(n)
+
+		if len(ranks) != n {
+			t.Fatalf("EvenRanks(%d) returned %d ranks", n, len(ranks))
+		}
+
+		for i, rank := range ranks {
+			if strings.HasSuffix(rank, "0") {
+				t.Fatalf("EvenRanks(%d)[%d] = %q ends in '0'", n, i, rank)
+			}
+			if i > 0 && ranks[i-1] >= rank {
+				t.Fatalf("EvenRanks(%d) is not ascending at %d: %q >= %q", n, i, ranks[i-1], rank)
+			}
+		}
+
+		// Every gap, and both ends, must leave room for a drop.
+		for i := 0; i <= n; i++ {
+			prev, next := "", ""
+			if i > 0 {
+				prev = ranks[i-1]
+			}
+			if i < n {
+				next = ranks[i]
+			}
+			checkRank(t, prev, next, RankBetween(prev, next))
+		}
+	}
+}
@@ -32,3 +32,16 @@ func SendError(w http.ResponseWriter, statusCode int, message string) {
 
 	json.NewEncoder(w).Encode(resp)
 }
+
+func SendErrorWithData(w http.ResponseWriter, statusCode int, message string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	resp := dto.APIResponse{
+		Status:      "fail",
+		Description: message,
+		Data:        data,
+	}
+
+	json.NewEncoder(w).Encode(resp)
+}